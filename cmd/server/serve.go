@@ -1,24 +1,30 @@
 package server
 
 import (
+	"context"
 	"database/sql"
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
 	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
 	_ "github.com/lib/pq"
 
 	"expense-tracker/internal/handlers"
+	"expense-tracker/internal/logging"
 	"expense-tracker/internal/models"
 	"expense-tracker/internal/repository"
 	"expense-tracker/internal/service"
 )
 
 func Serve() {
+	logging.Setup(os.Stdout, os.Getenv("LOG_LEVEL"), os.Getenv("LOG_FORMAT"))
+
 	dbConnStr := os.Getenv("DATABASE_URL")
 	if dbConnStr == "" {
 		dbConnStr = "host=localhost port=5432 user=admin password=root dbname=expense_tracker sslmode=disable"
@@ -34,69 +40,160 @@ func Serve() {
 				break
 			}
 		}
-		log.Printf("Attempt %d: Failed to connect to database. Retrying in 2s...", i+1)
+		slog.Warn("failed to connect to database, retrying in 2s", "attempt", i+1, "error", err)
 		time.Sleep(2 * time.Second)
 	}
 
 	if err != nil {
-		log.Fatal("Failed to connect to database after retries:", err)
+		slog.Error("failed to connect to database after retries", "error", err)
+		os.Exit(1)
 	}
 	defer db.Close()
 
-	log.Println("✓ Connected to database!")
+	slog.Info("connected to database")
 
 	if err := runMigrations(db); err != nil {
-		log.Printf("Warning: Failed to run migrations: %v", err)
+		slog.Warn("failed to run migrations", "error", err)
+	}
+
+	settingsRepo := repository.NewSettingsRepository(db)
+	fiscal, err := loadFiscalCalendar(context.Background(), settingsRepo)
+	if err != nil {
+		slog.Error("invalid fiscal year configuration", "error", err)
+		os.Exit(1)
 	}
+	slog.Info("fiscal calendar loaded", "start_month", fiscal.StartMonth, "current_year", fiscal.Label(fiscal.CurrentYear()))
 
-	budgetRepo := repository.NewBudgetRepository(db)
-	expenseRepo := repository.NewExpenseRepository(db)
+	budgetRepo := repository.NewBudgetRepository(db, fiscal)
+	expenseRepo := repository.NewExpenseRepository(db, fiscal)
 	categoryRepo := repository.NewCategoryRepository(db)
 	userRepo := repository.NewUserRepository(db)
+	exchangeRateRepo := repository.NewExchangeRateRepository(db)
+	budgetEntryRepo := repository.NewBudgetEntryRepository(db, fiscal)
+	notificationRepo := repository.NewNotificationRepository(db)
+	categorizationRuleRepo := repository.NewCategorizationRuleRepository(db)
+	tagRepo := repository.NewTagRepository(db)
+	vendorRepo := repository.NewVendorRepository(db)
+	payoutRepo := repository.NewPayoutRepository(db)
+	statementRepo := repository.NewStatementRepository(db)
+	taxRateRepo := repository.NewTaxRateRepository(db)
+	costCenterRepo := repository.NewCostCenterRepository(db, fiscal)
+	departmentRepo := repository.NewDepartmentRepository(db, fiscal)
 
 	emailService := service.NewEmailService()
 	userService := service.NewUserService(userRepo, emailService)
 	authService := service.NewAuthService(userRepo)
 
 	categoryService := service.NewCategoryService(categoryRepo)
-	if err := categoryService.InitializeDefaults(); err != nil {
-		log.Printf("Warning: Failed to initialize default categories: %v", err)
+	if err := categoryService.InitializeDefaults(context.Background()); err != nil {
+		slog.Warn("failed to initialize default categories", "error", err)
 	} else {
-		log.Println("✓ Default categories initialized")
+		slog.Info("default categories initialized")
 	}
 
-	budgetService := service.NewBudgetService(budgetRepo, expenseRepo)
-	expenseService := service.NewExpenseService(expenseRepo, budgetRepo)
-
-	budgetHandler := handlers.NewBudgetHandler(budgetService)
-	expenseHandler := handlers.NewExpenseHandler(expenseService)
+	metrics := handlers.NewMetrics()
+
+	notificationService := service.NewNotificationService(notificationRepo, userRepo, emailService)
+	budgetService := service.NewBudgetService(budgetRepo, expenseRepo, budgetEntryRepo, notificationService, metrics, fiscal)
+	forecastService := service.NewForecastService(expenseRepo, fiscal)
+	currencyService := service.NewCurrencyService(exchangeRateRepo, settingsRepo)
+	categorizationService := service.NewCategorizationService(categorizationRuleRepo, currencyService)
+	costCenterService := service.NewCostCenterService(costCenterRepo, fiscal)
+	departmentService := service.NewDepartmentService(departmentRepo, userRepo, fiscal)
+	reportService := service.NewReportService(budgetRepo, expenseRepo, departmentService, fiscal)
+	vendorService := service.NewVendorService(vendorRepo)
+	taxService := service.NewTaxService(taxRateRepo, currencyService)
+	expenseService := service.NewExpenseService(expenseRepo, budgetService, currencyService, categorizationService, costCenterService, departmentService, vendorService, taxService)
+	tagService := service.NewTagService(tagRepo)
+	payoutService := service.NewPayoutService(payoutRepo, settingsRepo, currencyService, departmentService)
+	statementService := service.NewStatementService(statementRepo, expenseService, currencyService)
+
+	budgetHandler := handlers.NewBudgetHandler(budgetService, forecastService, metrics)
+	expenseHandler := handlers.NewExpenseHandler(expenseService, metrics)
 	categoryHandler := handlers.NewCategoryHandler(categoryService)
-	templateHandler := handlers.NewTemplateHandler("web/templates", categoryRepo, budgetRepo, expenseRepo)
-	authHandler := handlers.NewAuthHandler(authService)
+	currencyHandler := handlers.NewCurrencyHandler(currencyService)
+	notificationHandler := handlers.NewNotificationHandler(notificationService)
+	reportHandler := handlers.NewReportHandler(reportService)
+	categorizationHandler := handlers.NewCategorizationHandler(categorizationService)
+	tagHandler := handlers.NewTagHandler(tagService)
+	costCenterHandler := handlers.NewCostCenterHandler(costCenterService)
+	departmentHandler := handlers.NewDepartmentHandler(departmentService)
+	vendorHandler := handlers.NewVendorHandler(vendorService)
+	payoutHandler := handlers.NewPayoutHandler(payoutService)
+	statementHandler := handlers.NewStatementHandler(statementService)
+	taxHandler := handlers.NewTaxHandler(taxService)
+	templateHandler := handlers.NewTemplateHandler("web/templates", categoryRepo, budgetRepo, expenseRepo, costCenterRepo, fiscal)
+	authHandler := handlers.NewAuthHandler(authService, metrics)
 	userHandler := handlers.NewUserHandler(userService)
 	adminHandler := handlers.NewAdminHandler(db)
+	healthHandler := handlers.NewHealthHandler(db, "migrations", metrics, authService)
 	authMiddleware := handlers.NewAuthMiddleware(authService)
 
-	setupRoutes(categoryHandler, budgetHandler, expenseHandler, templateHandler, authHandler, userHandler, adminHandler, authMiddleware)
+	setupRoutes(categoryHandler, budgetHandler, expenseHandler, currencyHandler, notificationHandler, reportHandler, categorizationHandler, tagHandler, costCenterHandler, departmentHandler, vendorHandler, payoutHandler, statementHandler, taxHandler, templateHandler, authHandler, userHandler, adminHandler, healthHandler, authMiddleware)
 
 	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	log.Printf("🚀 Server running on http://localhost:%s", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	slog.Info("server running", "addr", "http://localhost:"+port)
+	requestTimeout := 30 * time.Second
+	if v, err := time.ParseDuration(os.Getenv("REQUEST_TIMEOUT")); err == nil && v > 0 {
+		requestTimeout = v
+	}
+
+	handler := handlers.RequestTimeout(requestTimeout, handlers.RequestLogger(metrics.Instrument(http.DefaultServeMux)))
+	if err := http.ListenAndServe(":"+port, handler); err != nil {
+		slog.Error("server stopped", "error", err)
+		os.Exit(1)
+	}
+}
+
+// loadFiscalCalendar reads the fiscal year start month from app_settings.
+// FISCAL_YEAR_START_MONTH, when set, takes precedence and is persisted.
+func loadFiscalCalendar(ctx context.Context, settings repository.SettingsRepository) (models.FiscalCalendar, error) {
+	if v := os.Getenv("FISCAL_YEAR_START_MONTH"); v != "" {
+		month, err := strconv.Atoi(v)
+		if err != nil {
+			return models.FiscalCalendar{}, fmt.Errorf("FISCAL_YEAR_START_MONTH must be a number: %w", err)
+		}
+		fiscal, err := models.NewFiscalCalendar(month)
+		if err != nil {
+			return models.FiscalCalendar{}, err
+		}
+		if err := settings.Set(ctx, "fiscal_year_start_month", v); err != nil {
+			slog.Warn("failed to persist fiscal year start month", "error", err)
+		}
+		return fiscal, nil
+	}
+
+	value, ok, err := settings.Get(ctx, "fiscal_year_start_month")
+	if err != nil || !ok {
+		if err != nil {
+			slog.Warn("failed to read fiscal year start month, using calendar years", "error", err)
+		}
+		return models.NewFiscalCalendar(1)
+	}
+	month, err := strconv.Atoi(value)
+	if err != nil {
+		return models.FiscalCalendar{}, fmt.Errorf("fiscal_year_start_month setting %q is not a number", value)
+	}
+	return models.NewFiscalCalendar(month)
 }
 
 func runMigrations(db *sql.DB) error {
 	var tableExists bool
 	err := db.QueryRow("SELECT EXISTS (SELECT FROM information_schema.tables WHERE table_name = 'users')").Scan(&tableExists)
 	if err != nil {
-		log.Printf("Warning: Could not check for users table: %v", err)
+		slog.Warn("could not check for users table", "error", err)
 	}
 
 	if !tableExists {
-		log.Println("⚠️  Users table not found - running ALL migrations...")
+		slog.Warn("users table not found, running all migrations")
+	}
+
+	if _, err := db.Exec(handlers.SchemaMigrationsTableSQL); err != nil {
+		return err
 	}
 
 	migrationDir := "migrations"
@@ -118,50 +215,78 @@ func runMigrations(db *sql.DB) error {
 		filePath := filepath.Join(migrationDir, filename)
 		content, err := os.ReadFile(filePath)
 		if err != nil {
-			log.Printf("Warning: Failed to read %s: %v", filename, err)
+			slog.Warn("failed to read migration", "file", filename, "error", err)
 			continue
 		}
 
-		log.Printf("Running migration: %s", filename)
+		slog.Debug("running migration", "file", filename)
 		_, err = db.Exec(string(content))
 		if err != nil {
 			errMsg := err.Error()
 			if strings.Contains(errMsg, "already exists") ||
 				strings.Contains(errMsg, "duplicate") ||
 				strings.Contains(errMsg, "ON CONFLICT") {
-				log.Printf("  ✓ %s (already applied)", filename)
+				slog.Debug("migration already applied", "file", filename)
+				recordMigration(db, filename)
 				successCount++
 			} else {
-				log.Printf("  ✗ Failed: %v", err)
+				slog.Error("migration failed", "file", filename, "error", err)
 			}
 		} else {
-			log.Printf("  ✓ %s", filename)
+			slog.Info("migration applied", "file", filename)
+			recordMigration(db, filename)
 			successCount++
 		}
 	}
 
 	if successCount > 0 {
-		log.Printf("✓ Successfully applied %d migrations", successCount)
+		slog.Info("migrations complete", "applied", successCount)
 	}
 
 	return nil
 }
 
+func recordMigration(db *sql.DB, filename string) {
+	_, err := db.Exec("INSERT INTO schema_migrations (filename) VALUES ($1) ON CONFLICT (filename) DO NOTHING", filename)
+	if err != nil {
+		slog.Warn("failed to record migration", "file", filename, "error", err)
+	}
+}
+
 func setupRoutes(
 	categoryHandler *handlers.CategoryHandler,
 	budgetHandler *handlers.BudgetHandler,
 	expenseHandler *handlers.ExpenseHandler,
+	currencyHandler *handlers.CurrencyHandler,
+	notificationHandler *handlers.NotificationHandler,
+	reportHandler *handlers.ReportHandler,
+	categorizationHandler *handlers.CategorizationHandler,
+	tagHandler *handlers.TagHandler,
+	costCenterHandler *handlers.CostCenterHandler,
+	departmentHandler *handlers.DepartmentHandler,
+	vendorHandler *handlers.VendorHandler,
+	payoutHandler *handlers.PayoutHandler,
+	statementHandler *handlers.StatementHandler,
+	taxHandler *handlers.TaxHandler,
 	templateHandler *handlers.TemplateHandler,
 	authHandler *handlers.AuthHandler,
 	userHandler *handlers.UserHandler,
 	adminHandler *handlers.AdminHandler,
+	healthHandler *handlers.HealthHandler,
 	authMiddleware *handlers.AuthMiddleware,
 ) {
+	http.HandleFunc("/healthz", healthHandler.Healthz)
+	http.HandleFunc("/readyz", healthHandler.Readyz)
+	http.HandleFunc("/metrics", healthHandler.Metrics)
+
 	http.HandleFunc("/", authMiddleware.Authenticate(templateHandler.RenderHome))
 	http.HandleFunc("/categories", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(templateHandler.RenderCategoriesPage))
 	http.HandleFunc("/budgets", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(templateHandler.RenderBudgetsPage))
 	http.HandleFunc("/expenses", authMiddleware.RequireAuth(templateHandler.RenderExpensesPage))
 	http.HandleFunc("/monitoring", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(templateHandler.RenderMonitoringPage))
+	http.HandleFunc("/payouts", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(templateHandler.RenderPayoutsPage))
+	http.HandleFunc("/statements", authMiddleware.RequireRole(models.RoleAdmin)(templateHandler.RenderStatementsPage))
+	http.HandleFunc("/tax", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(templateHandler.RenderTaxPage))
 	http.HandleFunc("/users", authMiddleware.RequireRole(models.RoleAdmin)(templateHandler.RenderUsersPage))
 	http.HandleFunc("/login", authMiddleware.Authenticate(templateHandler.RenderLoginPage))
 	http.HandleFunc("/set-password", authMiddleware.Authenticate(templateHandler.RenderSetPasswordPage))
@@ -173,13 +298,22 @@ func setupRoutes(
 	http.HandleFunc("/api/users", authMiddleware.RequireRole(models.RoleAdmin)(userHandler.ListUsers))
 	http.HandleFunc("/api/users/create", authMiddleware.RequireRole(models.RoleAdmin)(userHandler.CreateUser))
 	http.HandleFunc("/api/users/update-role", authMiddleware.RequireRole(models.RoleAdmin)(userHandler.UpdateUserRole))
+	http.HandleFunc("/api/users/update-department", authMiddleware.RequireRole(models.RoleAdmin)(departmentHandler.UpdateUserDepartment))
+	http.HandleFunc("/api/users/update-bank-account", authMiddleware.RequireRole(models.RoleAdmin)(payoutHandler.UpdateBankAccount))
 	http.HandleFunc("/admin/run-migrations", adminHandler.RunMigrations)
 
 	http.HandleFunc("/api/categories", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(categoryHandler.HandleCategories))
 	http.HandleFunc("/api/categories/", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(categoryHandler.HandleCategoryByID))
 
 	http.HandleFunc("/api/budgets", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(budgetHandler.HandleBudgets))
+	http.HandleFunc("/api/fiscal-year", authMiddleware.Authenticate(budgetHandler.GetFiscalYear))
+	http.HandleFunc("/api/budgets/savings-target", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(budgetHandler.SetSavingsTarget))
+	http.HandleFunc("/api/budgets/roll-forward", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(budgetHandler.RollForward))
+	http.HandleFunc("/api/budgets/transfers", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(budgetHandler.Transfer))
 	http.HandleFunc("/api/budgets/status", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(budgetHandler.GetBudgetStatus))
+	http.HandleFunc("/api/reports/variance", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(reportHandler.GetVariance))
+	http.HandleFunc("/api/reports/variance/expenses", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(reportHandler.GetVarianceExpenses))
+	http.HandleFunc("/api/reports/vat", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(taxHandler.GetVATReport))
 	http.HandleFunc("/api/monitoring", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(budgetHandler.HandleMonitoring))
 
 	http.HandleFunc("/api/budgets/", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(func(w http.ResponseWriter, r *http.Request) {
@@ -187,11 +321,63 @@ func setupRoutes(
 			budgetHandler.ToggleCircuitBreaker(w, r)
 			return
 		}
+		if strings.HasSuffix(r.URL.Path, "/policy") {
+			budgetHandler.UpdatePolicy(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/override") {
+			budgetHandler.SetOverride(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/alerts") {
+			budgetHandler.UpdateAlerts(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/lock-events") {
+			budgetHandler.GetLockEvents(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/history") {
+			budgetHandler.GetHistory(w, r)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/allocations") {
+			budgetHandler.AddSupplementary(w, r)
+			return
+		}
 		http.NotFound(w, r)
 	}))
 
+	http.HandleFunc("/api/cost-centers", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(costCenterHandler.HandleCostCenters))
+	http.HandleFunc("/api/cost-centers/", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(costCenterHandler.HandleCostCenterPath))
+
+	http.HandleFunc("/api/departments", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(departmentHandler.HandleDepartments))
+	http.HandleFunc("/api/departments/", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(departmentHandler.HandleDepartmentPath))
+
+	http.HandleFunc("/api/payouts", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(payoutHandler.HandlePayouts))
+	http.HandleFunc("/api/payouts/", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(payoutHandler.HandlePayoutPath))
+
+	http.HandleFunc("/api/statements", authMiddleware.RequireRole(models.RoleAdmin)(statementHandler.GetStatements))
+	http.HandleFunc("/api/statements/", authMiddleware.RequireRole(models.RoleAdmin)(statementHandler.HandleStatementPath))
+
+	http.HandleFunc("/api/tax-rates", authMiddleware.RequireAuth(taxHandler.HandleTaxRates))
+	http.HandleFunc("/api/tax-rates/", authMiddleware.RequireAuth(taxHandler.HandleTaxRateByID))
+
 	http.HandleFunc("/api/expenses", authMiddleware.Authenticate(expenseHandler.HandleExpenses))
-	http.HandleFunc("/api/expenses/", authMiddleware.Authenticate(expenseHandler.HandleExpenseByID))
+	http.HandleFunc("/api/expenses/", authMiddleware.RequireAuth(expenseHandler.HandleExpenseByID))
+
+	http.HandleFunc("/api/exchange-rates", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(currencyHandler.HandleRates))
+	http.HandleFunc("/api/categorization/rules", authMiddleware.RequireRole(models.RoleAdmin)(categorizationHandler.HandleRules))
+	http.HandleFunc("/api/categorization/rules/", authMiddleware.RequireRole(models.RoleAdmin)(categorizationHandler.HandleRuleByID))
+	http.HandleFunc("/api/categorization/suggest", authMiddleware.RequireAuth(categorizationHandler.Suggest))
+	http.HandleFunc("/api/categorization/overrides", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(categorizationHandler.GetOverrideReport))
+	http.HandleFunc("/api/tags", authMiddleware.RequireAuth(tagHandler.GetTags))
+	http.HandleFunc("/api/vendors", authMiddleware.RequireAuth(vendorHandler.HandleVendors))
+	http.HandleFunc("/api/vendors/", authMiddleware.RequireAuth(vendorHandler.HandleVendorPath))
+
+	http.HandleFunc("/api/notifications", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(notificationHandler.GetNotifications))
+	http.HandleFunc("/api/notifications/", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(notificationHandler.HandleNotificationByID))
+	http.HandleFunc("/api/exchange-rates/import", authMiddleware.RequireRole(models.RoleAdmin, models.RoleManagement)(currencyHandler.ImportRates))
 
 	fs := http.FileServer(http.Dir("web/static"))
 	http.Handle("/static/", http.StripPrefix("/static/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -199,14 +385,5 @@ func setupRoutes(
 		fs.ServeHTTP(w, r)
 	})))
 
-	log.Println("✓ Routes configured:")
-	log.Println("  - GET  /                      (Home page)")
-	log.Println("  - GET  /categories            (Categories page)")
-	log.Println("  - GET  /budgets               (Budgets page)")
-	log.Println("  - GET  /expenses              (Expenses page)")
-	log.Println("  - GET  /monitoring            (Monitoring page)")
-	log.Println("  - API  /api/categories        (Full CRUD)")
-	log.Println("  - API  /api/budgets           (Set & Get)")
-	log.Println("  - API  /api/expenses          (Filter & Record)")
-	log.Println("  - GET  /static/*              (Static files)")
+	slog.Info("routes configured")
 }
@@ -10,6 +10,11 @@ import (
 	"strings"
 )
 
+const SchemaMigrationsTableSQL = `CREATE TABLE IF NOT EXISTS schema_migrations (
+	filename VARCHAR(255) PRIMARY KEY,
+	applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+)`
+
 type AdminHandler struct {
 	db *sql.DB
 }
@@ -50,6 +55,11 @@ func (h *AdminHandler) RunMigrations(w http.ResponseWriter, r *http.Request) {
 	}
 	sort.Strings(sqlFiles)
 
+	if _, err := h.db.Exec(SchemaMigrationsTableSQL); err != nil {
+		h.sendErrorResponse(w, "Failed to prepare schema_migrations table", err.Error())
+		return
+	}
+
 	results := []map[string]interface{}{}
 
 	for _, filename := range sqlFiles {
@@ -72,6 +82,7 @@ func (h *AdminHandler) RunMigrations(w http.ResponseWriter, r *http.Request) {
 				"message": err.Error(),
 			})
 		} else {
+			h.db.Exec("INSERT INTO schema_migrations (filename) VALUES ($1) ON CONFLICT (filename) DO NOTHING", filename)
 			results = append(results, map[string]interface{}{
 				"file":    filename,
 				"status":  "success",
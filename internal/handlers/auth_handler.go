@@ -8,10 +8,11 @@ import (
 
 type AuthHandler struct {
 	authService service.AuthService
+	metrics     *Metrics
 }
 
-func NewAuthHandler(authService service.AuthService) *AuthHandler {
-	return &AuthHandler{authService: authService}
+func NewAuthHandler(authService service.AuthService, metrics *Metrics) *AuthHandler {
+	return &AuthHandler{authService: authService, metrics: metrics}
 }
 
 func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
@@ -30,8 +31,9 @@ func (h *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, token, err := h.authService.Login(req.Email, req.Password)
+	user, token, err := h.authService.Login(r.Context(), req.Email, req.Password)
 	if err != nil {
+		h.metrics.IncLoginFailures()
 		h.sendErrorResponse(w, "Authentication failed", err.Error(), http.StatusUnauthorized)
 		return
 	}
@@ -62,7 +64,7 @@ func (h *AuthHandler) SetPassword(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.authService.SetPassword(req.Token, req.Password); err != nil {
+	if err := h.authService.SetPassword(r.Context(), req.Token, req.Password); err != nil {
 		h.sendErrorResponse(w, "Failed to set password", err.Error(), http.StatusBadRequest)
 		return
 	}
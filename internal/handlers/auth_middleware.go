@@ -2,6 +2,7 @@ package handlers
 
 import (
 	"context"
+	"expense-tracker/internal/logging"
 	"expense-tracker/internal/models"
 	"expense-tracker/internal/service"
 	"net/http"
@@ -28,6 +29,7 @@ func (m *AuthMiddleware) Authenticate(next http.HandlerFunc) http.HandlerFunc {
 		if err == nil {
 			user, authenticated := m.authService.IsAuthenticated(cookie.Value)
 			if authenticated {
+				logging.SetUserID(r.Context(), user.ID)
 				ctx := context.WithValue(r.Context(), UserContextKey, user)
 				next.ServeHTTP(w, r.WithContext(ctx))
 				return
@@ -51,6 +53,7 @@ func (m *AuthMiddleware) RequireAuth(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
+		logging.SetUserID(r.Context(), user.ID)
 		ctx := context.WithValue(r.Context(), UserContextKey, user)
 		next.ServeHTTP(w, r.WithContext(ctx))
 	}
@@ -7,14 +7,17 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type BudgetHandler struct {
-	service *service.BudgetService
+	service  *service.BudgetService
+	forecast *service.ForecastService
+	metrics  *Metrics
 }
 
-func NewBudgetHandler(service *service.BudgetService) *BudgetHandler {
-	return &BudgetHandler{service: service}
+func NewBudgetHandler(service *service.BudgetService, forecast *service.ForecastService, metrics *Metrics) *BudgetHandler {
+	return &BudgetHandler{service: service, forecast: forecast, metrics: metrics}
 }
 
 func (h *BudgetHandler) HandleBudgets(w http.ResponseWriter, r *http.Request) {
@@ -32,16 +35,16 @@ func (h *BudgetHandler) GetBudgets(w http.ResponseWriter, r *http.Request) {
 	yearStr := r.URL.Query().Get("year")
 	year, err := strconv.Atoi(yearStr)
 	if err != nil {
-		year = 2026
+		year = h.service.FiscalCalendar().CurrentYear()
 	}
 
-	budgets, err := h.service.GetAll(year)
+	budgets, err := h.service.GetAll(r.Context(), year)
 	if err != nil {
 		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	summary, err := h.service.GetDashboardSummary(year)
+	summary, err := h.service.GetDashboardSummary(r.Context(), year)
 	if err != nil {
 		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		return
@@ -65,13 +68,29 @@ func (h *BudgetHandler) GetBudgetStatus(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if dateStr := r.URL.Query().Get("date"); dateStr != "" {
+		date, err := time.Parse("2006-01-02", dateStr)
+		if err != nil {
+			h.sendErrorResponse(w, "Invalid date", "Date must be in YYYY-MM-DD format", http.StatusBadRequest)
+			return
+		}
+
+		status, err := h.service.GetPeriodStatus(r.Context(), categoryID, date)
+		if err != nil {
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.sendSuccessResponse(w, status, "", http.StatusOK)
+		return
+	}
+
 	year, err := strconv.Atoi(yearStr)
 	if err != nil {
 		h.sendErrorResponse(w, "Invalid year", "Year is required", http.StatusBadRequest)
 		return
 	}
 
-	status, err := h.service.GetStatus(categoryID, year)
+	status, err := h.service.GetStatus(r.Context(), categoryID, year)
 	if err != nil {
 		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		return
@@ -84,18 +103,66 @@ func (h *BudgetHandler) HandleMonitoring(w http.ResponseWriter, r *http.Request)
 	yearStr := r.URL.Query().Get("year")
 	year, err := strconv.Atoi(yearStr)
 	if err != nil {
-		year = 2026
+		year = h.service.FiscalCalendar().CurrentYear()
 	}
 
-	stats, err := h.service.GetMonitoringData(year)
+	fiscal := h.service.FiscalCalendar()
+	asOf := time.Now()
+	if fiscal.YearOf(asOf) != year {
+		_, asOf = fiscal.Bounds(year)
+	}
+	if month, err := strconv.Atoi(r.URL.Query().Get("month")); err == nil && month >= 1 && month <= 12 {
+		asOf = fiscal.MonthStart(year, fiscal.MonthOf(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)))
+	}
+
+	stats, err := h.service.GetMonitoringData(r.Context(), year, asOf)
 	if err != nil {
 		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	if err := h.forecast.Apply(r.Context(), year, asOf, stats); err != nil {
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
 	h.sendSuccessResponse(w, stats, "", http.StatusOK)
 }
 
+func (h *BudgetHandler) SetSavingsTarget(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.sendErrorResponse(w, "Method not allowed", "Only PUT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.SavingsTargetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	summary, err := h.service.SetSavingsTarget(r.Context(), req)
+	if err != nil {
+		if strings.Contains(err.Error(), "must be") {
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, summary, "Savings target updated", http.StatusOK)
+}
+
+func (h *BudgetHandler) GetFiscalYear(w http.ResponseWriter, r *http.Request) {
+	fiscal := h.service.FiscalCalendar()
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		year = fiscal.CurrentYear()
+	}
+	h.sendSuccessResponse(w, fiscal.Info(year), "", http.StatusOK)
+}
+
 func (h *BudgetHandler) ToggleCircuitBreaker(w http.ResponseWriter, r *http.Request) {
 	pathParts := strings.Split(r.URL.Path, "/")
 	if len(pathParts) < 4 {
@@ -117,13 +184,14 @@ func (h *BudgetHandler) ToggleCircuitBreaker(w http.ResponseWriter, r *http.Requ
 		return
 	}
 
-	if err := h.service.ToggleLock(id, req.IsLocked); err != nil {
+	if err := h.service.ToggleLock(r.Context(), id, req.IsLocked, GetAuthenticatedUser(r)); err != nil {
 		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		return
 	}
 
 	status := "unlocked"
 	if req.IsLocked {
+		h.metrics.IncBudgetLocks()
 		status = "locked"
 	}
 	h.sendSuccessResponse(w, nil, "Circuit breaker "+status, http.StatusOK)
@@ -141,7 +209,11 @@ func (h *BudgetHandler) SetBudget(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	budget, err := h.service.CreateOrUpdate(req.CategoryID, req.Amount, req.Year)
+	if user := GetAuthenticatedUser(r); user != nil {
+		req.ChangedBy = user.ID
+	}
+
+	budget, err := h.service.CreateOrUpdate(r.Context(), req)
 	if err != nil {
 		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		return
@@ -150,6 +222,258 @@ func (h *BudgetHandler) SetBudget(w http.ResponseWriter, r *http.Request) {
 	h.sendSuccessResponse(w, budget, "Budget set successfully", http.StatusOK)
 }
 
+func (h *BudgetHandler) GetHistory(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := h.budgetIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	history, err := h.service.GetHistory(r.Context(), id)
+	if err != nil {
+		if err.Error() == "budget not found" {
+			h.sendErrorResponse(w, "Not found", err.Error(), http.StatusNotFound)
+			return
+		}
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, history, "", http.StatusOK)
+}
+
+func (h *BudgetHandler) AddSupplementary(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := h.budgetIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.BudgetAllocationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	entry, err := h.service.AddSupplementary(r.Context(), id, req, GetAuthenticatedUser(r))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "not found"):
+			h.sendErrorResponse(w, "Not found", err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "required"):
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, entry, "Supplementary allocation added", http.StatusCreated)
+}
+
+func (h *BudgetHandler) Transfer(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.BudgetTransferRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	transfer, err := h.service.Transfer(r.Context(), req, GetAuthenticatedUser(r))
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "no budget for"):
+			h.sendErrorResponse(w, "Not found", err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "below its spent amount") || strings.Contains(err.Error(), "below its minimum") || strings.Contains(err.Error(), "negative monthly allocation"):
+			h.sendErrorResponse(w, "Transfer rejected", err.Error(), http.StatusConflict)
+		case strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "required"):
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, transfer, "Budget transferred", http.StatusCreated)
+}
+
+func (h *BudgetHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.sendErrorResponse(w, "Method not allowed", "Only PUT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := h.budgetIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var policy models.BudgetPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.service.UpdatePolicy(r.Context(), id, policy)
+	if err != nil {
+		switch {
+		case err.Error() == "budget not found":
+			h.sendErrorResponse(w, "Not found", err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "must be"):
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, budget, "Budget policy updated", http.StatusOK)
+}
+
+func (h *BudgetHandler) SetOverride(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := h.budgetIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.BudgetOverrideRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.SetOverride(r.Context(), id, req, GetAuthenticatedUser(r)); err != nil {
+		switch {
+		case err.Error() == "budget not found":
+			h.sendErrorResponse(w, "Not found", err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "must be") || strings.Contains(err.Error(), "required"):
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	message := "Override cleared"
+	if req.Until != "" {
+		message = "Circuit breaker overridden until " + req.Until
+	}
+	h.sendSuccessResponse(w, nil, message, http.StatusOK)
+}
+
+func (h *BudgetHandler) UpdateAlerts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		h.sendErrorResponse(w, "Method not allowed", "Only PUT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := h.budgetIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	var req models.BudgetAlertRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.service.UpdateAlertThresholds(r.Context(), id, req)
+	if err != nil {
+		switch {
+		case err.Error() == "budget not found":
+			h.sendErrorResponse(w, "Not found", err.Error(), http.StatusNotFound)
+		case strings.Contains(err.Error(), "must be"):
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, budget, "Alert thresholds updated", http.StatusOK)
+}
+
+func (h *BudgetHandler) GetLockEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id, ok := h.budgetIDFromPath(w, r)
+	if !ok {
+		return
+	}
+
+	events, err := h.service.GetLockEvents(r.Context(), id)
+	if err != nil {
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, events, "", http.StatusOK)
+}
+
+func (h *BudgetHandler) RollForward(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.RollForwardRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	result, err := h.service.RollForward(r.Context(), req, GetAuthenticatedUser(r))
+	if err != nil {
+		if strings.Contains(err.Error(), "must be") {
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	message := "Budgets rolled forward"
+	if result.Preview {
+		message = "Roll-forward preview"
+	}
+	h.sendSuccessResponse(w, result, message, http.StatusOK)
+}
+
+func (h *BudgetHandler) budgetIDFromPath(w http.ResponseWriter, r *http.Request) (int, bool) {
+	pathParts := strings.Split(r.URL.Path, "/")
+	if len(pathParts) < 4 {
+		h.sendErrorResponse(w, "Invalid URL", "Budget ID missing", http.StatusBadRequest)
+		return 0, false
+	}
+	id, err := strconv.Atoi(pathParts[3])
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Budget ID must be a number", http.StatusBadRequest)
+		return 0, false
+	}
+	return id, true
+}
+
 func (h *BudgetHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
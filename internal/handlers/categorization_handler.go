@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/service"
+)
+
+type CategorizationHandler struct {
+	service *service.CategorizationService
+}
+
+func NewCategorizationHandler(service *service.CategorizationService) *CategorizationHandler {
+	return &CategorizationHandler{service: service}
+}
+
+func (h *CategorizationHandler) HandleRules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		rules, err := h.service.GetRules(r.Context())
+		if err != nil {
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.sendSuccessResponse(w, rules, "", http.StatusOK)
+	case http.MethodPost:
+		var req models.CategorizationRuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		rule, err := h.service.CreateRule(r.Context(), req)
+		if err != nil {
+			h.sendRuleError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, rule, "Rule created successfully", http.StatusCreated)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CategorizationHandler) HandleRuleByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/api/categorization/rules/"))
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Rule ID must be a valid number", http.StatusBadRequest)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPut:
+		var req models.CategorizationRuleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		rule, err := h.service.UpdateRule(r.Context(), id, req)
+		if err != nil {
+			h.sendRuleError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, rule, "Rule updated successfully", http.StatusOK)
+	case http.MethodDelete:
+		if err := h.service.DeleteRule(r.Context(), id); err != nil {
+			h.sendRuleError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, nil, "Rule deleted successfully", http.StatusOK)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Supported: PUT, DELETE", http.StatusMethodNotAllowed)
+	}
+}
+
+// Suggest serves the expense form: given remarks and the amount as entered
+// (with its currency and date) it returns the suggested category, or null
+// data when no rule matches.
+func (h *CategorizationHandler) Suggest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := GetAuthenticatedUser(r)
+	if user == nil {
+		h.sendErrorResponse(w, "Unauthorized", "Authentication required", http.StatusUnauthorized)
+		return
+	}
+
+	var req models.CategorySuggestionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+	req.UserID = user.ID
+
+	suggestion, err := h.service.SuggestEntered(r.Context(), req)
+	if err != nil {
+		h.sendRuleError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, suggestion, "", http.StatusOK)
+}
+
+func (h *CategorizationHandler) GetOverrideReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	report, err := h.service.GetOverrideReport(r.Context(), query.Get("start_date"), query.Get("end_date"))
+	if err != nil {
+		h.sendRuleError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, report, "", http.StatusOK)
+}
+
+func (h *CategorizationHandler) sendRuleError(w http.ResponseWriter, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+	case strings.Contains(msg, "must") || strings.Contains(msg, "required"):
+		h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+	default:
+		h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+	}
+}
+
+func (h *CategorizationHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *CategorizationHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
@@ -42,10 +42,23 @@ func (h *CategoryHandler) HandleCategories(w http.ResponseWriter, r *http.Reques
 
 func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Request) {
 	path := strings.TrimPrefix(r.URL.Path, "/api/categories/")
+	if path == "tree" {
+		h.GetCategoryTree(w, r)
+		return
+	}
 	if path == "" {
 		h.sendErrorResponse(w, "Invalid request", "Category ID is required", http.StatusBadRequest)
 		return
 	}
+	if strings.HasSuffix(path, "/merge") {
+		id, err := strconv.Atoi(strings.TrimSuffix(path, "/merge"))
+		if err != nil {
+			h.sendErrorResponse(w, "Invalid ID", "Category ID must be a valid number", http.StatusBadRequest)
+			return
+		}
+		h.MergeCategory(w, r, id)
+		return
+	}
 
 	id, err := strconv.Atoi(path)
 	if err != nil {
@@ -60,15 +73,17 @@ func (h *CategoryHandler) HandleCategoryByID(w http.ResponseWriter, r *http.Requ
 		h.UpdateCategory(w, r, id)
 	case http.MethodPatch:
 		h.ToggleCategoryStatus(w, r, id)
+	case http.MethodDelete:
+		h.DeleteCategory(w, r, id)
 	default:
-		h.sendErrorResponse(w, "Method not allowed", "Supported: GET, PUT, PATCH", http.StatusMethodNotAllowed)
+		h.sendErrorResponse(w, "Method not allowed", "Supported: GET, PUT, PATCH, DELETE", http.StatusMethodNotAllowed)
 	}
 }
 
 func (h *CategoryHandler) GetAllCategories(w http.ResponseWriter, r *http.Request) {
 	activeOnly := r.URL.Query().Get("active_only") == "true"
 
-	categories, err := h.service.GetAll(activeOnly)
+	categories, err := h.service.GetAll(r.Context(), activeOnly)
 	if err != nil {
 		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		return
@@ -77,8 +92,24 @@ func (h *CategoryHandler) GetAllCategories(w http.ResponseWriter, r *http.Reques
 	h.sendSuccessResponse(w, categories, "", http.StatusOK)
 }
 
+func (h *CategoryHandler) GetCategoryTree(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	activeOnly := r.URL.Query().Get("active_only") == "true"
+	tree, err := h.service.GetTree(r.Context(), activeOnly)
+	if err != nil {
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, tree, "", http.StatusOK)
+}
+
 func (h *CategoryHandler) GetCategoryByID(w http.ResponseWriter, r *http.Request, id int) {
-	category, err := h.service.GetByID(id)
+	category, err := h.service.GetByID(r.Context(), id)
 	if err != nil {
 		if err.Error() == "category not found" {
 			h.sendErrorResponse(w, "Not found", "Category not found", http.StatusNotFound)
@@ -109,10 +140,12 @@ func (h *CategoryHandler) CreateCategory(w http.ResponseWriter, r *http.Request)
 		isActive = *req.IsActive
 	}
 
-	category, err := h.service.Create(req.Name, isActive)
+	category, err := h.service.Create(r.Context(), req.Name, isActive, req.ParentID)
 	if err != nil {
 		if err.Error() == "category with this name already exists" {
 			h.sendErrorResponse(w, "Duplicate category", err.Error(), http.StatusConflict)
+		} else if isParentError(err) {
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
 		} else {
 			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		}
@@ -134,10 +167,12 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request,
 		isActive = *req.IsActive
 	}
 
-	category, err := h.service.Update(id, req.Name, isActive)
+	category, err := h.service.Update(r.Context(), id, req.Name, isActive, req.ParentID)
 	if err != nil {
 		if err.Error() == "category not found" {
 			h.sendErrorResponse(w, "Not found", "Category not found", http.StatusNotFound)
+		} else if isParentError(err) {
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
 		} else {
 			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
 		}
@@ -148,7 +183,7 @@ func (h *CategoryHandler) UpdateCategory(w http.ResponseWriter, r *http.Request,
 }
 
 func (h *CategoryHandler) ToggleCategoryStatus(w http.ResponseWriter, r *http.Request, id int) {
-	category, err := h.service.ToggleStatus(id)
+	category, err := h.service.ToggleStatus(r.Context(), id)
 	if err != nil {
 		if err.Error() == "category not found" {
 			h.sendErrorResponse(w, "Not found", "Category not found", http.StatusNotFound)
@@ -161,6 +196,57 @@ func (h *CategoryHandler) ToggleCategoryStatus(w http.ResponseWriter, r *http.Re
 	h.sendSuccessResponse(w, category, "Status toggled successfully", http.StatusOK)
 }
 
+func (h *CategoryHandler) MergeCategory(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.CategoryMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+
+	merge, err := h.service.Merge(r.Context(), id, req, GetAuthenticatedUser(r))
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case strings.Contains(msg, "not found"):
+			h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+		case strings.Contains(msg, "must be") || strings.Contains(msg, "subcategories"):
+			h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, merge, "Category merged successfully", http.StatusOK)
+}
+
+func (h *CategoryHandler) DeleteCategory(w http.ResponseWriter, r *http.Request, id int) {
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		msg := err.Error()
+		switch {
+		case msg == "category not found":
+			h.sendErrorResponse(w, "Not found", "Category not found", http.StatusNotFound)
+		case strings.Contains(msg, "in use"):
+			h.sendErrorResponse(w, "Category in use", msg, http.StatusConflict)
+		default:
+			h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, nil, "Category deleted successfully", http.StatusOK)
+}
+
+func isParentError(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "parent") || strings.Contains(msg, "subcategories")
+}
+
 func (h *CategoryHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -0,0 +1,229 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/service"
+)
+
+type CostCenterHandler struct {
+	service *service.CostCenterService
+}
+
+func NewCostCenterHandler(service *service.CostCenterService) *CostCenterHandler {
+	return &CostCenterHandler{service: service}
+}
+
+func (h *CostCenterHandler) HandleCostCenters(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		costCenters, err := h.service.GetAll(r.Context(), r.URL.Query().Get("active_only") == "true")
+		if err != nil {
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.sendSuccessResponse(w, costCenters, "", http.StatusOK)
+	case http.MethodPost:
+		var req models.CostCenterRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		costCenter, err := h.service.Create(r.Context(), req)
+		if err != nil {
+			h.sendCostCenterError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, costCenter, "Cost center created successfully", http.StatusCreated)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleCostCenterPath routes /api/cost-centers/{id}, /budgets,
+// /budgets/{id}/lock, /budgets/{id}/policy and /monitoring.
+func (h *CostCenterHandler) HandleCostCenterPath(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/cost-centers/"), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "monitoring":
+		h.GetMonitoring(w, r)
+	case len(parts) == 1 && parts[0] == "budgets":
+		h.HandleBudgets(w, r)
+	case len(parts) == 3 && parts[0] == "budgets" && parts[2] == "lock":
+		h.ToggleLock(w, r, parts[1])
+	case len(parts) == 3 && parts[0] == "budgets" && parts[2] == "policy":
+		h.UpdatePolicy(w, r, parts[1])
+	case len(parts) == 1:
+		h.UpdateCostCenter(w, r, parts[0])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *CostCenterHandler) UpdateCostCenter(w http.ResponseWriter, r *http.Request, idStr string) {
+	if r.Method != http.MethodPut {
+		h.sendErrorResponse(w, "Method not allowed", "Only PUT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Cost center ID must be a valid number", http.StatusBadRequest)
+		return
+	}
+
+	var req models.CostCenterRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+	costCenter, err := h.service.Update(r.Context(), id, req)
+	if err != nil {
+		h.sendCostCenterError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, costCenter, "Cost center updated successfully", http.StatusOK)
+}
+
+func (h *CostCenterHandler) HandleBudgets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		budgets, err := h.service.GetBudgets(r.Context(), h.yearParam(r))
+		if err != nil {
+			h.sendCostCenterError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, budgets, "", http.StatusOK)
+	case http.MethodPost:
+		var req models.CostCenterBudgetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+			return
+		}
+		budget, err := h.service.SetBudget(r.Context(), req)
+		if err != nil {
+			h.sendCostCenterError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, budget, "Cost center budget saved successfully", http.StatusOK)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CostCenterHandler) ToggleLock(w http.ResponseWriter, r *http.Request, idStr string) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Budget ID must be a number", http.StatusBadRequest)
+		return
+	}
+
+	var req struct {
+		IsLocked bool `json:"is_locked"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.ToggleLock(r.Context(), id, req.IsLocked, GetAuthenticatedUser(r)); err != nil {
+		h.sendCostCenterError(w, err)
+		return
+	}
+
+	status := "unlocked"
+	if req.IsLocked {
+		status = "locked"
+	}
+	h.sendSuccessResponse(w, nil, "Circuit breaker "+status, http.StatusOK)
+}
+
+func (h *CostCenterHandler) UpdatePolicy(w http.ResponseWriter, r *http.Request, idStr string) {
+	if r.Method != http.MethodPut {
+		h.sendErrorResponse(w, "Method not allowed", "Only PUT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Budget ID must be a number", http.StatusBadRequest)
+		return
+	}
+
+	var policy models.BudgetPolicy
+	if err := json.NewDecoder(r.Body).Decode(&policy); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	budget, err := h.service.UpdatePolicy(r.Context(), id, policy)
+	if err != nil {
+		h.sendCostCenterError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, budget, "Budget policy updated", http.StatusOK)
+}
+
+func (h *CostCenterHandler) GetMonitoring(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	year := h.yearParam(r)
+	fiscal := h.service.FiscalCalendar()
+	asOf := time.Now()
+	if fiscal.YearOf(asOf) != year {
+		_, asOf = fiscal.Bounds(year)
+	}
+
+	items, err := h.service.GetMonitoringData(r.Context(), year, asOf)
+	if err != nil {
+		h.sendCostCenterError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, items, "", http.StatusOK)
+}
+
+// yearParam returns the year query parameter, defaulting to the current
+// fiscal year.
+func (h *CostCenterHandler) yearParam(r *http.Request) int {
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		return h.service.FiscalCalendar().CurrentYear()
+	}
+	return year
+}
+
+func (h *CostCenterHandler) sendCostCenterError(w http.ResponseWriter, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+	case strings.Contains(msg, "already exists"):
+		h.sendErrorResponse(w, "Conflict", msg, http.StatusConflict)
+	case strings.Contains(msg, "must") || strings.Contains(msg, "required"):
+		h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+	default:
+		h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+	}
+}
+
+func (h *CostCenterHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *CostCenterHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
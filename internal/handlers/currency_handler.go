@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/service"
+)
+
+const maxImportSize = 10 << 20
+
+type CurrencyHandler struct {
+	service *service.CurrencyService
+}
+
+func NewCurrencyHandler(service *service.CurrencyService) *CurrencyHandler {
+	return &CurrencyHandler{service: service}
+}
+
+func (h *CurrencyHandler) HandleRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		h.GetRates(w, r)
+	case http.MethodPost:
+		h.AddRate(w, r)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *CurrencyHandler) GetRates(w http.ResponseWriter, r *http.Request) {
+	base, err := h.service.BaseCurrency(r.Context())
+	if err != nil {
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	rates, err := h.service.GetRates(r.Context(), r.URL.Query().Get("currency"))
+	if err != nil {
+		h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	data := map[string]interface{}{
+		"base_currency": base,
+		"rates":         rates,
+	}
+	h.sendSuccessResponse(w, data, "", http.StatusOK)
+}
+
+func (h *CurrencyHandler) AddRate(w http.ResponseWriter, r *http.Request) {
+	var req models.ExchangeRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	rate, err := h.service.AddRate(r.Context(), req)
+	if err != nil {
+		h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.sendSuccessResponse(w, rate, "Exchange rate saved", http.StatusCreated)
+}
+
+func (h *CurrencyHandler) ImportRates(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, _, err := importBody(w, r)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid upload", err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	var result *models.ExchangeRateImportResult
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "csv", "":
+		result, err = h.service.ImportCSV(r.Context(), body)
+	case "ecb", "xml":
+		result, err = h.service.ImportECB(r.Context(), body)
+	default:
+		h.sendErrorResponse(w, "Validation error", "format must be csv or ecb", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		h.sendErrorResponse(w, "Import failed", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h.sendSuccessResponse(w, result, "Exchange rates imported", http.StatusOK)
+}
+
+// importBody returns the uploaded file of a multipart form ("file" field) or
+// else the raw request body, with the file name when there is one.
+func importBody(w http.ResponseWriter, r *http.Request) (io.ReadCloser, string, error) {
+	r.Body = http.MaxBytesReader(w, r.Body, maxImportSize)
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		file, header, err := r.FormFile("file")
+		if err != nil {
+			return nil, "", err
+		}
+		return file, header.Filename, nil
+	}
+	return r.Body, "", nil
+}
+
+func (h *CurrencyHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *CurrencyHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/service"
+)
+
+type DepartmentHandler struct {
+	service *service.DepartmentService
+}
+
+func NewDepartmentHandler(service *service.DepartmentService) *DepartmentHandler {
+	return &DepartmentHandler{service: service}
+}
+
+// HandleDepartments lists departments for management and admins; only admins
+// create them.
+func (h *DepartmentHandler) HandleDepartments(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		departments, err := h.service.GetAll(r.Context())
+		if err != nil {
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.sendSuccessResponse(w, departments, "", http.StatusOK)
+	case http.MethodPost:
+		if !h.requireAdmin(w, r) {
+			return
+		}
+		var req models.DepartmentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		department, err := h.service.Create(r.Context(), req)
+		if err != nil {
+			h.sendDepartmentError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, department, "Department created successfully", http.StatusCreated)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleDepartmentPath routes /api/departments/{id} and /budgets.
+func (h *DepartmentHandler) HandleDepartmentPath(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/departments/"), "/")
+	if path == "budgets" {
+		h.HandleBudgets(w, r)
+		return
+	}
+
+	id, err := strconv.Atoi(path)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Department ID must be a valid number", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodPut {
+		h.sendErrorResponse(w, "Method not allowed", "Only PUT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	var req models.DepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+	department, err := h.service.Update(r.Context(), id, req)
+	if err != nil {
+		h.sendDepartmentError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, department, "Department updated successfully", http.StatusOK)
+}
+
+// HandleBudgets returns department budgets (management only sees their own
+// departments) and lets admins set them.
+func (h *DepartmentHandler) HandleBudgets(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		year, err := strconv.Atoi(r.URL.Query().Get("year"))
+		if err != nil {
+			year = h.service.FiscalCalendar().CurrentYear()
+		}
+		budgets, err := h.service.GetBudgets(r.Context(), year, GetAuthenticatedUser(r))
+		if err != nil {
+			h.sendDepartmentError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, budgets, "", http.StatusOK)
+	case http.MethodPost:
+		if !h.requireAdmin(w, r) {
+			return
+		}
+		var req models.DepartmentBudgetRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := h.service.SetBudget(r.Context(), req); err != nil {
+			h.sendDepartmentError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, nil, "Department budget saved successfully", http.StatusOK)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *DepartmentHandler) UpdateUserDepartment(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.UserDepartmentRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err := h.service.SetUserDepartment(r.Context(), req); err != nil {
+		h.sendDepartmentError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, nil, "User department updated", http.StatusOK)
+}
+
+func (h *DepartmentHandler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if user := GetAuthenticatedUser(r); user == nil || !user.IsAdmin() {
+		h.sendErrorResponse(w, "Forbidden", "Only admins can change departments", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (h *DepartmentHandler) sendDepartmentError(w http.ResponseWriter, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+	case strings.Contains(msg, "already exists"):
+		h.sendErrorResponse(w, "Conflict", msg, http.StatusConflict)
+	case strings.Contains(msg, "must") || strings.Contains(msg, "required"):
+		h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+	default:
+		h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+	}
+}
+
+func (h *DepartmentHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *DepartmentHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
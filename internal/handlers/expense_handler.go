@@ -2,7 +2,9 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
 	"expense-tracker/internal/service"
 	"net/http"
 	"strconv"
@@ -11,10 +13,11 @@ import (
 
 type ExpenseHandler struct {
 	service *service.ExpenseService
+	metrics *Metrics
 }
 
-func NewExpenseHandler(service *service.ExpenseService) *ExpenseHandler {
-	return &ExpenseHandler{service: service}
+func NewExpenseHandler(service *service.ExpenseService, metrics *Metrics) *ExpenseHandler {
+	return &ExpenseHandler{service: service, metrics: metrics}
 }
 
 func (h *ExpenseHandler) HandleExpenses(w http.ResponseWriter, r *http.Request) {
@@ -33,20 +36,34 @@ func (h *ExpenseHandler) HandleExpenses(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *ExpenseHandler) HandleExpenseByID(w http.ResponseWriter, r *http.Request) {
+	path := strings.TrimPrefix(r.URL.Path, "/api/expenses/")
+	if strings.HasSuffix(path, "/approve") || strings.HasSuffix(path, "/reject") {
+		h.DecideApproval(w, r, path)
+		return
+	}
 	if r.Method != http.MethodDelete {
 		w.WriteHeader(http.StatusMethodNotAllowed)
 		return
 	}
 
-	path := strings.TrimPrefix(r.URL.Path, "/api/expenses/")
 	id, err := strconv.Atoi(path)
 	if err != nil {
 		h.sendErrorResponse(w, "Invalid ID", "Expense ID must be a number", http.StatusBadRequest)
 		return
 	}
 
-	if err := h.service.Delete(id); err != nil {
-		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+	if err := h.service.Delete(r.Context(), id, GetAuthenticatedUser(r)); err != nil {
+		msg := err.Error()
+		switch {
+		case errors.Is(err, repository.ErrExpenseInPayout):
+			h.sendErrorResponse(w, "Conflict", msg, http.StatusConflict)
+		case strings.Contains(msg, "not found"):
+			h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+		case strings.Contains(msg, "must be"):
+			h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+		}
 		return
 	}
 
@@ -57,8 +74,11 @@ func (h *ExpenseHandler) GetExpenses(w http.ResponseWriter, r *http.Request) {
 	user := GetAuthenticatedUser(r)
 	query := r.URL.Query()
 	catID, _ := strconv.Atoi(query.Get("category_id"))
-	minAmount, _ := strconv.ParseFloat(query.Get("min_amount"), 64)
-	maxAmount, _ := strconv.ParseFloat(query.Get("max_amount"), 64)
+	costCenterID, _ := strconv.Atoi(query.Get("cost_center_id"))
+	departmentID, _ := strconv.Atoi(query.Get("department_id"))
+	vendorID, _ := strconv.Atoi(query.Get("vendor_id"))
+	minAmount, _ := models.ParseMoney(query.Get("min_amount"))
+	maxAmount, _ := models.ParseMoney(query.Get("max_amount"))
 
 	filter := models.ExpenseFilter{
 		StartDate:  query.Get("start_date"),
@@ -67,9 +87,18 @@ func (h *ExpenseHandler) GetExpenses(w http.ResponseWriter, r *http.Request) {
 		SearchText: query.Get("search"),
 		MinAmount:  minAmount,
 		MaxAmount:  maxAmount,
+		Tags:       parseTagsParam(query.Get("tags")),
+		TagMatch:   models.TagMatch(query.Get("tag_match")),
+
+		CostCenterID:   costCenterID,
+		DepartmentID:   departmentID,
+		ApprovalStatus: models.ApprovalStatus(query.Get("status")),
+		VendorID:       vendorID,
+		PaymentMethod:  models.PaymentMethod(query.Get("payment_method")),
+		Reconciliation: models.ReconciliationStatus(query.Get("reconciliation")),
 	}
 
-	expenses, err := h.service.GetAll(filter, user)
+	expenses, err := h.service.GetAll(r.Context(), filter, user)
 	if err != nil {
 		h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
 		return
@@ -82,14 +111,26 @@ func (h *ExpenseHandler) GetInsights(w http.ResponseWriter, r *http.Request) {
 	user := GetAuthenticatedUser(r)
 	query := r.URL.Query()
 	catID, _ := strconv.Atoi(query.Get("category_id"))
+	costCenterID, _ := strconv.Atoi(query.Get("cost_center_id"))
+	departmentID, _ := strconv.Atoi(query.Get("department_id"))
+	vendorID, _ := strconv.Atoi(query.Get("vendor_id"))
 
 	filter := models.ExpenseFilter{
 		StartDate:  query.Get("start_date"),
 		EndDate:    query.Get("end_date"),
 		CategoryID: catID,
+		Tags:       parseTagsParam(query.Get("tags")),
+		TagMatch:   models.TagMatch(query.Get("tag_match")),
+
+		CostCenterID:   costCenterID,
+		DepartmentID:   departmentID,
+		ApprovalStatus: models.ApprovalStatus(query.Get("status")),
+		VendorID:       vendorID,
+		PaymentMethod:  models.PaymentMethod(query.Get("payment_method")),
+		Reconciliation: models.ReconciliationStatus(query.Get("reconciliation")),
 	}
 
-	insights, err := h.service.GetInsights(filter, user)
+	insights, err := h.service.GetInsights(r.Context(), filter, user)
 	if err != nil {
 		h.sendErrorResponse(w, "Error fetching insights", err.Error(), http.StatusInternalServerError)
 		return
@@ -106,9 +147,9 @@ func (h *ExpenseHandler) CreateExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	expense, err := h.service.Create(req, user)
+	expense, err := h.service.Create(r.Context(), req, user)
 	if err != nil {
-		if err.Error() == "spending is temporarily locked for this category" {
+		if isCircuitBreakerError(err) {
 			h.sendErrorResponse(w, "Circuit Breaker Active", err.Error(), http.StatusForbidden)
 		} else {
 			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
@@ -116,9 +157,68 @@ func (h *ExpenseHandler) CreateExpense(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.metrics.IncExpensesCreated()
 	h.sendSuccessResponse(w, expense, "Expense recorded successfully", http.StatusCreated)
 }
 
+// DecideApproval serves POST /api/expenses/{id}/approve and /reject.
+func (h *ExpenseHandler) DecideApproval(w http.ResponseWriter, r *http.Request, path string) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	idStr, action, _ := strings.Cut(path, "/")
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Expense ID must be a number", http.StatusBadRequest)
+		return
+	}
+
+	var req models.ExpenseApprovalRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	user := GetAuthenticatedUser(r)
+	message := "Expense approved"
+	if action == "reject" {
+		err = h.service.Reject(r.Context(), id, req, user)
+		message = "Expense rejected"
+	} else {
+		err = h.service.Approve(r.Context(), id, req, user)
+	}
+	if err != nil {
+		msg := err.Error()
+		switch {
+		case strings.HasPrefix(msg, "only "):
+			h.sendErrorResponse(w, "Forbidden", msg, http.StatusForbidden)
+		case strings.Contains(msg, "not found"):
+			h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+		case strings.Contains(msg, "no longer pending"):
+			h.sendErrorResponse(w, "Conflict", msg, http.StatusConflict)
+		case strings.Contains(msg, "must be") || strings.Contains(msg, "required"):
+			h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+		default:
+			h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+		}
+		return
+	}
+
+	h.sendSuccessResponse(w, nil, message, http.StatusOK)
+}
+
+// parseTagsParam splits a comma-separated tags query parameter.
+func parseTagsParam(value string) []string {
+	if value == "" {
+		return nil
+	}
+	return strings.Split(value, ",")
+}
+
 func (h *ExpenseHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
@@ -130,3 +230,10 @@ func (h *ExpenseHandler) sendSuccessResponse(w http.ResponseWriter, data interfa
 	w.WriteHeader(statusCode)
 	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
 }
+
+// isCircuitBreakerError reports whether a budget or cost center refused a new
+// expense.
+func isCircuitBreakerError(err error) bool {
+	return errors.Is(err, repository.ErrBudgetLocked) || errors.Is(err, repository.ErrOverBudget) ||
+		errors.Is(err, repository.ErrCostCenterLocked) || errors.Is(err, repository.ErrCostCenterOverBudget)
+}
@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"expense-tracker/internal/service"
+)
+
+type HealthHandler struct {
+	db            *sql.DB
+	migrationsDir string
+	metrics       *Metrics
+	authService   service.AuthService
+}
+
+func NewHealthHandler(db *sql.DB, migrationsDir string, metrics *Metrics, authService service.AuthService) *HealthHandler {
+	return &HealthHandler{
+		db:            db,
+		migrationsDir: migrationsDir,
+		metrics:       metrics,
+		authService:   authService,
+	}
+}
+
+func (h *HealthHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	h.sendStatus(w, map[string]interface{}{"status": "ok"}, http.StatusOK)
+}
+
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), 2*time.Second)
+	defer cancel()
+
+	checks := map[string]string{}
+	ready := true
+
+	if err := h.db.PingContext(ctx); err != nil {
+		checks["database"] = err.Error()
+		ready = false
+	} else {
+		checks["database"] = "ok"
+	}
+
+	pending, err := h.pendingMigrations(ctx)
+	switch {
+	case err != nil:
+		checks["migrations"] = err.Error()
+		ready = false
+	case len(pending) > 0:
+		checks["migrations"] = "pending: " + strings.Join(pending, ", ")
+		ready = false
+	default:
+		checks["migrations"] = "ok"
+	}
+
+	status := "ready"
+	statusCode := http.StatusOK
+	if !ready {
+		status = "not ready"
+		statusCode = http.StatusServiceUnavailable
+	}
+
+	h.sendStatus(w, map[string]interface{}{"status": status, "checks": checks}, statusCode)
+}
+
+func (h *HealthHandler) Metrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	h.metrics.Write(w)
+
+	stats := h.db.Stats()
+	writeGauge(w, "db_pool_open_connections", "Open connections to the database.", int64(stats.OpenConnections))
+	writeGauge(w, "db_pool_in_use_connections", "Connections currently in use.", int64(stats.InUse))
+	writeGauge(w, "db_pool_idle_connections", "Idle connections in the pool.", int64(stats.Idle))
+	writeCounter(w, "db_pool_wait_count_total", "Connections waited for.", uint64(stats.WaitCount))
+	writeCounter(w, "db_pool_wait_duration_seconds_total", "Total time spent waiting for a connection, in whole seconds.", uint64(stats.WaitDuration.Seconds()))
+
+	writeGauge(w, "active_sessions", "Logged-in sessions held in the session store.", int64(h.authService.ActiveSessions()))
+}
+
+func (h *HealthHandler) pendingMigrations(ctx context.Context) ([]string, error) {
+	files, err := os.ReadDir(h.migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := h.db.QueryContext(ctx, "SELECT filename FROM schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[string]bool{}
+	for rows.Next() {
+		var filename string
+		if err := rows.Scan(&filename); err != nil {
+			return nil, err
+		}
+		applied[filename] = true
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	pending := []string{}
+	for _, file := range files {
+		if strings.HasSuffix(file.Name(), ".sql") && !applied[file.Name()] {
+			pending = append(pending, file.Name())
+		}
+	}
+	return pending, nil
+}
+
+func (h *HealthHandler) sendStatus(w http.ResponseWriter, data interface{}, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(data)
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+type routeKey struct {
+	method string
+	route  string
+	status int
+}
+
+type routeLatency struct {
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+type Metrics struct {
+	mu              sync.Mutex
+	requests        map[routeKey]uint64
+	latency         map[string]*routeLatency
+	loginFailures   uint64
+	expensesCreated uint64
+	budgetLocks     uint64
+}
+
+func NewMetrics() *Metrics {
+	return &Metrics{
+		requests: make(map[routeKey]uint64),
+		latency:  make(map[string]*routeLatency),
+	}
+}
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (m *Metrics) Instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, r)
+
+		route := r.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+		m.observeRequest(r.Method, route, rec.status, time.Since(start))
+	})
+}
+
+func (m *Metrics) observeRequest(method, route string, status int, duration time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.requests[routeKey{method: method, route: route, status: status}]++
+
+	l, ok := m.latency[route]
+	if !ok {
+		l = &routeLatency{buckets: make([]uint64, len(latencyBuckets))}
+		m.latency[route] = l
+	}
+	seconds := duration.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			l.buckets[i]++
+		}
+	}
+	l.sum += seconds
+	l.count++
+}
+
+func (m *Metrics) IncLoginFailures() {
+	m.mu.Lock()
+	m.loginFailures++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) IncExpensesCreated() {
+	m.mu.Lock()
+	m.expensesCreated++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) IncBudgetLocks() {
+	m.mu.Lock()
+	m.budgetLocks++
+	m.mu.Unlock()
+}
+
+func (m *Metrics) Write(w io.Writer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	keys := make([]routeKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		if keys[i].method != keys[j].method {
+			return keys[i].method < keys[j].method
+		}
+		return keys[i].status < keys[j].status
+	})
+
+	fmt.Fprintln(w, "# HELP http_requests_total Total HTTP requests by method, route and status.")
+	fmt.Fprintln(w, "# TYPE http_requests_total counter")
+	for _, k := range keys {
+		fmt.Fprintf(w, "http_requests_total{method=%q,route=%q,status=\"%d\"} %d\n", k.method, k.route, k.status, m.requests[k])
+	}
+
+	routes := make([]string, 0, len(m.latency))
+	for route := range m.latency {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	fmt.Fprintln(w, "# HELP http_request_duration_seconds HTTP request latency by route.")
+	fmt.Fprintln(w, "# TYPE http_request_duration_seconds histogram")
+	for _, route := range routes {
+		l := m.latency[route]
+		for i, bound := range latencyBuckets {
+			fmt.Fprintf(w, "http_request_duration_seconds_bucket{route=%q,le=%q} %d\n", route, strconv.FormatFloat(bound, 'f', -1, 64), l.buckets[i])
+		}
+		fmt.Fprintf(w, "http_request_duration_seconds_bucket{route=%q,le=\"+Inf\"} %d\n", route, l.count)
+		fmt.Fprintf(w, "http_request_duration_seconds_sum{route=%q} %g\n", route, l.sum)
+		fmt.Fprintf(w, "http_request_duration_seconds_count{route=%q} %d\n", route, l.count)
+	}
+
+	writeCounter(w, "login_failures_total", "Failed login attempts.", m.loginFailures)
+	writeCounter(w, "expenses_created_total", "Expenses recorded through the API.", m.expensesCreated)
+	writeCounter(w, "budget_locks_triggered_total", "Circuit breaker locks applied to budgets.", m.budgetLocks)
+}
+
+func writeCounter(w io.Writer, name, help string, value uint64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s counter\n", name)
+	fmt.Fprintf(w, "%s %d\n", name, value)
+}
+
+func writeGauge(w io.Writer, name, help string, value int64) {
+	fmt.Fprintf(w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(w, "# TYPE %s gauge\n", name)
+	fmt.Fprintf(w, "%s %d\n", name, value)
+}
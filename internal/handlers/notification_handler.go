@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"expense-tracker/internal/service"
+)
+
+type NotificationHandler struct {
+	service *service.NotificationService
+}
+
+func NewNotificationHandler(service *service.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+func (h *NotificationHandler) GetNotifications(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	unreadOnly := r.URL.Query().Get("unread") == "true"
+	feed, err := h.service.GetFeed(r.Context(), GetAuthenticatedUser(r), unreadOnly)
+	if err != nil {
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, feed, "", http.StatusOK)
+}
+
+// HandleNotificationByID serves POST /api/notifications/{id}/read and
+// POST /api/notifications/read-all.
+func (h *NotificationHandler) HandleNotificationByID(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	user := GetAuthenticatedUser(r)
+	path := strings.TrimPrefix(r.URL.Path, "/api/notifications/")
+	if path == "read-all" {
+		if err := h.service.MarkAllRead(r.Context(), user); err != nil {
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.sendSuccessResponse(w, nil, "All notifications marked as read", http.StatusOK)
+		return
+	}
+
+	idStr, ok := strings.CutSuffix(path, "/read")
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Notification ID must be a number", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.service.MarkRead(r.Context(), id, user); err != nil {
+		if err.Error() == "notification not found" {
+			h.sendErrorResponse(w, "Not found", err.Error(), http.StatusNotFound)
+			return
+		}
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, nil, "Notification marked as read", http.StatusOK)
+}
+
+func (h *NotificationHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *NotificationHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/service"
+)
+
+type PayoutHandler struct {
+	service *service.PayoutService
+}
+
+func NewPayoutHandler(service *service.PayoutService) *PayoutHandler {
+	return &PayoutHandler{service: service}
+}
+
+// HandlePayouts lists payout batches (?status=open|paid) and creates batches
+// from approved reimbursable expenses.
+func (h *PayoutHandler) HandlePayouts(w http.ResponseWriter, r *http.Request) {
+	user := GetAuthenticatedUser(r)
+	switch r.Method {
+	case http.MethodGet:
+		batches, err := h.service.GetAll(r.Context(), models.PayoutStatus(r.URL.Query().Get("status")), user)
+		if err != nil {
+			h.sendPayoutError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, batches, "", http.StatusOK)
+	case http.MethodPost:
+		var req models.PayoutBatchRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		batches, err := h.service.CreateBatches(r.Context(), req, user)
+		if err != nil {
+			h.sendPayoutError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, batches, fmt.Sprintf("%d payout batches created", len(batches)), http.StatusCreated)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandlePayoutPath routes /api/payouts/pending, /export, /settings, /{id}
+// and /{id}/paid.
+func (h *PayoutHandler) HandlePayoutPath(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/payouts/"), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "pending":
+		h.GetPending(w, r)
+	case len(parts) == 1 && parts[0] == "export":
+		h.Export(w, r)
+	case len(parts) == 1 && parts[0] == "settings":
+		h.HandleSettings(w, r)
+	case len(parts) == 2 && parts[1] == "paid":
+		h.MarkPaid(w, r, parts[0])
+	case len(parts) == 1:
+		h.HandleBatch(w, r, parts[0])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *PayoutHandler) GetPending(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	pending, err := h.service.GetPending(r.Context(), GetAuthenticatedUser(r))
+	if err != nil {
+		h.sendPayoutError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, pending, "", http.StatusOK)
+}
+
+// HandleBatch returns a batch with its expenses (GET) or cancels an open
+// batch (DELETE).
+func (h *PayoutHandler) HandleBatch(w http.ResponseWriter, r *http.Request, idStr string) {
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Payout batch ID must be a number", http.StatusBadRequest)
+		return
+	}
+	user := GetAuthenticatedUser(r)
+
+	switch r.Method {
+	case http.MethodGet:
+		batch, err := h.service.GetByID(r.Context(), id, user)
+		if err != nil {
+			h.sendPayoutError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, batch, "", http.StatusOK)
+	case http.MethodDelete:
+		if err := h.service.Cancel(r.Context(), id, user); err != nil {
+			h.sendPayoutError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, nil, "Payout batch cancelled", http.StatusOK)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and DELETE methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *PayoutHandler) MarkPaid(w http.ResponseWriter, r *http.Request, idStr string) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Payout batch ID must be a number", http.StatusBadRequest)
+		return
+	}
+
+	var req models.PayoutPaidRequest
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	batch, err := h.service.MarkPaid(r.Context(), id, req, GetAuthenticatedUser(r))
+	if err != nil {
+		h.sendPayoutError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, batch, "Payout batch marked as paid", http.StatusOK)
+}
+
+// Export serves the payout file: GET /api/payouts/export?format=csv|pain001
+// with optional ids=1,2,3 (default: all open batches).
+func (h *PayoutHandler) Export(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var ids []int
+	for _, v := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if v = strings.TrimSpace(v); v == "" {
+			continue
+		}
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			h.sendErrorResponse(w, "Validation error", "ids must be a comma-separated list of numbers", http.StatusBadRequest)
+			return
+		}
+		ids = append(ids, id)
+	}
+
+	format := strings.ToLower(r.URL.Query().Get("format"))
+	if format != "" && format != "csv" && format != "pain001" {
+		h.sendErrorResponse(w, "Validation error", "format must be csv or pain001", http.StatusBadRequest)
+		return
+	}
+
+	batches, err := h.service.ExportBatches(r.Context(), ids, GetAuthenticatedUser(r))
+	if err != nil {
+		h.sendPayoutError(w, err)
+		return
+	}
+
+	now := time.Now()
+	if format == "pain001" {
+		// Render first so validation errors can still be sent as JSON.
+		var buf strings.Builder
+		if err := h.service.WritePain001(r.Context(), &buf, batches, now); err != nil {
+			h.sendPayoutError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=payouts-%s.xml", now.Format("20060102-150405")))
+		fmt.Fprint(w, buf.String())
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=payouts-%s.csv", now.Format("20060102-150405")))
+	if err := h.service.WriteCSV(w, batches); err != nil {
+		h.sendErrorResponse(w, "Export failed", err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// HandleSettings shows the company account payouts are made from; only
+// admins change it.
+func (h *PayoutHandler) HandleSettings(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		settings, err := h.service.GetSettings(r.Context())
+		if err != nil {
+			h.sendPayoutError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, settings, "", http.StatusOK)
+	case http.MethodPut:
+		if user := GetAuthenticatedUser(r); user == nil || !user.IsAdmin() {
+			h.sendErrorResponse(w, "Forbidden", "Only admins can change payout settings", http.StatusForbidden)
+			return
+		}
+		var req models.PayoutSettings
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		settings, err := h.service.UpdateSettings(r.Context(), req)
+		if err != nil {
+			h.sendPayoutError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, settings, "Payout settings saved", http.StatusOK)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and PUT methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// UpdateBankAccount sets the account a user's reimbursements are paid to.
+func (h *PayoutHandler) UpdateBankAccount(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.BankAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.SetBankAccount(r.Context(), req); err != nil {
+		h.sendPayoutError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, nil, "Bank account saved", http.StatusOK)
+}
+
+func (h *PayoutHandler) sendPayoutError(w http.ResponseWriter, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found") || strings.HasPrefix(msg, "no payout batches"):
+		h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+	case strings.Contains(msg, "already paid"):
+		h.sendErrorResponse(w, "Conflict", msg, http.StatusConflict)
+	case strings.Contains(msg, "must") || strings.Contains(msg, "required"):
+		h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+	default:
+		h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+	}
+}
+
+func (h *PayoutHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *PayoutHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
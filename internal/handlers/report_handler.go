@@ -0,0 +1,95 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"expense-tracker/internal/service"
+)
+
+type ReportHandler struct {
+	service *service.ReportService
+}
+
+func NewReportHandler(service *service.ReportService) *ReportHandler {
+	return &ReportHandler{service: service}
+}
+
+// GetVariance serves the budget vs actual report as JSON, or as CSV with
+// ?format=csv.
+func (h *ReportHandler) GetVariance(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	year, err := strconv.Atoi(r.URL.Query().Get("year"))
+	if err != nil {
+		year = h.service.FiscalCalendar().CurrentYear()
+	}
+
+	report, err := h.service.VarianceReport(r.Context(), year)
+	if err != nil {
+		if strings.Contains(err.Error(), "must be") {
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	switch strings.ToLower(r.URL.Query().Get("format")) {
+	case "", "json":
+		h.sendSuccessResponse(w, report, "", http.StatusOK)
+	case "csv":
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=variance-%d.csv", year))
+		if err := h.service.WriteVarianceCSV(w, report); err != nil {
+			h.sendErrorResponse(w, "Export failed", err.Error(), http.StatusInternalServerError)
+		}
+	default:
+		h.sendErrorResponse(w, "Validation error", "format must be json or csv", http.StatusBadRequest)
+	}
+}
+
+func (h *ReportHandler) GetVarianceExpenses(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	categoryID, _ := strconv.Atoi(query.Get("category_id"))
+	month, _ := strconv.Atoi(query.Get("month"))
+	year, err := strconv.Atoi(query.Get("year"))
+	if err != nil {
+		year = h.service.FiscalCalendar().CurrentYear()
+	}
+
+	drill, err := h.service.VarianceExpenses(r.Context(), categoryID, year, month, GetAuthenticatedUser(r))
+	if err != nil {
+		if strings.Contains(err.Error(), "must be") {
+			h.sendErrorResponse(w, "Validation error", err.Error(), http.StatusBadRequest)
+			return
+		}
+		h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	h.sendSuccessResponse(w, drill, "", http.StatusOK)
+}
+
+func (h *ReportHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *ReportHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
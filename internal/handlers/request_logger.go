@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"expense-tracker/internal/logging"
+)
+
+const RequestIDHeader = "X-Request-ID"
+
+func RequestLogger(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = logging.NewRequestID()
+		}
+		w.Header().Set(RequestIDHeader, requestID)
+
+		req := r.WithContext(logging.WithRequestID(r.Context(), requestID))
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+		next.ServeHTTP(rec, req)
+
+		route := req.Pattern
+		if route == "" {
+			route = "unmatched"
+		}
+
+		logger := logging.FromContext(req.Context())
+		attrs := []any{
+			"method", r.Method,
+			"path", r.URL.Path,
+			"route", route,
+			"status", rec.status,
+			"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+		}
+		if userID := logging.UserID(req.Context()); userID > 0 {
+			attrs = append(attrs, "user_id", userID)
+		}
+
+		switch {
+		case rec.status >= http.StatusInternalServerError:
+			logger.Error("request completed", attrs...)
+		case rec.status >= http.StatusBadRequest:
+			logger.Warn("request completed", attrs...)
+		default:
+			logger.Info("request completed", attrs...)
+		}
+	})
+}
+
+func RequestTimeout(timeout time.Duration, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
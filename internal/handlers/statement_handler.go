@@ -0,0 +1,215 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+	"expense-tracker/internal/service"
+)
+
+type StatementHandler struct {
+	service *service.StatementService
+}
+
+func NewStatementHandler(service *service.StatementService) *StatementHandler {
+	return &StatementHandler{service: service}
+}
+
+func (h *StatementHandler) GetStatements(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	statements, err := h.service.GetAll(r.Context())
+	if err != nil {
+		h.sendStatementError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, statements, "", http.StatusOK)
+}
+
+// HandleStatementPath routes /api/statements/import, /lines,
+// /lines/{id}/{match|ignore|reset|expense} and /{id}.
+func (h *StatementHandler) HandleStatementPath(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/statements/"), "/"), "/")
+	switch {
+	case len(parts) == 1 && parts[0] == "import":
+		h.Import(w, r)
+	case len(parts) == 1 && parts[0] == "lines":
+		h.GetLines(w, r)
+	case len(parts) == 3 && parts[0] == "lines":
+		h.HandleLineAction(w, r, parts[1], parts[2])
+	case len(parts) == 1:
+		h.DeleteStatement(w, r, parts[0])
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+// Import reads a statement upload: POST /api/statements/import?format=csv|ofx|camt053
+// with a multipart "file" field or the file as request body. Without a
+// format it is guessed from the file extension.
+func (h *StatementHandler) Import(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	body, filename, err := importBody(w, r)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid upload", err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	format := models.StatementFormat(strings.ToLower(r.URL.Query().Get("format")))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(filename)) {
+		case ".ofx", ".qfx":
+			format = models.StatementOFX
+		case ".xml":
+			format = models.StatementCAMT053
+		default:
+			format = models.StatementCSV
+		}
+	}
+
+	result, err := h.service.Import(r.Context(), format, filename, body, GetAuthenticatedUser(r))
+	if err != nil {
+		h.sendErrorResponse(w, "Import failed", err.Error(), http.StatusBadRequest)
+		return
+	}
+	h.sendSuccessResponse(w, result, fmt.Sprintf("%d statement lines imported, %d matched automatically", result.Imported, result.AutoMatched), http.StatusOK)
+}
+
+func (h *StatementHandler) DeleteStatement(w http.ResponseWriter, r *http.Request, idStr string) {
+	if r.Method != http.MethodDelete {
+		h.sendErrorResponse(w, "Method not allowed", "Only DELETE is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Statement ID must be a number", http.StatusBadRequest)
+		return
+	}
+	if err := h.service.Delete(r.Context(), id); err != nil {
+		h.sendStatementError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, nil, "Bank statement deleted", http.StatusOK)
+}
+
+// GetLines lists statement lines (?status=unmatched|matched|ignored,
+// ?statement_id=); unmatched lines include match candidates.
+func (h *StatementHandler) GetLines(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	statementID, _ := strconv.Atoi(r.URL.Query().Get("statement_id"))
+	filter := models.StatementLineFilter{
+		StatementID: statementID,
+		Status:      models.StatementLineStatus(r.URL.Query().Get("status")),
+	}
+	lines, err := h.service.GetLines(r.Context(), filter)
+	if err != nil {
+		h.sendStatementError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, lines, "", http.StatusOK)
+}
+
+func (h *StatementHandler) HandleLineAction(w http.ResponseWriter, r *http.Request, idStr, action string) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	id, err := strconv.Atoi(idStr)
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Statement line ID must be a number", http.StatusBadRequest)
+		return
+	}
+	user := GetAuthenticatedUser(r)
+
+	switch action {
+	case "match":
+		var req models.StatementMatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		line, err := h.service.Match(r.Context(), id, req, user)
+		if err != nil {
+			h.sendStatementError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, line, "Statement line matched", http.StatusOK)
+	case "ignore":
+		line, err := h.service.Ignore(r.Context(), id, user)
+		if err != nil {
+			h.sendStatementError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, line, "Statement line ignored", http.StatusOK)
+	case "reset":
+		line, err := h.service.Reset(r.Context(), id, user)
+		if err != nil {
+			h.sendStatementError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, line, "Statement line returned to review", http.StatusOK)
+	case "expense":
+		var req models.StatementExpenseRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		expense, err := h.service.CreateExpense(r.Context(), id, req, user)
+		if err != nil {
+			if isCircuitBreakerError(err) {
+				h.sendErrorResponse(w, "Circuit Breaker Active", err.Error(), http.StatusForbidden)
+				return
+			}
+			h.sendStatementError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, expense, "Expense created from statement line", http.StatusCreated)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *StatementHandler) sendStatementError(w http.ResponseWriter, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+	case strings.Contains(msg, "already matched") || errors.Is(err, repository.ErrExpenseReconciled):
+		h.sendErrorResponse(w, "Conflict", msg, http.StatusConflict)
+	case strings.HasPrefix(msg, "only "):
+		h.sendErrorResponse(w, "Forbidden", msg, http.StatusForbidden)
+	case strings.Contains(msg, "must") || strings.Contains(msg, "required"):
+		h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+	default:
+		h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+	}
+}
+
+func (h *StatementHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *StatementHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
@@ -0,0 +1,164 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/service"
+)
+
+type TaxHandler struct {
+	service *service.TaxService
+}
+
+func NewTaxHandler(service *service.TaxService) *TaxHandler {
+	return &TaxHandler{service: service}
+}
+
+// HandleTaxRates lists tax rates (GET /api/tax-rates?active=true, used for
+// the expense form) and lets admins add them.
+func (h *TaxHandler) HandleTaxRates(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		rates, err := h.service.GetAll(r.Context(), r.URL.Query().Get("active") == "true")
+		if err != nil {
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.sendSuccessResponse(w, rates, "", http.StatusOK)
+	case http.MethodPost:
+		if !h.requireAdmin(w, r) {
+			return
+		}
+		var req models.TaxRateRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		rate, err := h.service.Create(r.Context(), req)
+		if err != nil {
+			h.sendTaxError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, rate, "Tax rate created successfully", http.StatusCreated)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleTaxRateByID updates a tax rate: PUT /api/tax-rates/{id}. Rates are
+// deactivated rather than deleted since expenses keep referring to them.
+func (h *TaxHandler) HandleTaxRateByID(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/tax-rates/"), "/"))
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Tax rate ID must be a valid number", http.StatusBadRequest)
+		return
+	}
+	if r.Method != http.MethodPut {
+		h.sendErrorResponse(w, "Method not allowed", "Only PUT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.requireAdmin(w, r) {
+		return
+	}
+
+	var req models.TaxRateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+	rate, err := h.service.Update(r.Context(), id, req)
+	if err != nil {
+		h.sendTaxError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, rate, "Tax rate updated successfully", http.StatusOK)
+}
+
+// GetVATReport serves the VAT report for a period:
+// GET /api/reports/vat?year=2026&quarter=1 (or month=, or start_date=&end_date=),
+// with format=csv for a download.
+func (h *TaxHandler) GetVATReport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		h.sendErrorResponse(w, "Method not allowed", "Only GET is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	query := r.URL.Query()
+	var req models.VATReportRequest
+	for _, p := range []struct {
+		name  string
+		value *int
+	}{{"year", &req.Year}, {"quarter", &req.Quarter}, {"month", &req.Month}} {
+		if v := query.Get(p.name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				h.sendErrorResponse(w, "Validation error", p.name+" must be a number", http.StatusBadRequest)
+				return
+			}
+			*p.value = n
+		}
+	}
+	req.StartDate = query.Get("start_date")
+	req.EndDate = query.Get("end_date")
+
+	format := strings.ToLower(query.Get("format"))
+	if format != "" && format != "json" && format != "csv" {
+		h.sendErrorResponse(w, "Validation error", "format must be json or csv", http.StatusBadRequest)
+		return
+	}
+
+	report, err := h.service.VATReport(r.Context(), req)
+	if err != nil {
+		h.sendTaxError(w, err)
+		return
+	}
+
+	if format == "csv" {
+		w.Header().Set("Content-Type", "text/csv")
+		w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=vat-%s-%s.csv", report.StartDate, report.EndDate))
+		if err := h.service.WriteVATCSV(w, report); err != nil {
+			h.sendErrorResponse(w, "Export failed", err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	h.sendSuccessResponse(w, report, "", http.StatusOK)
+}
+
+func (h *TaxHandler) requireAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if user := GetAuthenticatedUser(r); user == nil || !user.IsAdmin() {
+		h.sendErrorResponse(w, "Forbidden", "Only admins can change tax rates", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (h *TaxHandler) sendTaxError(w http.ResponseWriter, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+	case strings.Contains(msg, "already exists"):
+		h.sendErrorResponse(w, "Conflict", msg, http.StatusConflict)
+	case strings.Contains(msg, "must") || strings.Contains(msg, "required"):
+		h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+	default:
+		h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+	}
+}
+
+func (h *TaxHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *TaxHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
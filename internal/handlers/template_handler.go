@@ -2,10 +2,11 @@ package handlers
 
 import (
 	"html/template"
-	"log"
 	"net/http"
 	"path/filepath"
 
+	"expense-tracker/internal/logging"
+	"expense-tracker/internal/models"
 	"expense-tracker/internal/repository"
 )
 
@@ -14,9 +15,11 @@ type TemplateHandler struct {
 	catRepo     repository.CategoryRepository
 	budgetRepo  repository.BudgetRepository
 	expenseRepo repository.ExpenseRepository
+	costCenters repository.CostCenterRepository
+	fiscal      models.FiscalCalendar
 }
 
-func NewTemplateHandler(templatesDir string, catRepo repository.CategoryRepository, budgetRepo repository.BudgetRepository, expenseRepo repository.ExpenseRepository) *TemplateHandler {
+func NewTemplateHandler(templatesDir string, catRepo repository.CategoryRepository, budgetRepo repository.BudgetRepository, expenseRepo repository.ExpenseRepository, costCenterRepo repository.CostCenterRepository, fiscal models.FiscalCalendar) *TemplateHandler {
 	templates := template.Must(template.ParseGlob(filepath.Join(templatesDir, "*.html")))
 
 	return &TemplateHandler{
@@ -24,6 +27,8 @@ func NewTemplateHandler(templatesDir string, catRepo repository.CategoryReposito
 		catRepo:     catRepo,
 		budgetRepo:  budgetRepo,
 		expenseRepo: expenseRepo,
+		costCenters: costCenterRepo,
+		fiscal:      fiscal,
 	}
 }
 
@@ -43,15 +48,15 @@ func (h *TemplateHandler) RenderHome(w http.ResponseWriter, r *http.Request) {
 
 	err := h.templates.ExecuteTemplate(w, "index.html", data)
 	if err != nil {
-		log.Printf("Error rendering template: %v", err)
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 func (h *TemplateHandler) RenderCategoriesPage(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.catRepo.GetAll(false)
+	categories, err := h.catRepo.GetAll(r.Context(), false)
 	if err != nil {
-		log.Printf("Error fetching categories: %v", err)
+		logging.FromContext(r.Context()).Error("failed to fetch categories", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
@@ -68,76 +73,135 @@ func (h *TemplateHandler) RenderCategoriesPage(w http.ResponseWriter, r *http.Re
 
 	err = h.templates.ExecuteTemplate(w, "categories.html", data)
 	if err != nil {
-		log.Printf("Error rendering template: %v", err)
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 func (h *TemplateHandler) RenderBudgetsPage(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.catRepo.GetAll(true)
+	categories, err := h.catRepo.GetAll(r.Context(), true)
 	if err != nil {
-		log.Printf("Error fetching categories: %v", err)
+		logging.FromContext(r.Context()).Error("failed to fetch categories", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
-	summary, _ := h.budgetRepo.GetDashboardSummary(2026)
+	fiscalYear := h.fiscal.CurrentYear()
+	summary, _ := h.budgetRepo.GetDashboardSummary(r.Context(), fiscalYear)
 
 	data := struct {
 		Categories interface{}
 		Summary    interface{}
 		Title      string
 		User       interface{}
+		FiscalYear models.FiscalYearInfo
 	}{
 		Categories: categories,
 		Summary:    summary,
 		Title:      "Budget Planning",
 		User:       GetAuthenticatedUser(r),
+		FiscalYear: h.fiscal.Info(fiscalYear),
 	}
 
 	err = h.templates.ExecuteTemplate(w, "budgets.html", data)
 	if err != nil {
-		log.Printf("Error rendering template: %v", err)
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 func (h *TemplateHandler) RenderExpensesPage(w http.ResponseWriter, r *http.Request) {
-	categories, err := h.catRepo.GetAll(true)
+	categories, err := h.catRepo.GetAll(r.Context(), true)
 	if err != nil {
-		log.Printf("Error fetching categories: %v", err)
+		logging.FromContext(r.Context()).Error("failed to fetch categories", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+		return
+	}
+
+	costCenters, err := h.costCenters.GetAll(r.Context(), true)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("failed to fetch cost centers", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 		return
 	}
 
 	data := struct {
-		Categories interface{}
-		Title      string
-		User       interface{}
+		Categories  interface{}
+		CostCenters interface{}
+		Title       string
+		User        interface{}
 	}{
-		Categories: categories,
-		Title:      "Expense Tracking",
-		User:       GetAuthenticatedUser(r),
+		Categories:  categories,
+		CostCenters: costCenters,
+		Title:       "Expense Tracking",
+		User:        GetAuthenticatedUser(r),
 	}
 
 	err = h.templates.ExecuteTemplate(w, "expenses.html", data)
 	if err != nil {
-		log.Printf("Error rendering template: %v", err)
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
 
 func (h *TemplateHandler) RenderMonitoringPage(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		User       interface{}
+		Title      string
+		FiscalYear models.FiscalYearInfo
+	}{
+		User:       GetAuthenticatedUser(r),
+		Title:      "Monitoring",
+		FiscalYear: h.fiscal.Info(h.fiscal.CurrentYear()),
+	}
+	err := h.templates.ExecuteTemplate(w, "monitoring.html", data)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (h *TemplateHandler) RenderPayoutsPage(w http.ResponseWriter, r *http.Request) {
 	data := struct {
 		User  interface{}
 		Title string
 	}{
 		User:  GetAuthenticatedUser(r),
-		Title: "Monitoring",
+		Title: "Reimbursements",
 	}
-	err := h.templates.ExecuteTemplate(w, "monitoring.html", data)
+	err := h.templates.ExecuteTemplate(w, "payouts.html", data)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (h *TemplateHandler) RenderStatementsPage(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		User  interface{}
+		Title string
+	}{
+		User:  GetAuthenticatedUser(r),
+		Title: "Bank Reconciliation",
+	}
+	err := h.templates.ExecuteTemplate(w, "statements.html", data)
+	if err != nil {
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
+		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
+	}
+}
+
+func (h *TemplateHandler) RenderTaxPage(w http.ResponseWriter, r *http.Request) {
+	data := struct {
+		User  interface{}
+		Title string
+	}{
+		User:  GetAuthenticatedUser(r),
+		Title: "Tax & VAT",
+	}
+	err := h.templates.ExecuteTemplate(w, "tax.html", data)
 	if err != nil {
-		log.Printf("Error rendering template: %v", err)
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -152,7 +216,7 @@ func (h *TemplateHandler) RenderLoginPage(w http.ResponseWriter, r *http.Request
 	}
 	err := h.templates.ExecuteTemplate(w, "login.html", data)
 	if err != nil {
-		log.Printf("Error rendering template: %v", err)
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -167,7 +231,7 @@ func (h *TemplateHandler) RenderSetPasswordPage(w http.ResponseWriter, r *http.R
 	}
 	err := h.templates.ExecuteTemplate(w, "set-password.html", data)
 	if err != nil {
-		log.Printf("Error rendering template: %v", err)
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
@@ -182,7 +246,7 @@ func (h *TemplateHandler) RenderUsersPage(w http.ResponseWriter, r *http.Request
 	}
 	err := h.templates.ExecuteTemplate(w, "users.html", data)
 	if err != nil {
-		log.Printf("Error rendering template: %v", err)
+		logging.FromContext(r.Context()).Error("failed to render template", "error", err)
 		http.Error(w, "Internal Server Error", http.StatusInternalServerError)
 	}
 }
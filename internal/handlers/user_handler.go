@@ -32,7 +32,7 @@ func (h *UserHandler) CreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	user, err := h.userService.CreateUser(req.Name, req.DisplayID, req.Email, models.RoleExecutive)
+	user, err := h.userService.CreateUser(r.Context(), req.Name, req.DisplayID, req.Email, models.RoleExecutive)
 	if err != nil {
 		h.sendErrorResponse(w, "Failed to create user", err.Error(), http.StatusInternalServerError)
 		return
@@ -47,7 +47,7 @@ func (h *UserHandler) ListUsers(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	users, err := h.userService.GetAllUsers()
+	users, err := h.userService.GetAllUsers(r.Context())
 	if err != nil {
 		h.sendErrorResponse(w, "Failed to fetch users", err.Error(), http.StatusInternalServerError)
 		return
@@ -72,7 +72,7 @@ func (h *UserHandler) UpdateUserRole(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.userService.UpdateUserRole(req.UserID, req.Role); err != nil {
+	if err := h.userService.UpdateUserRole(r.Context(), req.UserID, req.Role); err != nil {
 		h.sendErrorResponse(w, "Failed to update role", err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/service"
+)
+
+type VendorHandler struct {
+	service *service.VendorService
+}
+
+func NewVendorHandler(service *service.VendorService) *VendorHandler {
+	return &VendorHandler{service: service}
+}
+
+// HandleVendors lists vendors (GET /api/vendors?q=acme, used for the expense
+// form's autocomplete) and lets management and admins add them.
+func (h *VendorHandler) HandleVendors(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		vendors, err := h.service.GetAll(r.Context(), r.URL.Query().Get("q"))
+		if err != nil {
+			h.sendErrorResponse(w, "Database error", err.Error(), http.StatusInternalServerError)
+			return
+		}
+		h.sendSuccessResponse(w, vendors, "", http.StatusOK)
+	case http.MethodPost:
+		if !h.requireManager(w, r) {
+			return
+		}
+		var req models.VendorRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		vendor, err := h.service.Create(r.Context(), req)
+		if err != nil {
+			h.sendVendorError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, vendor, "Vendor created successfully", http.StatusCreated)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only GET and POST methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+// HandleVendorPath routes /api/vendors/{id}, /{id}/aliases and /{id}/merge.
+// All of them change vendors and are limited to management and admins.
+func (h *VendorHandler) HandleVendorPath(w http.ResponseWriter, r *http.Request) {
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(r.URL.Path, "/api/vendors/"), "/"), "/")
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		h.sendErrorResponse(w, "Invalid ID", "Vendor ID must be a valid number", http.StatusBadRequest)
+		return
+	}
+	if !h.requireManager(w, r) {
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		h.UpdateVendor(w, r, id)
+	case len(parts) == 2 && parts[1] == "aliases":
+		h.HandleAliases(w, r, id)
+	case len(parts) == 2 && parts[1] == "merge":
+		h.MergeVendor(w, r, id)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *VendorHandler) UpdateVendor(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPut {
+		h.sendErrorResponse(w, "Method not allowed", "Only PUT is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.VendorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+	vendor, err := h.service.Update(r.Context(), id, req)
+	if err != nil {
+		h.sendVendorError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, vendor, "Vendor updated successfully", http.StatusOK)
+}
+
+// HandleAliases adds (POST {"alias": ...}) or removes (DELETE ?alias=...) an
+// alternative spelling of a vendor.
+func (h *VendorHandler) HandleAliases(w http.ResponseWriter, r *http.Request, id int) {
+	switch r.Method {
+	case http.MethodPost:
+		var req models.VendorAliasRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+			return
+		}
+		vendor, err := h.service.AddAlias(r.Context(), id, req)
+		if err != nil {
+			h.sendVendorError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, vendor, "Vendor alias added", http.StatusOK)
+	case http.MethodDelete:
+		vendor, err := h.service.RemoveAlias(r.Context(), id, r.URL.Query().Get("alias"))
+		if err != nil {
+			h.sendVendorError(w, err)
+			return
+		}
+		h.sendSuccessResponse(w, vendor, "Vendor alias removed", http.StatusOK)
+	default:
+		h.sendErrorResponse(w, "Method not allowed", "Only POST and DELETE methods are supported", http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *VendorHandler) MergeVendor(w http.ResponseWriter, r *http.Request, id int) {
+	if r.Method != http.MethodPost {
+		h.sendErrorResponse(w, "Method not allowed", "Only POST is supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req models.VendorMergeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		h.sendErrorResponse(w, "Invalid JSON", "Request body must be valid JSON", http.StatusBadRequest)
+		return
+	}
+	merge, err := h.service.Merge(r.Context(), id, req, GetAuthenticatedUser(r))
+	if err != nil {
+		h.sendVendorError(w, err)
+		return
+	}
+	h.sendSuccessResponse(w, merge, "Vendor merged successfully", http.StatusOK)
+}
+
+func (h *VendorHandler) requireManager(w http.ResponseWriter, r *http.Request) bool {
+	if user := GetAuthenticatedUser(r); user == nil || !user.CanManage() {
+		h.sendErrorResponse(w, "Forbidden", "Only management and admins can change vendors", http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+func (h *VendorHandler) sendVendorError(w http.ResponseWriter, err error) {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "not found"):
+		h.sendErrorResponse(w, "Not found", msg, http.StatusNotFound)
+	case strings.Contains(msg, "already exists"):
+		h.sendErrorResponse(w, "Conflict", msg, http.StatusConflict)
+	case strings.Contains(msg, "must") || strings.Contains(msg, "required"):
+		h.sendErrorResponse(w, "Validation error", msg, http.StatusBadRequest)
+	default:
+		h.sendErrorResponse(w, "Database error", msg, http.StatusInternalServerError)
+	}
+}
+
+func (h *VendorHandler) sendErrorResponse(w http.ResponseWriter, error string, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(ErrorResponse{Error: error, Message: message})
+}
+
+func (h *VendorHandler) sendSuccessResponse(w http.ResponseWriter, data interface{}, message string, statusCode int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	json.NewEncoder(w).Encode(SuccessResponse{Success: true, Data: data, Message: message})
+}
@@ -2,45 +2,104 @@ package models
 
 import "time"
 
+// MinBudgetAmount is the amount every budget must exceed, including after
+// transfers out of it.
+var MinBudgetAmount = MoneyFromUnits(10000)
+
 type Budget struct {
-	ID         int       `json:"id"`
-	CategoryID int       `json:"category_id"`
-	Amount     float64   `json:"amount"`
-	Year       int       `json:"year"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-
-	CategoryName string `json:"category_name,omitempty"`
-	IsLocked     bool   `json:"is_locked"`
+	ID         int          `json:"id"`
+	CategoryID int          `json:"category_id"`
+	Amount     Money        `json:"amount"`
+	Year       int          `json:"year"`
+	Period     BudgetPeriod `json:"period"`
+	Phasing    []Money      `json:"phasing,omitempty"`
+	CreatedAt  time.Time    `json:"created_at"`
+	UpdatedAt  time.Time    `json:"updated_at"`
+
+	CategoryName string     `json:"category_name,omitempty"`
+	IsLocked     bool       `json:"is_locked"`
+	LockedAt     *time.Time `json:"locked_at,omitempty"`
+
+	AutoLockPercent  *float64   `json:"auto_lock_percent,omitempty"`
+	RejectOverBudget bool       `json:"reject_over_budget"`
+	AutoLocked       bool       `json:"auto_locked"`
+	OverrideUntil    *time.Time `json:"override_until,omitempty"`
+	OverrideReason   string     `json:"override_reason,omitempty"`
+	AlertThresholds  []float64  `json:"alert_thresholds,omitempty"`
 }
 
 type BudgetRequest struct {
-	CategoryID int     `json:"category_id"`
-	Amount     float64 `json:"amount"`
-	Year       int     `json:"year"`
+	CategoryID int          `json:"category_id"`
+	Amount     Money        `json:"amount"`
+	Year       int          `json:"year"`
+	Period     BudgetPeriod `json:"period,omitempty"`
+	Phasing    []Money      `json:"phasing,omitempty"`
+	Reason     string       `json:"reason,omitempty"`
+
+	ChangedBy int `json:"-"`
 }
 
+// BudgetDashboardSummary totals a year's budgets. TotalSpent counts approved
+// expenses and Committed those still awaiting approval, so RemainingBudget is
+// what is left once everything pending is approved.
 type BudgetDashboardSummary struct {
-	TotalAnnualBudget float64 `json:"total_annual_budget"`
-	HighestAllocation float64 `json:"highest_allocation"`
-	RemainingBudget   float64 `json:"remaining_budget"`
-	SavingsTarget     float64 `json:"savings_target"`
+	Year                 int      `json:"year"`
+	TotalAnnualBudget    Money    `json:"total_annual_budget"`
+	HighestAllocation    Money    `json:"highest_allocation"`
+	TotalSpent           Money    `json:"total_spent"`
+	Committed            Money    `json:"committed"`
+	RemainingBudget      Money    `json:"remaining_budget"`
+	PercentConsumed      float64  `json:"percent_consumed"`
+	CategoriesOverBudget int      `json:"categories_over_budget"`
+	OverBudgetCategories []string `json:"over_budget_categories"`
+	SavingsTarget        Money    `json:"savings_target"`
+}
+
+type SavingsTargetRequest struct {
+	Year   int   `json:"year"`
+	Amount Money `json:"amount"`
 }
 
 type BudgetMonitoringItem struct {
-	BudgetID     int     `json:"budget_id"`
-	CategoryID   int     `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	BudgetAmount float64 `json:"budget_amount"`
-	SpentAmount  float64 `json:"spent_amount"`
-	Percentage   float64 `json:"percentage"`
-	IsLocked     bool    `json:"is_locked"`
+	BudgetID     int          `json:"budget_id"`
+	CategoryID   int          `json:"category_id"`
+	CategoryName string       `json:"category_name"`
+	BudgetAmount Money        `json:"budget_amount"`
+	SpentAmount  Money        `json:"spent_amount"`
+	Percentage   float64      `json:"percentage"`
+	IsLocked     bool         `json:"is_locked"`
+	Period       BudgetPeriod `json:"period"`
+
+	PeriodLabel        string    `json:"period_label"`
+	PeriodStart        time.Time `json:"period_start"`
+	PeriodEnd          time.Time `json:"period_end"`
+	PeriodBudgetAmount Money     `json:"period_budget_amount"`
+	PeriodSpentAmount  Money     `json:"period_spent_amount"`
+	PeriodPercentage   float64   `json:"period_percentage"`
+
+	Forecast *CategoryForecast `json:"forecast,omitempty"`
+
+	ParentCategoryID  *int  `json:"parent_category_id,omitempty"`
+	Depth             int   `json:"depth"`
+	SubcategoryBudget Money `json:"subcategory_budget"`
+	// RollUp marks a parent category without a budget of its own; its
+	// budget is then the sum of its subcategories' budgets.
+	RollUp bool `json:"roll_up"`
+
+	Phasing       []Money    `json:"-"`
+	LockedAt      *time.Time `json:"-"`
+	OverrideUntil *time.Time `json:"-"`
 }
 
 type BudgetStatus struct {
-	Allocated float64 `json:"allocated"`
-	Spent     float64 `json:"spent"`
-	Remaining float64 `json:"remaining"`
+	Allocated Money   `json:"allocated"`
+	Spent     Money   `json:"spent"`
+	Remaining Money   `json:"remaining"`
 	Percent   float64 `json:"percent"`
 	IsLocked  bool    `json:"is_locked"`
+
+	Period          BudgetPeriod `json:"period,omitempty"`
+	PeriodLabel     string       `json:"period_label,omitempty"`
+	AnnualAllocated Money        `json:"annual_allocated"`
+	AnnualSpent     Money        `json:"annual_spent"`
 }
@@ -2,12 +2,56 @@ package models
 
 import "time"
 
+type BudgetEntryType string
+
+const (
+	BudgetEntryInitial       BudgetEntryType = "initial"
+	BudgetEntryRevision      BudgetEntryType = "revision"
+	BudgetEntrySupplementary BudgetEntryType = "supplementary"
+	BudgetEntryTransfer      BudgetEntryType = "transfer"
+)
+
 type BudgetEntry struct {
-	ID          int       `json:"id"`
-	BudgetID    int       `json:"budget_id"`
-	Amount      float64   `json:"amount"`
-	Description string    `json:"description"`
-	Date        time.Time `json:"date"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID          int             `json:"id"`
+	BudgetID    int             `json:"budget_id"`
+	Amount      Money           `json:"amount"`
+	EntryType   BudgetEntryType `json:"entry_type"`
+	Description string          `json:"description"`
+	UserID      *int            `json:"user_id,omitempty"`
+	TransferID  *int            `json:"transfer_id,omitempty"`
+	Date        time.Time       `json:"date"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+
+	UserName string `json:"user_name,omitempty"`
+}
+
+type BudgetAllocationRequest struct {
+	Amount Money  `json:"amount"`
+	Reason string `json:"reason"`
+}
+
+type BudgetHistory struct {
+	Budget          *Budget       `json:"budget"`
+	Entries         []BudgetEntry `json:"entries"`
+	EffectiveAmount Money         `json:"effective_amount"`
+}
+
+type BudgetTransferRequest struct {
+	FromCategoryID int    `json:"from_category_id"`
+	ToCategoryID   int    `json:"to_category_id"`
+	Year           int    `json:"year"`
+	Amount         Money  `json:"amount"`
+	Reason         string `json:"reason"`
+}
+
+type BudgetTransfer struct {
+	ID           int           `json:"id"`
+	FromBudgetID int           `json:"from_budget_id"`
+	ToBudgetID   int           `json:"to_budget_id"`
+	Amount       Money         `json:"amount"`
+	Reason       string        `json:"reason"`
+	UserID       *int          `json:"user_id,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	Entries      []BudgetEntry `json:"entries"`
 }
@@ -0,0 +1,124 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+type BudgetPeriod string
+
+const (
+	BudgetPeriodAnnual    BudgetPeriod = "annual"
+	BudgetPeriodQuarterly BudgetPeriod = "quarterly"
+	BudgetPeriodMonthly   BudgetPeriod = "monthly"
+)
+
+func (p BudgetPeriod) Valid() bool {
+	return p == BudgetPeriodAnnual || p == BudgetPeriodQuarterly || p == BudgetPeriodMonthly
+}
+
+func (p BudgetPeriod) monthsPerPeriod() int {
+	switch p {
+	case BudgetPeriodMonthly:
+		return 1
+	case BudgetPeriodQuarterly:
+		return 3
+	default:
+		return 12
+	}
+}
+
+// PeriodMonths returns the first and last fiscal month (1-12) of the period
+// that contains fiscal month.
+func (p BudgetPeriod) PeriodMonths(month int) (int, int) {
+	size := p.monthsPerPeriod()
+	first := ((month-1)/size)*size + 1
+	return first, first + size - 1
+}
+
+func (p BudgetPeriod) Label(cal FiscalCalendar, year, month int) string {
+	switch p {
+	case BudgetPeriodMonthly:
+		return cal.MonthStart(year, month).Format("Jan 2006")
+	case BudgetPeriodQuarterly:
+		return fmt.Sprintf("Q%d %s", (month-1)/3+1, cal.Label(year))
+	default:
+		return cal.Label(year)
+	}
+}
+
+func ValidatePhasing(amount Money, phasing []Money) error {
+	if len(phasing) == 0 {
+		return nil
+	}
+	if len(phasing) != 12 {
+		return fmt.Errorf("phasing must contain 12 monthly amounts")
+	}
+	var total Money
+	for _, m := range phasing {
+		if m < 0 {
+			return fmt.Errorf("phasing amounts cannot be negative")
+		}
+		total += m
+	}
+	if total != amount {
+		return fmt.Errorf("phasing totals %s but budget amount is %s", total, amount)
+	}
+	return nil
+}
+
+// MonthlyAllocations spreads the budget over the 12 fiscal months, using the stored
+// phasing when present and an even split (remainder cents going to the
+// earliest months) otherwise.
+func (b *Budget) MonthlyAllocations() []Money {
+	if len(b.Phasing) == 12 {
+		return b.Phasing
+	}
+	months := make([]Money, 12)
+	for i := range months {
+		months[i] = b.Amount.MulRatio(int64(i+1), 12) - b.Amount.MulRatio(int64(i), 12)
+	}
+	return months
+}
+
+func (b *Budget) PeriodAllocation(month int) Money {
+	first, last := b.Period.PeriodMonths(month)
+	allocations := b.MonthlyAllocations()
+	var total Money
+	for m := first; m <= last; m++ {
+		total += allocations[m-1]
+	}
+	return total
+}
+
+func (b *Budget) PeriodBounds(cal FiscalCalendar, month int) (time.Time, time.Time) {
+	first, last := b.Period.PeriodMonths(month)
+	return cal.MonthStart(b.Year, first), cal.MonthStart(b.Year, last+1).AddDate(0, 0, -1)
+}
+
+// LockApplies reports whether a lock engaged on lockedAt still covers date.
+// Annual locks last the whole year; periodic locks expire with their period.
+func (b *Budget) LockApplies(cal FiscalCalendar, date time.Time) bool {
+	if !b.IsLocked || b.OverrideActive(date) {
+		return false
+	}
+	if b.LockedAt == nil || b.Period == BudgetPeriodAnnual || b.Period == "" {
+		return true
+	}
+	lockedFirst, _ := b.Period.PeriodMonths(cal.MonthOf(*b.LockedAt))
+	dateFirst, _ := b.Period.PeriodMonths(cal.MonthOf(date))
+	return cal.YearOf(*b.LockedAt) == cal.YearOf(date) && lockedFirst == dateFirst
+}
+
+// SpreadOverPhasing adds delta to a 12-month phasing evenly so the phasing
+// keeps summing to the budget amount.
+func SpreadOverPhasing(phasing []Money, delta Money) []Money {
+	if len(phasing) != 12 {
+		return phasing
+	}
+	spread := make([]Money, 12)
+	for i := range phasing {
+		spread[i] = phasing[i] + delta.MulRatio(int64(i+1), 12) - delta.MulRatio(int64(i), 12)
+	}
+	return spread
+}
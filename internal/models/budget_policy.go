@@ -0,0 +1,48 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type BudgetLockAction string
+
+const (
+	BudgetLockActionLock     BudgetLockAction = "lock"
+	BudgetLockActionUnlock   BudgetLockAction = "unlock"
+	BudgetLockActionOverride BudgetLockAction = "override"
+)
+
+type BudgetPolicy struct {
+	AutoLockPercent  *float64 `json:"auto_lock_percent"`
+	RejectOverBudget bool     `json:"reject_over_budget"`
+}
+
+func (p BudgetPolicy) Validate() error {
+	if p.AutoLockPercent != nil && (*p.AutoLockPercent <= 0 || *p.AutoLockPercent > 999) {
+		return errors.New("auto lock percent must be between 0 and 999")
+	}
+	return nil
+}
+
+type BudgetOverrideRequest struct {
+	Until  string `json:"until"`
+	Reason string `json:"reason"`
+}
+
+type BudgetLockEvent struct {
+	ID        int              `json:"id"`
+	BudgetID  int              `json:"budget_id"`
+	Action    BudgetLockAction `json:"action"`
+	Automatic bool             `json:"automatic"`
+	Reason    string           `json:"reason"`
+	UserID    *int             `json:"user_id,omitempty"`
+	UserName  string           `json:"user_name,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+// OverrideActive reports whether management has suspended the circuit
+// breaker for this budget on date.
+func (b *Budget) OverrideActive(date time.Time) bool {
+	return b.OverrideUntil != nil && !date.After(*b.OverrideUntil)
+}
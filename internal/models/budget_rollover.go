@@ -0,0 +1,110 @@
+package models
+
+import (
+	"errors"
+	"math"
+)
+
+type RollForwardBasis string
+
+const (
+	RollForwardSameAmount RollForwardBasis = "same"
+	RollForwardActual     RollForwardBasis = "actual"
+)
+
+type RollForwardRequest struct {
+	FromYear      int              `json:"from_year"`
+	ToYear        int              `json:"to_year"`
+	Basis         RollForwardBasis `json:"basis"`
+	UpliftPercent float64          `json:"uplift_percent"`
+	CarryOver     bool             `json:"carry_over"`
+	Overwrite     bool             `json:"overwrite"`
+	CategoryIDs   []int            `json:"category_ids,omitempty"`
+	Preview       bool             `json:"preview"`
+}
+
+func (r *RollForwardRequest) Validate() error {
+	if r.FromYear <= 0 || r.ToYear <= 0 {
+		return errors.New("from_year and to_year must be greater than 0")
+	}
+	if r.ToYear <= r.FromYear {
+		return errors.New("to_year must be after from_year")
+	}
+	if r.Basis == "" {
+		r.Basis = RollForwardSameAmount
+	}
+	if r.Basis != RollForwardSameAmount && r.Basis != RollForwardActual {
+		return errors.New("basis must be same or actual")
+	}
+	if r.UpliftPercent <= -100 || r.UpliftPercent > 1000 {
+		return errors.New("uplift_percent must be greater than -100 and at most 1000")
+	}
+	return nil
+}
+
+// Apply returns the next-year amount for a budget of amount with spent
+// recorded against it: the chosen basis, scaled by the uplift, plus any
+// unspent remainder when carrying over.
+func (r *RollForwardRequest) Apply(amount, spent Money) (base, carry, proposed Money) {
+	base = amount
+	if r.Basis == RollForwardActual {
+		base = spent
+	}
+	basisPoints := int64(math.Round(r.UpliftPercent * 100))
+	proposed = base.MulRatio(10000+basisPoints, 10000)
+	if r.CarryOver && amount > spent {
+		carry = amount - spent
+		proposed += carry
+	}
+	return base, carry, proposed
+}
+
+type RollForwardItem struct {
+	CategoryID     int          `json:"category_id"`
+	CategoryName   string       `json:"category_name"`
+	Period         BudgetPeriod `json:"period"`
+	SourceAmount   Money        `json:"source_amount"`
+	SourceSpent    Money        `json:"source_spent"`
+	CarryOver      Money        `json:"carry_over"`
+	ProposedAmount Money        `json:"proposed_amount"`
+	ExistingAmount *Money       `json:"existing_amount,omitempty"`
+	Action         string       `json:"action"`
+	Note           string       `json:"note,omitempty"`
+
+	Phasing []Money `json:"-"`
+}
+
+type RollForwardResult struct {
+	FromYear      int               `json:"from_year"`
+	ToYear        int               `json:"to_year"`
+	Preview       bool              `json:"preview"`
+	Items         []RollForwardItem `json:"items"`
+	TotalProposed Money             `json:"total_proposed"`
+	Created       int               `json:"created"`
+	Updated       int               `json:"updated"`
+	Skipped       int               `json:"skipped"`
+}
+
+// ScalePhasing rescales a 12-month phasing from its current total to amount,
+// putting any rounding difference in the final month.
+func ScalePhasing(phasing []Money, amount Money) []Money {
+	if len(phasing) != 12 {
+		return nil
+	}
+	var total Money
+	for _, p := range phasing {
+		total += p
+	}
+	if total <= 0 {
+		return nil
+	}
+
+	scaled := make([]Money, 12)
+	var sum Money
+	for i, p := range phasing[:11] {
+		scaled[i] = p.MulRatio(int64(amount), int64(total))
+		sum += scaled[i]
+	}
+	scaled[11] = amount - sum
+	return scaled
+}
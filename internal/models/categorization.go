@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"time"
+)
+
+type RuleMatchType string
+
+const (
+	RuleMatchKeyword RuleMatchType = "keyword"
+	RuleMatchRegex   RuleMatchType = "regex"
+)
+
+// CategorizationRule suggests CategoryID for expenses whose remarks, amount
+// (in base currency) and user all match. Empty criteria match everything.
+type CategorizationRule struct {
+	ID             int           `json:"id"`
+	Name           string        `json:"name"`
+	CategoryID     int           `json:"category_id"`
+	Priority       int           `json:"priority"`
+	MatchType      RuleMatchType `json:"match_type"`
+	RemarksPattern string        `json:"remarks_pattern"`
+	MinAmount      *Money        `json:"min_amount,omitempty"`
+	MaxAmount      *Money        `json:"max_amount,omitempty"`
+	UserID         *int          `json:"user_id,omitempty"`
+	AutoAssign     bool          `json:"auto_assign"`
+	IsActive       bool          `json:"is_active"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+
+	CategoryName string `json:"category_name,omitempty"`
+}
+
+type CategorizationRuleRequest struct {
+	Name           string        `json:"name"`
+	CategoryID     int           `json:"category_id"`
+	Priority       int           `json:"priority"`
+	MatchType      RuleMatchType `json:"match_type"`
+	RemarksPattern string        `json:"remarks_pattern"`
+	MinAmount      *Money        `json:"min_amount,omitempty"`
+	MaxAmount      *Money        `json:"max_amount,omitempty"`
+	UserID         *int          `json:"user_id,omitempty"`
+	AutoAssign     bool          `json:"auto_assign"`
+	IsActive       *bool         `json:"is_active,omitempty"`
+}
+
+func (r *CategorizationRuleRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	r.RemarksPattern = strings.TrimSpace(r.RemarksPattern)
+	if r.Name == "" {
+		return errors.New("rule name is required")
+	}
+	if r.CategoryID <= 0 {
+		return errors.New("category ID must be greater than 0")
+	}
+	if r.MatchType == "" {
+		r.MatchType = RuleMatchKeyword
+	}
+	switch r.MatchType {
+	case RuleMatchKeyword:
+	case RuleMatchRegex:
+		if _, err := regexp.Compile("(?i)" + r.RemarksPattern); err != nil {
+			return errors.New("remarks pattern must be a valid regular expression")
+		}
+	default:
+		return errors.New("match type must be keyword or regex")
+	}
+	if r.MinAmount != nil && r.MaxAmount != nil && *r.MinAmount > *r.MaxAmount {
+		return errors.New("minimum amount must be less than or equal to maximum amount")
+	}
+	if r.RemarksPattern == "" && r.MinAmount == nil && r.MaxAmount == nil && r.UserID == nil {
+		return errors.New("a rule must match on remarks, amount or user")
+	}
+	return nil
+}
+
+// Matches reports whether an expense with remarks, base-currency amount and
+// userID satisfies every criterion of the rule. Keyword patterns are
+// comma-separated and match case-insensitively on any keyword.
+func (r *CategorizationRule) Matches(remarks string, amount Money, userID int) bool {
+	if r.UserID != nil && *r.UserID != userID {
+		return false
+	}
+	if r.MinAmount != nil && amount < *r.MinAmount {
+		return false
+	}
+	if r.MaxAmount != nil && amount > *r.MaxAmount {
+		return false
+	}
+	if r.RemarksPattern == "" {
+		return true
+	}
+
+	if r.MatchType == RuleMatchRegex {
+		re, err := regexp.Compile("(?i)" + r.RemarksPattern)
+		return err == nil && re.MatchString(remarks)
+	}
+	remarks = strings.ToLower(remarks)
+	for _, keyword := range strings.Split(r.RemarksPattern, ",") {
+		if keyword = strings.ToLower(strings.TrimSpace(keyword)); keyword != "" && strings.Contains(remarks, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// CategorySuggestionRequest describes an expense being entered. Rule amount
+// bounds are in the base currency; an Amount in another Currency is converted
+// at the rate of ExpenseDate (today when empty) before rules are matched.
+type CategorySuggestionRequest struct {
+	Remarks     string `json:"remarks"`
+	Amount      Money  `json:"amount"`
+	Currency    string `json:"currency"`
+	ExpenseDate string `json:"expense_date"`
+	UserID      int    `json:"-"`
+}
+
+type CategorySuggestion struct {
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	RuleID       int    `json:"rule_id"`
+	RuleName     string `json:"rule_name"`
+	AutoAssign   bool   `json:"auto_assign"`
+}
+
+// RuleOverrideStats counts, per rule, how often its suggestion was kept or
+// replaced by a different category.
+type RuleOverrideStats struct {
+	RuleID       int     `json:"rule_id"`
+	RuleName     string  `json:"rule_name"`
+	CategoryID   int     `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	Suggested    int     `json:"suggested"`
+	Accepted     int     `json:"accepted"`
+	Overridden   int     `json:"overridden"`
+	OverrideRate float64 `json:"override_rate"`
+}
@@ -6,6 +6,7 @@ type Category struct {
 	ID        int       `json:"id" db:"id"`
 	Name      string    `json:"name" db:"name"`
 	IsActive  bool      `json:"is_active" db:"is_active"`
+	ParentID  *int      `json:"parent_id,omitempty" db:"parent_id"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -13,4 +14,30 @@ type Category struct {
 type CategoryRequest struct {
 	Name     string `json:"name"`
 	IsActive *bool  `json:"is_active,omitempty"`
+	ParentID *int   `json:"parent_id,omitempty"`
+}
+
+// CategoryNode is a category with its subcategories, as served by the tree endpoint.
+type CategoryNode struct {
+	Category
+	Path     string         `json:"path"`
+	Children []CategoryNode `json:"children"`
+}
+
+type CategoryMergeRequest struct {
+	TargetID int `json:"target_id"`
+}
+
+// CategoryMerge records a merge of one category into another.
+type CategoryMerge struct {
+	ID                 int       `json:"id"`
+	SourceCategoryID   int       `json:"source_category_id"`
+	SourceName         string    `json:"source_name"`
+	TargetCategoryID   int       `json:"target_category_id"`
+	ExpensesMoved      int       `json:"expenses_moved"`
+	BudgetsMoved       int       `json:"budgets_moved"`
+	BudgetsCombined    int       `json:"budgets_combined"`
+	SubcategoriesMoved int       `json:"subcategories_moved"`
+	UserID             *int      `json:"user_id,omitempty"`
+	CreatedAt          time.Time `json:"created_at"`
 }
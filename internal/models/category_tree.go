@@ -0,0 +1,103 @@
+package models
+
+import "strings"
+
+// CategoryTree indexes categories by parent so callers can walk the hierarchy
+// without further queries.
+type CategoryTree struct {
+	byID     map[int]Category
+	children map[int][]int
+	roots    []int
+}
+
+func NewCategoryTree(categories []Category) *CategoryTree {
+	t := &CategoryTree{
+		byID:     make(map[int]Category, len(categories)),
+		children: map[int][]int{},
+	}
+	for _, c := range categories {
+		t.byID[c.ID] = c
+	}
+	for _, c := range categories {
+		if c.ParentID != nil {
+			if _, ok := t.byID[*c.ParentID]; ok {
+				t.children[*c.ParentID] = append(t.children[*c.ParentID], c.ID)
+				continue
+			}
+		}
+		t.roots = append(t.roots, c.ID)
+	}
+	return t
+}
+
+func (t *CategoryTree) Get(id int) (Category, bool) {
+	c, ok := t.byID[id]
+	return c, ok
+}
+
+func (t *CategoryTree) Children(id int) []int {
+	return t.children[id]
+}
+
+// Ancestors returns the ids above id, nearest parent first.
+func (t *CategoryTree) Ancestors(id int) []int {
+	var ids []int
+	seen := map[int]bool{id: true}
+	for c, ok := t.byID[id]; ok && c.ParentID != nil && !seen[*c.ParentID]; c, ok = t.byID[*c.ParentID] {
+		seen[*c.ParentID] = true
+		ids = append(ids, *c.ParentID)
+	}
+	return ids
+}
+
+// Descendants returns every id below id, depth first.
+func (t *CategoryTree) Descendants(id int) []int {
+	var ids []int
+	for _, child := range t.children[id] {
+		ids = append(ids, child)
+		ids = append(ids, t.Descendants(child)...)
+	}
+	return ids
+}
+
+func (t *CategoryTree) Depth(id int) int {
+	return len(t.Ancestors(id))
+}
+
+// Path renders id as "Travel > Flights".
+func (t *CategoryTree) Path(id int) string {
+	ancestors := t.Ancestors(id)
+	names := make([]string, 0, len(ancestors)+1)
+	for i := len(ancestors) - 1; i >= 0; i-- {
+		names = append(names, t.byID[ancestors[i]].Name)
+	}
+	names = append(names, t.byID[id].Name)
+	return strings.Join(names, " > ")
+}
+
+// Ordered returns every id depth first, each parent before its subcategories.
+func (t *CategoryTree) Ordered() []int {
+	var ids []int
+	for _, root := range t.roots {
+		ids = append(ids, root)
+		ids = append(ids, t.Descendants(root)...)
+	}
+	return ids
+}
+
+// Nodes returns the hierarchy as nested nodes, siblings in input order.
+func (t *CategoryTree) Nodes() []CategoryNode {
+	return t.nodes(t.roots)
+}
+
+func (t *CategoryTree) nodes(ids []int) []CategoryNode {
+	nodes := make([]CategoryNode, 0, len(ids))
+	for _, id := range ids {
+		nodes = append(nodes, CategoryNode{
+			Category: t.byID[id],
+			Path:     t.Path(id),
+			Children: t.nodes(t.children[id]),
+		})
+	}
+	return nodes
+}
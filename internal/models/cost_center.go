@@ -0,0 +1,109 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type CostCenter struct {
+	ID        int       `json:"id"`
+	Code      string    `json:"code"`
+	Name      string    `json:"name"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type CostCenterRequest struct {
+	Code     string `json:"code"`
+	Name     string `json:"name"`
+	IsActive *bool  `json:"is_active"`
+}
+
+func (r *CostCenterRequest) Validate() error {
+	r.Code = strings.ToUpper(strings.TrimSpace(r.Code))
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Code == "" {
+		return errors.New("cost center code is required")
+	}
+	if len(r.Code) > 20 {
+		return errors.New("cost center code must be at most 20 characters")
+	}
+	if r.Name == "" {
+		return errors.New("cost center name is required")
+	}
+	return nil
+}
+
+// CostCenterBudget is an annual allocation for a cost center, checked per
+// period like category budgets. Locks and the auto-lock/over-budget policy
+// behave as on category budgets; the change ledger and threshold alerts are
+// kept for category budgets only.
+type CostCenterBudget struct {
+	ID             int          `json:"id"`
+	CostCenterID   int          `json:"cost_center_id"`
+	CostCenterCode string       `json:"cost_center_code,omitempty"`
+	CostCenterName string       `json:"cost_center_name,omitempty"`
+	Year           int          `json:"year"`
+	Amount         Money        `json:"amount"`
+	Period         BudgetPeriod `json:"period"`
+	Phasing        []Money      `json:"phasing,omitempty"`
+	IsLocked       bool         `json:"is_locked"`
+	LockedAt       *time.Time   `json:"locked_at,omitempty"`
+
+	AutoLockPercent  *float64 `json:"auto_lock_percent,omitempty"`
+	RejectOverBudget bool     `json:"reject_over_budget"`
+	AutoLocked       bool     `json:"auto_locked"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Schedule returns the budget as a category budget, so the period, phasing
+// and lock expiry rules of category budgets apply unchanged.
+func (b *CostCenterBudget) Schedule() *Budget {
+	return &Budget{
+		ID:       b.ID,
+		Amount:   b.Amount,
+		Year:     b.Year,
+		Period:   b.Period,
+		Phasing:  b.Phasing,
+		IsLocked: b.IsLocked,
+		LockedAt: b.LockedAt,
+	}
+}
+
+type CostCenterBudgetRequest struct {
+	CostCenterID int          `json:"cost_center_id"`
+	Year         int          `json:"year"`
+	Amount       Money        `json:"amount"`
+	Period       BudgetPeriod `json:"period,omitempty"`
+	Phasing      []Money      `json:"phasing,omitempty"`
+}
+
+// CostCenterMonitoringItem is the annual spend against budget for one cost
+// center, plus the figures of the budget's current period; BudgetID is 0 when
+// the cost center has spend but no budget.
+type CostCenterMonitoringItem struct {
+	BudgetID       int          `json:"budget_id"`
+	CostCenterID   int          `json:"cost_center_id"`
+	CostCenterCode string       `json:"cost_center_code"`
+	CostCenterName string       `json:"cost_center_name"`
+	BudgetAmount   Money        `json:"budget_amount"`
+	SpentAmount    Money        `json:"spent_amount"`
+	Remaining      Money        `json:"remaining"`
+	Percentage     float64      `json:"percentage"`
+	IsLocked       bool         `json:"is_locked"`
+	Period         BudgetPeriod `json:"period"`
+
+	PeriodLabel        string    `json:"period_label,omitempty"`
+	PeriodStart        time.Time `json:"period_start"`
+	PeriodEnd          time.Time `json:"period_end"`
+	PeriodBudgetAmount Money     `json:"period_budget_amount"`
+	PeriodSpentAmount  Money     `json:"period_spent_amount"`
+	PeriodPercentage   float64   `json:"period_percentage"`
+
+	Phasing  []Money    `json:"-"`
+	LockedAt *time.Time `json:"-"`
+}
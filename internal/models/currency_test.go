@@ -0,0 +1,60 @@
+package models
+
+import "testing"
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "usd", want: "USD"},
+		{in: " eur ", want: "EUR"},
+		{in: "GBP", want: "GBP"},
+		{in: "", wantErr: true},
+		{in: "US", wantErr: true},
+		{in: "USDT", wantErr: true},
+		{in: "U$D", wantErr: true},
+		{in: "12A", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeCurrency(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NormalizeCurrency(%q) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("NormalizeCurrency(%q) = %q, %v, want %q", tt.in, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseRate(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "1.0856", want: "1.08560000"},
+		{in: " 0.5 ", want: "0.50000000"},
+		{in: "3/2", want: "1.50000000"},
+		{in: "0", wantErr: true},
+		{in: "-1.2", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseRate(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseRate(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil || got.FloatString(8) != tt.want {
+			t.Errorf("ParseRate(%q) = %v, %v, want %s", tt.in, got, err, tt.want)
+		}
+	}
+}
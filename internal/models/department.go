@@ -0,0 +1,65 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+type Department struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	ManagerID   *int      `json:"manager_id,omitempty"`
+	ManagerName string    `json:"manager_name,omitempty"`
+	MemberCount int       `json:"member_count"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type DepartmentRequest struct {
+	Name      string `json:"name"`
+	ManagerID *int   `json:"manager_id"`
+}
+
+func (r *DepartmentRequest) Validate() error {
+	r.Name = strings.TrimSpace(r.Name)
+	if r.Name == "" {
+		return errors.New("department name is required")
+	}
+	if r.ManagerID != nil && *r.ManagerID <= 0 {
+		return errors.New("manager ID must be greater than 0")
+	}
+	return nil
+}
+
+type UserDepartmentRequest struct {
+	UserID       int  `json:"user_id"`
+	DepartmentID *int `json:"department_id"`
+}
+
+// DepartmentBudget is an annual allocation for a department with its spend;
+// Committed is the total of expenses still awaiting approval.
+type DepartmentBudget struct {
+	ID             int     `json:"id"`
+	DepartmentID   int     `json:"department_id"`
+	DepartmentName string  `json:"department_name"`
+	Year           int     `json:"year"`
+	Amount         Money   `json:"amount"`
+	SpentAmount    Money   `json:"spent_amount"`
+	Committed      Money   `json:"committed"`
+	Remaining      Money   `json:"remaining"`
+	Percentage     float64 `json:"percentage"`
+}
+
+type DepartmentBudgetRequest struct {
+	DepartmentID int   `json:"department_id"`
+	Year         int   `json:"year"`
+	Amount       Money `json:"amount"`
+}
+
+type DepartmentSpending struct {
+	DepartmentID   *int   `json:"department_id"`
+	DepartmentName string `json:"department_name"`
+	TotalAmount    Money  `json:"total_amount"`
+	Count          int    `json:"count"`
+}
@@ -9,32 +9,130 @@ type Expense struct {
 	ID          int       `json:"id"`
 	CategoryID  int       `json:"category_id"`
 	UserID      *int      `json:"user_id,omitempty"`
-	Amount      float64   `json:"amount"`
+	Amount      Money     `json:"amount"`
 	ExpenseDate time.Time `json:"expense_date"`
 	Remarks     string    `json:"remarks"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 
+	Currency       string `json:"currency"`
+	OriginalAmount Money  `json:"original_amount"`
+	ExchangeRate   string `json:"exchange_rate"`
+
 	CategoryName string `json:"category_name,omitempty"`
 	UserName     string `json:"user_name,omitempty"`
+
+	Tags []string `json:"tags"`
+
+	CostCenterID   *int   `json:"cost_center_id,omitempty"`
+	CostCenterCode string `json:"cost_center_code,omitempty"`
+
+	DepartmentID   *int           `json:"department_id,omitempty"`
+	DepartmentName string         `json:"department_name,omitempty"`
+	ApprovalStatus ApprovalStatus `json:"approval_status"`
+	ApprovedBy     *int           `json:"approved_by,omitempty"`
+	ApprovedAt     *time.Time     `json:"approved_at,omitempty"`
+	ApprovalNote   string         `json:"approval_note,omitempty"`
+
+	VendorID      *int          `json:"vendor_id,omitempty"`
+	VendorName    string        `json:"vendor_name,omitempty"`
+	PaymentMethod PaymentMethod `json:"payment_method,omitempty"`
+
+	// PayoutBatchID is set once a reimbursable expense is in a payout batch.
+	PayoutBatchID *int `json:"payout_batch_id,omitempty"`
+
+	// ReconciliationStatus is reconciled once a bank statement line is
+	// matched to the expense.
+	ReconciliationStatus ReconciliationStatus `json:"reconciliation_status"`
+
+	// Amount is gross; the net/tax split is in the base currency and uses
+	// the rate as it was when the expense was entered.
+	TaxRateID      *int     `json:"tax_rate_id,omitempty"`
+	TaxRateName    string   `json:"tax_rate_name,omitempty"`
+	TaxRate        *float64 `json:"tax_rate,omitempty"`
+	TaxRecoverable *bool    `json:"tax_recoverable,omitempty"`
+	NetAmount      *Money   `json:"net_amount,omitempty"`
+	TaxAmount      *Money   `json:"tax_amount,omitempty"`
+}
+
+type ApprovalStatus string
+
+const (
+	ApprovalPending  ApprovalStatus = "pending"
+	ApprovalApproved ApprovalStatus = "approved"
+	ApprovalRejected ApprovalStatus = "rejected"
+)
+
+type ExpenseApprovalRequest struct {
+	Note string `json:"note"`
 }
 
 type ExpenseRequest struct {
-	CategoryID  int     `json:"category_id"`
-	UserID      int     `json:"user_id"`
-	Amount      float64 `json:"amount"`
-	ExpenseDate string  `json:"expense_date"`
-	Remarks     string  `json:"remarks"`
+	CategoryID  int      `json:"category_id"`
+	UserID      int      `json:"user_id"`
+	Amount      Money    `json:"amount"`
+	ExpenseDate string   `json:"expense_date"`
+	Remarks     string   `json:"remarks"`
+	Currency    string   `json:"currency,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+
+	CostCenterID *int `json:"cost_center_id,omitempty"`
+
+	// Vendor is the name as entered; it is resolved to VendorID, creating
+	// the vendor when no name or alias matches.
+	Vendor        string        `json:"vendor,omitempty"`
+	VendorID      *int          `json:"-"`
+	PaymentMethod PaymentMethod `json:"payment_method,omitempty"`
+
+	// TaxInclusive tells whether Amount includes the tax of TaxRateID (the
+	// default) or is the net amount the tax is added to.
+	TaxRateID    *int        `json:"tax_rate_id,omitempty"`
+	TaxInclusive *bool       `json:"tax_inclusive,omitempty"`
+	Tax          *ExpenseTax `json:"-"`
+
+	BaseAmount   Money  `json:"-"`
+	ExchangeRate string `json:"-"`
+
+	SuggestedCategoryID *int `json:"-"`
+	SuggestionRuleID    *int `json:"-"`
+
+	// ApprovedBy is set when the expense needs no approval (entered by an admin).
+	ApprovedBy *int `json:"-"`
+}
+
+// ExpenseTax is the tax applied to a new expense, split in the base currency.
+type ExpenseTax struct {
+	Rate      TaxRate
+	NetAmount Money
+	TaxAmount Money
 }
 
 type ExpenseFilter struct {
-	StartDate  string  `json:"start_date"`
-	EndDate    string  `json:"end_date"`
-	CategoryID int     `json:"category_id"`
-	UserID     int     `json:"user_id"`
-	SearchText string  `json:"search_text"`
-	MinAmount  float64 `json:"min_amount"`
-	MaxAmount  float64 `json:"max_amount"`
+	StartDate  string `json:"start_date"`
+	EndDate    string `json:"end_date"`
+	CategoryID int    `json:"category_id"`
+	UserID     int    `json:"user_id"`
+	SearchText string `json:"search_text"`
+	MinAmount  Money  `json:"min_amount"`
+	MaxAmount  Money  `json:"max_amount"`
+
+	// Tags restricts to expenses carrying any (TagMatchAny, the default) or
+	// all (TagMatchAll) of the given tags.
+	Tags     []string `json:"tags"`
+	TagMatch TagMatch `json:"tag_match"`
+
+	CostCenterID int `json:"cost_center_id"`
+
+	DepartmentID   int            `json:"department_id"`
+	ApprovalStatus ApprovalStatus `json:"approval_status"`
+	// VisibleDepartments limits a manager to the departments they manage.
+	// nil means no restriction; an empty slice matches nothing.
+	VisibleDepartments []int `json:"-"`
+
+	VendorID      int           `json:"vendor_id"`
+	PaymentMethod PaymentMethod `json:"payment_method"`
+
+	Reconciliation ReconciliationStatus `json:"reconciliation"`
 }
 
 func (f *ExpenseFilter) Validate() error {
@@ -48,32 +146,61 @@ func (f *ExpenseFilter) Validate() error {
 	if f.MinAmount > 0 && f.MaxAmount > 0 && f.MinAmount > f.MaxAmount {
 		return fmt.Errorf("minimum amount must be less than or equal to maximum amount")
 	}
-	return nil
+	if f.TagMatch == "" {
+		f.TagMatch = TagMatchAny
+	}
+	if f.TagMatch != TagMatchAny && f.TagMatch != TagMatchAll {
+		return fmt.Errorf("tag match must be any or all")
+	}
+	tags, err := NormalizeTags(f.Tags)
+	if err != nil {
+		return err
+	}
+	f.Tags = tags
+	switch f.ApprovalStatus {
+	case "", ApprovalPending, ApprovalApproved, ApprovalRejected:
+	default:
+		return fmt.Errorf("status must be pending, approved or rejected")
+	}
+	switch f.Reconciliation {
+	case "", Reconciled, Unreconciled:
+	default:
+		return fmt.Errorf("reconciliation must be reconciled or unreconciled")
+	}
+	return f.PaymentMethod.Validate()
 }
 
 type CategorySpending struct {
-	CategoryID   int     `json:"category_id"`
-	CategoryName string  `json:"category_name"`
-	TotalAmount  float64 `json:"total_amount"`
-	Count        int     `json:"count"`
+	CategoryID   int    `json:"category_id"`
+	CategoryName string `json:"category_name"`
+	TotalAmount  Money  `json:"total_amount"`
+	Count        int    `json:"count"`
 }
 
 type DaySpending struct {
-	DayOfWeek   int     `json:"day_of_week"`
-	DayName     string  `json:"day_name"`
-	TotalAmount float64 `json:"total_amount"`
-	Count       int     `json:"count"`
+	DayOfWeek   int    `json:"day_of_week"`
+	DayName     string `json:"day_name"`
+	TotalAmount Money  `json:"total_amount"`
+	Count       int    `json:"count"`
 }
 
 type ExpenseInsights struct {
-	TotalSpent       float64 `json:"total_spent"`
-	TransactionCount int     `json:"transaction_count"`
-	AverageExpense   float64 `json:"average_expense"`
+	TotalSpent       Money `json:"total_spent"`
+	TransactionCount int   `json:"transaction_count"`
+	AverageExpense   Money `json:"average_expense"`
 
-	PreviousPeriodTotal float64 `json:"previous_period_total"`
+	PreviousPeriodTotal Money   `json:"previous_period_total"`
 	SpendingChange      float64 `json:"spending_change"`
 
 	TopCategories []CategorySpending `json:"top_categories"`
+	// TopLevelCategories rolls subcategory spend up to each root category.
+	TopLevelCategories []CategorySpending `json:"top_level_categories"`
+
+	TopTags []TagSpending `json:"top_tags"`
+
+	TopDepartments []DepartmentSpending `json:"top_departments"`
+
+	TopVendors []VendorSpending `json:"top_vendors"`
 
 	SpendingByDay []DaySpending `json:"spending_by_day"`
 }
@@ -0,0 +1,82 @@
+package models
+
+import (
+	"fmt"
+	"time"
+)
+
+// FiscalCalendar maps dates onto fiscal years. A fiscal year is labelled by
+// the calendar year it starts in, so with a July start FY2026 runs from
+// 1 July 2026 to 30 June 2027. A start month of 1 gives calendar years.
+type FiscalCalendar struct {
+	StartMonth int `json:"start_month"`
+}
+
+func NewFiscalCalendar(startMonth int) (FiscalCalendar, error) {
+	if startMonth < 1 || startMonth > 12 {
+		return FiscalCalendar{}, fmt.Errorf("fiscal year start month must be between 1 and 12, got %d", startMonth)
+	}
+	return FiscalCalendar{StartMonth: startMonth}, nil
+}
+
+func (c FiscalCalendar) startMonth() int {
+	if c.StartMonth < 1 || c.StartMonth > 12 {
+		return 1
+	}
+	return c.StartMonth
+}
+
+// YearOf returns the fiscal year containing date.
+func (c FiscalCalendar) YearOf(date time.Time) int {
+	year := date.Year()
+	if int(date.Month()) < c.startMonth() {
+		year--
+	}
+	return year
+}
+
+// MonthOf returns the position (1-12) of date's month within its fiscal year.
+func (c FiscalCalendar) MonthOf(date time.Time) int {
+	return (int(date.Month())-c.startMonth()+12)%12 + 1
+}
+
+// MonthStart returns the first day of fiscal month (1-based, may exceed 12)
+// of fiscal year.
+func (c FiscalCalendar) MonthStart(year, month int) time.Time {
+	return time.Date(year, time.Month(c.startMonth()+month-1), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// Bounds returns the first and last day of fiscal year.
+func (c FiscalCalendar) Bounds(year int) (time.Time, time.Time) {
+	return c.MonthStart(year, 1), c.MonthStart(year, 13).AddDate(0, 0, -1)
+}
+
+func (c FiscalCalendar) Label(year int) string {
+	if c.startMonth() == 1 {
+		return fmt.Sprintf("%d", year)
+	}
+	return fmt.Sprintf("FY%d", year)
+}
+
+func (c FiscalCalendar) CurrentYear() int {
+	return c.YearOf(time.Now())
+}
+
+type FiscalYearInfo struct {
+	StartMonth  int       `json:"start_month"`
+	CurrentYear int       `json:"current_year"`
+	Label       string    `json:"label"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+}
+
+func (c FiscalCalendar) Info(year int) FiscalYearInfo {
+	start, end := c.Bounds(year)
+	return FiscalYearInfo{
+		StartMonth:  c.startMonth(),
+		CurrentYear: year,
+		Label:       c.Label(year),
+		Start:       start,
+		End:         end,
+	}
+}
@@ -0,0 +1,97 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func utcDate(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestNewFiscalCalendar(t *testing.T) {
+	for _, month := range []int{1, 7, 12} {
+		if _, err := NewFiscalCalendar(month); err != nil {
+			t.Errorf("NewFiscalCalendar(%d) returned error: %v", month, err)
+		}
+	}
+	for _, month := range []int{0, 13, -1} {
+		if _, err := NewFiscalCalendar(month); err == nil {
+			t.Errorf("NewFiscalCalendar(%d) succeeded, want error", month)
+		}
+	}
+}
+
+func TestFiscalCalendarYearAndMonthOf(t *testing.T) {
+	tests := []struct {
+		start     int
+		date      time.Time
+		wantYear  int
+		wantMonth int
+	}{
+		{1, utcDate(2026, time.January, 1), 2026, 1},
+		{1, utcDate(2026, time.December, 31), 2026, 12},
+		{7, utcDate(2026, time.June, 30), 2025, 12},
+		{7, utcDate(2026, time.July, 1), 2026, 1},
+		{7, utcDate(2027, time.January, 15), 2026, 7},
+		{4, utcDate(2026, time.March, 31), 2025, 12},
+		{4, utcDate(2026, time.April, 1), 2026, 1},
+		{12, utcDate(2026, time.November, 30), 2025, 12},
+		{12, utcDate(2026, time.December, 1), 2026, 1},
+		{0, utcDate(2026, time.May, 1), 2026, 5}, // unset falls back to January
+	}
+	for _, tt := range tests {
+		c := FiscalCalendar{StartMonth: tt.start}
+		if got := c.YearOf(tt.date); got != tt.wantYear {
+			t.Errorf("start %d: YearOf(%s) = %d, want %d", tt.start, tt.date.Format("2006-01-02"), got, tt.wantYear)
+		}
+		if got := c.MonthOf(tt.date); got != tt.wantMonth {
+			t.Errorf("start %d: MonthOf(%s) = %d, want %d", tt.start, tt.date.Format("2006-01-02"), got, tt.wantMonth)
+		}
+	}
+}
+
+func TestFiscalCalendarBounds(t *testing.T) {
+	tests := []struct {
+		start     int
+		year      int
+		wantStart time.Time
+		wantEnd   time.Time
+		wantLabel string
+	}{
+		{1, 2026, utcDate(2026, time.January, 1), utcDate(2026, time.December, 31), "2026"},
+		{7, 2026, utcDate(2026, time.July, 1), utcDate(2027, time.June, 30), "FY2026"},
+		{3, 2027, utcDate(2027, time.March, 1), utcDate(2028, time.February, 29), "FY2027"},
+		{12, 2026, utcDate(2026, time.December, 1), utcDate(2027, time.November, 30), "FY2026"},
+	}
+	for _, tt := range tests {
+		c := FiscalCalendar{StartMonth: tt.start}
+		start, end := c.Bounds(tt.year)
+		if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+			t.Errorf("start %d: Bounds(%d) = %s..%s, want %s..%s", tt.start, tt.year,
+				start.Format("2006-01-02"), end.Format("2006-01-02"), tt.wantStart.Format("2006-01-02"), tt.wantEnd.Format("2006-01-02"))
+		}
+		if got := c.Label(tt.year); got != tt.wantLabel {
+			t.Errorf("start %d: Label(%d) = %q, want %q", tt.start, tt.year, got, tt.wantLabel)
+		}
+	}
+}
+
+func TestFiscalCalendarMonthStart(t *testing.T) {
+	c := FiscalCalendar{StartMonth: 10}
+	tests := []struct {
+		month int
+		want  time.Time
+	}{
+		{1, utcDate(2026, time.October, 1)},
+		{3, utcDate(2026, time.December, 1)},
+		{4, utcDate(2027, time.January, 1)},
+		{12, utcDate(2027, time.September, 1)},
+		{13, utcDate(2027, time.October, 1)},
+	}
+	for _, tt := range tests {
+		if got := c.MonthStart(2026, tt.month); !got.Equal(tt.want) {
+			t.Errorf("MonthStart(2026, %d) = %s, want %s", tt.month, got.Format("2006-01-02"), tt.want.Format("2006-01-02"))
+		}
+	}
+}
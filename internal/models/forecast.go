@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+type ForecastMethod string
+
+const (
+	ForecastSeasonal ForecastMethod = "seasonal"
+	ForecastRunRate  ForecastMethod = "run_rate"
+	ForecastHistory  ForecastMethod = "history"
+	ForecastActual   ForecastMethod = "actual"
+)
+
+type CategoryForecast struct {
+	Method             ForecastMethod `json:"method"`
+	ProjectedSpend     Money          `json:"projected_spend"`
+	ProjectedRemaining Money          `json:"projected_remaining"`
+	ProjectedPercent   float64        `json:"projected_percent"`
+	WillExceed         bool           `json:"will_exceed"`
+	ExceedDate         *time.Time     `json:"exceed_date,omitempty"`
+	MonthlyProjection  []Money        `json:"monthly_projection"`
+}
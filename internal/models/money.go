@@ -0,0 +1,247 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Money is an exact amount in minor units (cents), matching the DECIMAL(12,2)
+// columns in the database. Values with more than two decimals are rounded
+// half away from zero when parsed.
+type Money int64
+
+const CentsPerUnit = 100
+
+func MoneyFromUnits(units int64) Money {
+	return Money(units * CentsPerUnit)
+}
+
+func MoneyFromFloat(f float64) Money {
+	return Money(math.Round(f * CentsPerUnit))
+}
+
+func ParseMoney(s string) (Money, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("invalid amount: empty")
+	}
+
+	if strings.ContainsAny(s, "eE") {
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return 0, fmt.Errorf("invalid amount: %q", s)
+		}
+		return MoneyFromFloat(f), nil
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, fmt.Errorf("invalid amount: %q", s)
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, fmt.Errorf("invalid amount: %q", s)
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/CentsPerUnit-1 {
+		return 0, fmt.Errorf("invalid amount: %q", s)
+	}
+
+	var cents int64
+	if len(frac) > 0 {
+		padded := (frac + "00")[:2]
+		cents, _ = strconv.ParseInt(padded, 10, 64)
+		if len(frac) > 2 && frac[2] >= '5' {
+			cents++
+		}
+	}
+
+	total := units*CentsPerUnit + cents
+	if negative {
+		total = -total
+	}
+	return Money(total), nil
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/CentsPerUnit, v%CentsPerUnit)
+}
+
+func (m Money) Float64() float64 {
+	return float64(m) / CentsPerUnit
+}
+
+// Div splits m into n parts, rounding half away from zero.
+func (m Money) Div(n int64) Money {
+	if n == 0 {
+		return 0
+	}
+	q := int64(m) / n
+	r := int64(m) % n
+	if r < 0 {
+		r = -r
+	}
+	if 2*r >= abs64(n) {
+		if (int64(m) < 0) != (n < 0) {
+			q--
+		} else {
+			q++
+		}
+	}
+	return Money(q)
+}
+
+// MulRatio scales m by num/den, rounding half away from zero. Products that
+// do not fit in int64 are computed exactly (see MulRat).
+func (m Money) MulRatio(num, den int64) Money {
+	if den == 0 {
+		return 0
+	}
+	if num != 0 && (m == math.MinInt64 || abs64(int64(m)) > math.MaxInt64/abs64(num)) {
+		return m.MulRat(big.NewRat(num, den))
+	}
+	return Money(int64(m) * num).Div(den)
+}
+
+// PercentOf returns m as a percentage of total, rounded to two decimals.
+func (m Money) PercentOf(total Money) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(m)*10000/float64(total)) / 100
+}
+
+// MulRat scales m by an exact rational (e.g. an exchange rate), rounding half
+// away from zero.
+func (m Money) MulRat(r *big.Rat) Money {
+	product := new(big.Rat).Mul(new(big.Rat).SetInt64(int64(m)), r)
+	num := product.Num()
+	den := product.Denom()
+
+	q, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	rem.Abs(rem).Mul(rem, big.NewInt(2))
+	if rem.Cmp(den) >= 0 {
+		if num.Sign() < 0 {
+			q.Sub(q, big.NewInt(1))
+		} else {
+			q.Add(q, big.NewInt(1))
+		}
+	}
+	// Out-of-range results saturate rather than wrap around, so they are
+	// still rejected by the DECIMAL(12,2) columns instead of stored wrong.
+	if !q.IsInt64() {
+		if q.Sign() < 0 {
+			return Money(math.MinInt64)
+		}
+		return Money(math.MaxInt64)
+	}
+	return Money(q.Int64())
+}
+
+func ParseRate(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok || r.Sign() <= 0 {
+		return nil, fmt.Errorf("invalid rate %q", s)
+	}
+	return r, nil
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// MarshalJSON encodes m as a JSON number with exactly two decimals so existing
+// clients that expect numeric amounts keep working.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+func (m *Money) UnmarshalJSON(data []byte) error {
+	s := strings.TrimSpace(string(data))
+	if s == "null" {
+		*m = 0
+		return nil
+	}
+	if strings.HasPrefix(s, `"`) {
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return err
+		}
+		s = str
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.String(), nil
+}
+
+func (m *Money) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case []byte:
+		parsed, err := ParseMoney(string(v))
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case string:
+		parsed, err := ParseMoney(v)
+		if err != nil {
+			return err
+		}
+		*m = parsed
+		return nil
+	case int64:
+		*m = MoneyFromUnits(v)
+		return nil
+	case float64:
+		*m = MoneyFromFloat(v)
+		return nil
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+}
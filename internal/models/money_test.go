@@ -0,0 +1,186 @@
+package models
+
+import (
+	"encoding/json"
+	"math"
+	"math/big"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Money
+		wantErr bool
+	}{
+		{in: "0", want: 0},
+		{in: "12", want: 1200},
+		{in: "12.3", want: 1230},
+		{in: "12.34", want: 1234},
+		{in: " 12.34 ", want: 1234},
+		{in: "+5.00", want: 500},
+		{in: "-5.25", want: -525},
+		{in: ".5", want: 50},
+		{in: "7.", want: 700},
+		{in: "0.005", want: 1},
+		{in: "0.004", want: 0},
+		{in: "1.999", want: 200},
+		{in: "-0.005", want: -1},
+		{in: "1e2", want: 10000},
+		{in: "", wantErr: true},
+		{in: "-", wantErr: true},
+		{in: "+", wantErr: true},
+		{in: ".", wantErr: true},
+		{in: "-.", wantErr: true},
+		{in: "1,50", wantErr: true},
+		{in: "abc", wantErr: true},
+		{in: "1.2.3", wantErr: true},
+		{in: "--1", wantErr: true},
+		{in: "99999999999999999999", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("ParseMoney(%q) = %v, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseMoney(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseMoney(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		in   Money
+		want string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{1234, "12.34"},
+		{-1234, "-12.34"},
+		{-5, "-0.05"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyDiv(t *testing.T) {
+	tests := []struct {
+		m    Money
+		n    int64
+		want Money
+	}{
+		{1000, 4, 250},
+		{1000, 3, 333},
+		{200, 3, 67},
+		{5, 2, 3},
+		{-5, 2, -3},
+		{5, -2, -3},
+		{-5, -2, 3},
+		{100, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.m.Div(tt.n); got != tt.want {
+			t.Errorf("Money(%d).Div(%d) = %d, want %d", tt.m, tt.n, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMulRatio(t *testing.T) {
+	tests := []struct {
+		name     string
+		m        Money
+		num, den int64
+		want     Money
+	}{
+		{"simple", 1000, 1, 4, 250},
+		{"rounds half up", 1, 1, 2, 1},
+		{"rounds half away from zero", -1, 1, 2, -1},
+		{"zero denominator", 1000, 1, 0, 0},
+		{"large product stays exact", Money(math.MaxInt64 / 2), 4, 8, Money(math.MaxInt64 / 2).Div(2)},
+		{"overflowing result saturates", Money(math.MaxInt64 / 2), 4, 1, Money(math.MaxInt64)},
+		{"negative overflow saturates", Money(math.MinInt64 / 2), 4, 1, Money(math.MinInt64)},
+	}
+	for _, tt := range tests {
+		if got := tt.m.MulRatio(tt.num, tt.den); got != tt.want {
+			t.Errorf("%s: Money(%d).MulRatio(%d, %d) = %d, want %d", tt.name, tt.m, tt.num, tt.den, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyMulRat(t *testing.T) {
+	tests := []struct {
+		m    Money
+		rate string
+		want Money
+	}{
+		{10000, "1.1", 11000},
+		{10000, "0.85", 8500},
+		{333, "0.5", 167},
+		{-333, "0.5", -167},
+		{100, "1/3", 33},
+	}
+	for _, tt := range tests {
+		r, ok := new(big.Rat).SetString(tt.rate)
+		if !ok {
+			t.Fatalf("bad rate %q", tt.rate)
+		}
+		if got := tt.m.MulRat(r); got != tt.want {
+			t.Errorf("Money(%d).MulRat(%s) = %d, want %d", tt.m, tt.rate, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyPercentOf(t *testing.T) {
+	tests := []struct {
+		m, total Money
+		want     float64
+	}{
+		{50, 200, 25},
+		{1, 3, 33.33},
+		{2, 3, 66.67},
+		{100, 0, 0},
+	}
+	for _, tt := range tests {
+		if got := tt.m.PercentOf(tt.total); got != tt.want {
+			t.Errorf("Money(%d).PercentOf(%d) = %v, want %v", tt.m, tt.total, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var v struct {
+		Amount Money `json:"amount"`
+	}
+	for _, in := range []string{`{"amount": 12.5}`, `{"amount": "12.50"}`} {
+		if err := json.Unmarshal([]byte(in), &v); err != nil {
+			t.Fatalf("Unmarshal(%s) returned error: %v", in, err)
+		}
+		if v.Amount != 1250 {
+			t.Errorf("Unmarshal(%s) = %d, want 1250", in, v.Amount)
+		}
+	}
+	if err := json.Unmarshal([]byte(`{"amount": "-"}`), &v); err == nil {
+		t.Error(`Unmarshal("-") succeeded, want error`)
+	}
+
+	out, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+	}{Amount: 1250})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"amount":12.50}` {
+		t.Errorf("Marshal = %s, want {\"amount\":12.50}", out)
+	}
+}
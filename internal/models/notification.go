@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type NotificationType string
+
+const NotificationBudgetThreshold NotificationType = "budget_threshold"
+
+type Notification struct {
+	ID        int              `json:"id"`
+	UserID    int              `json:"user_id"`
+	Type      NotificationType `json:"type"`
+	Title     string           `json:"title"`
+	Message   string           `json:"message"`
+	BudgetID  *int             `json:"budget_id,omitempty"`
+	ReadAt    *time.Time       `json:"read_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type NotificationFeed struct {
+	Notifications []Notification `json:"notifications"`
+	UnreadCount   int            `json:"unread_count"`
+}
+
+type BudgetAlertRequest struct {
+	Thresholds []float64 `json:"thresholds"`
+}
@@ -0,0 +1,174 @@
+package models
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"time"
+)
+
+type PayoutStatus string
+
+const (
+	PayoutOpen PayoutStatus = "open"
+	PayoutPaid PayoutStatus = "paid"
+)
+
+// PayoutBatch is one reimbursement transfer to a user, covering the approved
+// personally paid expenses grouped into it.
+type PayoutBatch struct {
+	ID               int          `json:"id"`
+	UserID           int          `json:"user_id"`
+	UserName         string       `json:"user_name"`
+	Status           PayoutStatus `json:"status"`
+	TotalAmount      Money        `json:"total_amount"`
+	ExpenseCount     int          `json:"expense_count"`
+	Currency         string       `json:"currency"`
+	CreatedBy        *int         `json:"created_by,omitempty"`
+	CreatedAt        time.Time    `json:"created_at"`
+	PaidBy           *int         `json:"paid_by,omitempty"`
+	PaidAt           *time.Time   `json:"paid_at,omitempty"`
+	PaymentReference string       `json:"payment_reference"`
+
+	Expenses    []Expense    `json:"expenses,omitempty"`
+	BankAccount *BankAccount `json:"bank_account,omitempty"`
+}
+
+// PayoutBatchRequest creates batches from approved reimbursable expenses not
+// yet in a batch, optionally only for some users or up to a date.
+type PayoutBatchRequest struct {
+	UserIDs []int  `json:"user_ids"`
+	EndDate string `json:"end_date"`
+}
+
+func (r *PayoutBatchRequest) Validate() error {
+	if r.EndDate != "" {
+		if _, err := time.Parse("2006-01-02", r.EndDate); err != nil {
+			return errors.New("end date must be in YYYY-MM-DD format")
+		}
+	}
+	for _, id := range r.UserIDs {
+		if id <= 0 {
+			return errors.New("user IDs must be greater than 0")
+		}
+	}
+	return nil
+}
+
+type PayoutPaidRequest struct {
+	PaymentReference string `json:"payment_reference"`
+}
+
+// PendingReimbursement sums a user's approved reimbursable expenses that are
+// not in a payout batch yet.
+type PendingReimbursement struct {
+	UserID         int    `json:"user_id"`
+	UserName       string `json:"user_name"`
+	ExpenseCount   int    `json:"expense_count"`
+	TotalAmount    Money  `json:"total_amount"`
+	HasBankAccount bool   `json:"has_bank_account"`
+}
+
+type BankAccount struct {
+	UserID        int       `json:"user_id"`
+	AccountHolder string    `json:"account_holder"`
+	IBAN          string    `json:"iban"`
+	BIC           string    `json:"bic,omitempty"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+type BankAccountRequest struct {
+	UserID        int    `json:"user_id"`
+	AccountHolder string `json:"account_holder"`
+	IBAN          string `json:"iban"`
+	BIC           string `json:"bic"`
+}
+
+func (r *BankAccountRequest) Validate() error {
+	if r.UserID <= 0 {
+		return errors.New("user ID must be greater than 0")
+	}
+	r.AccountHolder = strings.Join(strings.Fields(r.AccountHolder), " ")
+	if r.AccountHolder == "" {
+		return errors.New("account holder is required")
+	}
+	if len(r.AccountHolder) > 140 {
+		return errors.New("account holder must be at most 140 characters")
+	}
+	iban, err := NormalizeIBAN(r.IBAN)
+	if err != nil {
+		return err
+	}
+	r.IBAN = iban
+	bic, err := NormalizeBIC(r.BIC)
+	if err != nil {
+		return err
+	}
+	r.BIC = bic
+	return nil
+}
+
+// PayoutSettings is the company account reimbursements are paid from.
+type PayoutSettings struct {
+	DebtorName string `json:"debtor_name"`
+	DebtorIBAN string `json:"debtor_iban"`
+	DebtorBIC  string `json:"debtor_bic"`
+}
+
+func (s *PayoutSettings) Validate() error {
+	s.DebtorName = strings.Join(strings.Fields(s.DebtorName), " ")
+	if s.DebtorName == "" {
+		return errors.New("debtor name is required")
+	}
+	if len(s.DebtorName) > 140 {
+		return errors.New("debtor name must be at most 140 characters")
+	}
+	iban, err := NormalizeIBAN(s.DebtorIBAN)
+	if err != nil {
+		return err
+	}
+	s.DebtorIBAN = iban
+	bic, err := NormalizeBIC(s.DebtorBIC)
+	if err != nil {
+		return err
+	}
+	s.DebtorBIC = bic
+	return nil
+}
+
+// NormalizeIBAN removes spaces, upper-cases and verifies the ISO 13616
+// mod-97 check digits.
+func NormalizeIBAN(iban string) (string, error) {
+	iban = strings.ToUpper(strings.Join(strings.Fields(iban), ""))
+	if iban == "" {
+		return "", errors.New("IBAN is required")
+	}
+	if len(iban) < 15 || len(iban) > 34 {
+		return "", errors.New("IBAN must be between 15 and 34 characters")
+	}
+	var digits strings.Builder
+	for _, c := range iban[4:] + iban[:4] {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c >= 'A' && c <= 'Z':
+			digits.WriteString(big.NewInt(int64(c-'A') + 10).String())
+		default:
+			return "", errors.New("IBAN must contain only letters and digits")
+		}
+	}
+	n, _ := new(big.Int).SetString(digits.String(), 10)
+	if new(big.Int).Mod(n, big.NewInt(97)).Int64() != 1 {
+		return "", errors.New("IBAN must have valid check digits")
+	}
+	return iban, nil
+}
+
+// NormalizeBIC upper-cases an optional BIC and checks its length.
+func NormalizeBIC(bic string) (string, error) {
+	bic = strings.ToUpper(strings.TrimSpace(bic))
+	if bic != "" && len(bic) != 8 && len(bic) != 11 {
+		return "", errors.New("BIC must be 8 or 11 characters")
+	}
+	return bic, nil
+}
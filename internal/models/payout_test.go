@@ -0,0 +1,58 @@
+package models
+
+import "testing"
+
+func TestNormalizeIBAN(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "DE89370400440532013000", want: "DE89370400440532013000"},
+		{in: "de89 3704 0044 0532 0130 00", want: "DE89370400440532013000"},
+		{in: "GB82 WEST 1234 5698 7654 32", want: "GB82WEST12345698765432"},
+		{in: "", wantErr: true},
+		{in: "DE89", wantErr: true},
+		{in: "DE88370400440532013000", wantErr: true},
+		{in: "DE89-3704-0044-0532-0130-00", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeIBAN(tt.in)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("NormalizeIBAN(%q) = %q, want error", tt.in, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("NormalizeIBAN(%q) returned error: %v", tt.in, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeIBAN(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestNormalizeBIC(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    string
+		wantErr bool
+	}{
+		{in: "", want: ""},
+		{in: " cobadeff ", want: "COBADEFF"},
+		{in: "COBADEFFXXX", want: "COBADEFFXXX"},
+		{in: "COBADE", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeBIC(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeBIC(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("NormalizeBIC(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// VarianceLine compares budgeted and actual spend for one fiscal month.
+// Variance is budgeted minus actual, so a negative variance is an overspend.
+type VarianceLine struct {
+	Month           int       `json:"month"`
+	Label           string    `json:"label"`
+	Start           time.Time `json:"start"`
+	End             time.Time `json:"end"`
+	Budgeted        Money     `json:"budgeted"`
+	Actual          Money     `json:"actual"`
+	Variance        Money     `json:"variance"`
+	VariancePercent float64   `json:"variance_percent"`
+
+	YTDBudgeted        Money   `json:"ytd_budgeted"`
+	YTDActual          Money   `json:"ytd_actual"`
+	YTDVariance        Money   `json:"ytd_variance"`
+	YTDVariancePercent float64 `json:"ytd_variance_percent"`
+}
+
+type CategoryVariance struct {
+	BudgetID         int            `json:"budget_id"`
+	CategoryID       int            `json:"category_id"`
+	CategoryName     string         `json:"category_name"`
+	ParentCategoryID *int           `json:"parent_category_id,omitempty"`
+	Depth            int            `json:"depth"`
+	RollUp           bool           `json:"roll_up"`
+	AnnualBudget     Money          `json:"annual_budget"`
+	Months           []VarianceLine `json:"months"`
+}
+
+type VarianceReport struct {
+	Year       int                `json:"year"`
+	Label      string             `json:"label"`
+	Categories []CategoryVariance `json:"categories"`
+	Totals     []VarianceLine     `json:"totals"`
+}
+
+type VarianceDrillDown struct {
+	CategoryID int       `json:"category_id"`
+	Year       int       `json:"year"`
+	Month      int       `json:"month"`
+	Label      string    `json:"label"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Total      Money     `json:"total"`
+	Expenses   []Expense `json:"expenses"`
+}
@@ -0,0 +1,144 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+type StatementFormat string
+
+const (
+	StatementCSV     StatementFormat = "csv"
+	StatementOFX     StatementFormat = "ofx"
+	StatementCAMT053 StatementFormat = "camt053"
+)
+
+type BankStatement struct {
+	ID               int             `json:"id"`
+	Filename         string          `json:"filename"`
+	Format           StatementFormat `json:"format"`
+	AccountReference string          `json:"account_reference"`
+	ImportedBy       *int            `json:"imported_by,omitempty"`
+	ImportedAt       time.Time       `json:"imported_at"`
+	LineCount        int             `json:"line_count"`
+	MatchedCount     int             `json:"matched_count"`
+	IgnoredCount     int             `json:"ignored_count"`
+}
+
+type StatementLineStatus string
+
+const (
+	LineUnmatched StatementLineStatus = "unmatched"
+	LineMatched   StatementLineStatus = "matched"
+	LineIgnored   StatementLineStatus = "ignored"
+)
+
+// StatementLine is one outgoing payment on an imported statement. Amount is
+// positive; incoming payments are not imported.
+type StatementLine struct {
+	ID           int                 `json:"id"`
+	StatementID  int                 `json:"statement_id"`
+	BookingDate  time.Time           `json:"booking_date"`
+	Amount       Money               `json:"amount"`
+	Currency     string              `json:"currency"`
+	Counterparty string              `json:"counterparty"`
+	Description  string              `json:"description"`
+	Reference    string              `json:"reference"`
+	Status       StatementLineStatus `json:"status"`
+	ExpenseID    *int                `json:"expense_id,omitempty"`
+	AutoMatched  bool                `json:"auto_matched"`
+	MatchedBy    *int                `json:"matched_by,omitempty"`
+	MatchedAt    *time.Time          `json:"matched_at,omitempty"`
+
+	// Candidates are the expenses the line could belong to, best first; only
+	// filled for unmatched lines in the review queue.
+	Candidates []MatchCandidate `json:"candidates,omitempty"`
+}
+
+// ParsedStatementLine is a statement line read from a file, before it is
+// stored. Amount is signed: negative is money leaving the account.
+type ParsedStatementLine struct {
+	BookingDate  time.Time
+	Amount       Money
+	Currency     string
+	Counterparty string
+	Description  string
+	Reference    string
+
+	// Fingerprint identifies the payment across overlapping imports.
+	Fingerprint string
+}
+
+type ParsedStatement struct {
+	AccountReference string
+	Lines            []ParsedStatementLine
+}
+
+// MatchCandidate is an unreconciled expense with the same amount and currency
+// as a statement line, booked within a few days of it.
+type MatchCandidate struct {
+	ExpenseID     int       `json:"expense_id"`
+	ExpenseDate   time.Time `json:"expense_date"`
+	Amount        Money     `json:"amount"`
+	Currency      string    `json:"currency"`
+	CategoryName  string    `json:"category_name"`
+	UserName      string    `json:"user_name"`
+	VendorName    string    `json:"vendor_name,omitempty"`
+	Remarks       string    `json:"remarks"`
+	VendorMatched bool      `json:"vendor_matched"`
+	Score         int       `json:"score"`
+
+	// VendorNames holds the vendor's normalized name and aliases.
+	VendorNames []string `json:"-"`
+}
+
+type StatementImportResult struct {
+	Statement   *BankStatement `json:"statement"`
+	Imported    int            `json:"imported"`
+	Duplicates  int            `json:"duplicates"`
+	Credits     int            `json:"credits"`
+	AutoMatched int            `json:"auto_matched"`
+	Errors      []string       `json:"errors,omitempty"`
+}
+
+type StatementLineFilter struct {
+	StatementID int                 `json:"statement_id"`
+	Status      StatementLineStatus `json:"status"`
+}
+
+func (f *StatementLineFilter) Validate() error {
+	switch f.Status {
+	case "", LineUnmatched, LineMatched, LineIgnored:
+		return nil
+	}
+	return errors.New("status must be unmatched, matched or ignored")
+}
+
+type StatementMatchRequest struct {
+	ExpenseID int `json:"expense_id"`
+}
+
+func (r *StatementMatchRequest) Validate() error {
+	if r.ExpenseID <= 0 {
+		return errors.New("expense ID must be greater than 0")
+	}
+	return nil
+}
+
+// StatementExpenseRequest turns an unmatched line into an expense; amount,
+// currency, date and vendor come from the line.
+type StatementExpenseRequest struct {
+	CategoryID    int           `json:"category_id"`
+	Remarks       string        `json:"remarks"`
+	Tags          []string      `json:"tags,omitempty"`
+	CostCenterID  *int          `json:"cost_center_id,omitempty"`
+	PaymentMethod PaymentMethod `json:"payment_method,omitempty"`
+	Vendor        string        `json:"vendor,omitempty"`
+}
+
+type ReconciliationStatus string
+
+const (
+	Reconciled   ReconciliationStatus = "reconciled"
+	Unreconciled ReconciliationStatus = "unreconciled"
+)
@@ -0,0 +1,52 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	maxTagLength      = 50
+	maxTagsPerExpense = 20
+)
+
+type Tag struct {
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	UsageCount int    `json:"usage_count"`
+}
+
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = "any"
+	TagMatchAll TagMatch = "all"
+)
+
+type TagSpending struct {
+	Tag         string `json:"tag"`
+	TotalAmount Money  `json:"total_amount"`
+	Count       int    `json:"count"`
+}
+
+// NormalizeTags trims, lower-cases and de-duplicates tags, collapsing inner
+// whitespace, so "Client  ACME" and "client acme" are the same tag.
+func NormalizeTags(tags []string) ([]string, error) {
+	seen := map[string]bool{}
+	normalized := []string{}
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.Join(strings.Fields(tag), " "))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxTagLength {
+			return nil, fmt.Errorf("tags must be at most %d characters", maxTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxTagsPerExpense {
+		return nil, fmt.Errorf("an expense must be given at most %d tags", maxTagsPerExpense)
+	}
+	return normalized, nil
+}
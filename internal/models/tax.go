@@ -0,0 +1,156 @@
+package models
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+)
+
+// TaxRate is a VAT or sales tax rate in percent. Tax on a non-recoverable
+// rate cannot be reclaimed and stays part of the cost.
+type TaxRate struct {
+	ID          int       `json:"id"`
+	Name        string    `json:"name"`
+	Rate        float64   `json:"rate"`
+	Recoverable bool      `json:"recoverable"`
+	IsActive    bool      `json:"is_active"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type TaxRateRequest struct {
+	Name        string  `json:"name"`
+	Rate        float64 `json:"rate"`
+	Recoverable *bool   `json:"recoverable"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+func (r *TaxRateRequest) Validate() error {
+	r.Name = strings.Join(strings.Fields(r.Name), " ")
+	if r.Name == "" {
+		return errors.New("tax rate name is required")
+	}
+	if len(r.Name) > 100 {
+		return errors.New("tax rate name must be at most 100 characters")
+	}
+	if r.Rate < 0 || r.Rate >= 100 {
+		return errors.New("rate must be between 0 and 100 percent")
+	}
+	if math.Abs(r.Rate*100-math.Round(r.Rate*100)) > 1e-6 {
+		return errors.New("rate must have at most two decimals")
+	}
+	return nil
+}
+
+// basisPoints is the rate in hundredths of a percent.
+func (t TaxRate) basisPoints() int64 {
+	return int64(math.Round(t.Rate * 100))
+}
+
+// SplitGross splits a tax-inclusive amount into net and tax.
+func (t TaxRate) SplitGross(gross Money) (net, tax Money) {
+	bp := t.basisPoints()
+	tax = gross.MulRatio(bp, 10000+bp)
+	return gross - tax, tax
+}
+
+// AddTax returns the gross amount and tax for a tax-exclusive amount.
+func (t TaxRate) AddTax(net Money) (gross, tax Money) {
+	tax = net.MulRatio(t.basisPoints(), 10000)
+	return net + tax, tax
+}
+
+// VATReportRequest selects the report period: a calendar month or quarter of
+// Year, the whole year, or explicit dates (which take precedence).
+type VATReportRequest struct {
+	Year      int    `json:"year"`
+	Quarter   int    `json:"quarter"`
+	Month     int    `json:"month"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+}
+
+// Bounds returns the first and last day of the requested period.
+func (r VATReportRequest) Bounds() (time.Time, time.Time, error) {
+	if r.StartDate != "" || r.EndDate != "" {
+		start, err := time.Parse("2006-01-02", r.StartDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("start date must be in YYYY-MM-DD format")
+		}
+		end, err := time.Parse("2006-01-02", r.EndDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, errors.New("end date must be in YYYY-MM-DD format")
+		}
+		if start.After(end) {
+			return time.Time{}, time.Time{}, errors.New("start date must be before or equal to end date")
+		}
+		return start, end, nil
+	}
+
+	if r.Year < 2000 || r.Year > 2100 {
+		return time.Time{}, time.Time{}, errors.New("year must be between 2000 and 2100")
+	}
+	switch {
+	case r.Quarter != 0 && r.Month != 0:
+		return time.Time{}, time.Time{}, errors.New("quarter and month must not both be set")
+	case r.Quarter != 0:
+		if r.Quarter < 1 || r.Quarter > 4 {
+			return time.Time{}, time.Time{}, errors.New("quarter must be between 1 and 4")
+		}
+		start := time.Date(r.Year, time.Month(3*r.Quarter-2), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 3, -1), nil
+	case r.Month != 0:
+		if r.Month < 1 || r.Month > 12 {
+			return time.Time{}, time.Time{}, errors.New("month must be between 1 and 12")
+		}
+		start := time.Date(r.Year, time.Month(r.Month), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, -1), nil
+	}
+	start := time.Date(r.Year, time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, -1), nil
+}
+
+// VATReportLine sums approved expenses of one category at one rate.
+type VATReportLine struct {
+	TaxRateID      int     `json:"tax_rate_id"`
+	TaxRateName    string  `json:"tax_rate_name"`
+	Rate           float64 `json:"rate"`
+	CategoryID     int     `json:"category_id"`
+	CategoryName   string  `json:"category_name"`
+	ExpenseCount   int     `json:"expense_count"`
+	NetAmount      Money   `json:"net_amount"`
+	TaxAmount      Money   `json:"tax_amount"`
+	GrossAmount    Money   `json:"gross_amount"`
+	RecoverableTax Money   `json:"recoverable_tax"`
+}
+
+// VATRateTotal sums a rate over all categories.
+type VATRateTotal struct {
+	TaxRateID      int     `json:"tax_rate_id"`
+	TaxRateName    string  `json:"tax_rate_name"`
+	Rate           float64 `json:"rate"`
+	ExpenseCount   int     `json:"expense_count"`
+	NetAmount      Money   `json:"net_amount"`
+	TaxAmount      Money   `json:"tax_amount"`
+	GrossAmount    Money   `json:"gross_amount"`
+	RecoverableTax Money   `json:"recoverable_tax"`
+}
+
+// VATReport lists input tax on approved expenses dated in the period, in the
+// base currency. Approved expenses without a tax rate are counted separately.
+type VATReport struct {
+	StartDate string          `json:"start_date"`
+	EndDate   string          `json:"end_date"`
+	Currency  string          `json:"currency"`
+	Lines     []VATReportLine `json:"lines"`
+	Rates     []VATRateTotal  `json:"rates"`
+
+	NetAmount      Money `json:"net_amount"`
+	TaxAmount      Money `json:"tax_amount"`
+	GrossAmount    Money `json:"gross_amount"`
+	RecoverableTax Money `json:"recoverable_tax"`
+
+	UntaxedCount  int   `json:"untaxed_count"`
+	UntaxedAmount Money `json:"untaxed_amount"`
+}
@@ -0,0 +1,121 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTaxRateSplitGross(t *testing.T) {
+	tests := []struct {
+		rate     float64
+		gross    Money
+		net, tax Money
+	}{
+		{19, 11900, 10000, 1900},
+		{19, 1000, 840, 160},
+		{7, 10700, 10000, 700},
+		{7.7, 10770, 10000, 770},
+		{20, 1, 1, 0},
+		{20, 6, 5, 1},
+		{0, 5000, 5000, 0},
+		{19, -11900, -10000, -1900},
+	}
+	for _, tt := range tests {
+		net, tax := TaxRate{Rate: tt.rate}.SplitGross(tt.gross)
+		if net != tt.net || tax != tt.tax {
+			t.Errorf("SplitGross(%d) at %v%% = %d + %d, want %d + %d", tt.gross, tt.rate, net, tax, tt.net, tt.tax)
+		}
+		if net+tax != tt.gross {
+			t.Errorf("SplitGross(%d) at %v%% does not add up", tt.gross, tt.rate)
+		}
+	}
+}
+
+func TestTaxRateAddTax(t *testing.T) {
+	tests := []struct {
+		rate       float64
+		net        Money
+		gross, tax Money
+	}{
+		{19, 10000, 11900, 1900},
+		{19, 842, 1002, 160},
+		{7.7, 10000, 10770, 770},
+		{20, 2, 2, 0},
+		{20, 3, 4, 1},
+		{0, 5000, 5000, 0},
+	}
+	for _, tt := range tests {
+		gross, tax := TaxRate{Rate: tt.rate}.AddTax(tt.net)
+		if gross != tt.gross || tax != tt.tax {
+			t.Errorf("AddTax(%d) at %v%% = %d with tax %d, want %d with tax %d", tt.net, tt.rate, gross, tax, tt.gross, tt.tax)
+		}
+	}
+}
+
+func TestTaxRateRequestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		req     TaxRateRequest
+		wantErr bool
+	}{
+		{"standard rate", TaxRateRequest{Name: "Standard VAT", Rate: 19}, false},
+		{"two decimals", TaxRateRequest{Name: "Swiss", Rate: 7.7}, false},
+		{"zero rate", TaxRateRequest{Name: "Exempt", Rate: 0}, false},
+		{"blank name", TaxRateRequest{Name: "   ", Rate: 19}, true},
+		{"negative rate", TaxRateRequest{Name: "Bad", Rate: -1}, true},
+		{"hundred percent", TaxRateRequest{Name: "Bad", Rate: 100}, true},
+		{"three decimals", TaxRateRequest{Name: "Bad", Rate: 7.125}, true},
+	}
+	for _, tt := range tests {
+		err := tt.req.Validate()
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: Validate() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	req := TaxRateRequest{Name: "  Reduced   VAT ", Rate: 7}
+	if err := req.Validate(); err != nil || req.Name != "Reduced VAT" {
+		t.Errorf("Validate() normalized name to %q (error %v), want %q", req.Name, err, "Reduced VAT")
+	}
+}
+
+func TestVATReportRequestBounds(t *testing.T) {
+	tests := []struct {
+		name       string
+		req        VATReportRequest
+		start, end string
+		wantErr    bool
+	}{
+		{name: "whole year", req: VATReportRequest{Year: 2026}, start: "2026-01-01", end: "2026-12-31"},
+		{name: "first quarter", req: VATReportRequest{Year: 2026, Quarter: 1}, start: "2026-01-01", end: "2026-03-31"},
+		{name: "fourth quarter", req: VATReportRequest{Year: 2026, Quarter: 4}, start: "2026-10-01", end: "2026-12-31"},
+		{name: "leap February", req: VATReportRequest{Year: 2028, Month: 2}, start: "2028-02-01", end: "2028-02-29"},
+		{name: "explicit dates win", req: VATReportRequest{Year: 2026, Quarter: 1, StartDate: "2026-05-03", EndDate: "2026-05-17"}, start: "2026-05-03", end: "2026-05-17"},
+		{name: "single day", req: VATReportRequest{StartDate: "2026-05-03", EndDate: "2026-05-03"}, start: "2026-05-03", end: "2026-05-03"},
+		{name: "missing year", req: VATReportRequest{}, wantErr: true},
+		{name: "quarter out of range", req: VATReportRequest{Year: 2026, Quarter: 5}, wantErr: true},
+		{name: "month out of range", req: VATReportRequest{Year: 2026, Month: 13}, wantErr: true},
+		{name: "quarter and month", req: VATReportRequest{Year: 2026, Quarter: 1, Month: 2}, wantErr: true},
+		{name: "only start date", req: VATReportRequest{StartDate: "2026-05-03"}, wantErr: true},
+		{name: "start after end", req: VATReportRequest{StartDate: "2026-05-17", EndDate: "2026-05-03"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		start, end, err := tt.req.Bounds()
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: Bounds() = %v..%v, want error", tt.name, start, end)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Bounds() returned error: %v", tt.name, err)
+			continue
+		}
+		if got := start.Format(time.DateOnly); got != tt.start {
+			t.Errorf("%s: start = %s, want %s", tt.name, got, tt.start)
+		}
+		if got := end.Format(time.DateOnly); got != tt.end {
+			t.Errorf("%s: end = %s, want %s", tt.name, got, tt.end)
+		}
+	}
+}
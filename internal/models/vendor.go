@@ -0,0 +1,104 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+const maxVendorNameLength = 255
+
+type Vendor struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	Aliases      []string  `json:"aliases"`
+	ExpenseCount int       `json:"expense_count"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+type VendorRequest struct {
+	Name string `json:"name"`
+}
+
+func (r *VendorRequest) Validate() error {
+	r.Name = strings.Join(strings.Fields(r.Name), " ")
+	if r.Name == "" || NormalizeVendorName(r.Name) == "" {
+		return errors.New("vendor name is required")
+	}
+	if len(r.Name) > maxVendorNameLength {
+		return fmt.Errorf("vendor name must be at most %d characters", maxVendorNameLength)
+	}
+	return nil
+}
+
+type VendorAliasRequest struct {
+	Alias string `json:"alias"`
+}
+
+type VendorMergeRequest struct {
+	TargetID int `json:"target_id"`
+}
+
+// VendorMerge reports what moved when one vendor was folded into another.
+// The source vendor's name and aliases become aliases of the target.
+type VendorMerge struct {
+	SourceVendorID int    `json:"source_vendor_id"`
+	SourceName     string `json:"source_name"`
+	TargetVendorID int    `json:"target_vendor_id"`
+	ExpensesMoved  int    `json:"expenses_moved"`
+	AliasesMoved   int    `json:"aliases_moved"`
+}
+
+type VendorSpending struct {
+	VendorID    int    `json:"vendor_id"`
+	VendorName  string `json:"vendor_name"`
+	TotalAmount Money  `json:"total_amount"`
+	Count       int    `json:"count"`
+}
+
+// vendorSuffixes are legal-form words dropped from the end of a vendor name
+// when matching, so "Acme Inc." and "ACME" resolve to the same vendor.
+var vendorSuffixes = map[string]bool{
+	"inc": true, "incorporated": true, "llc": true, "ltd": true, "limited": true,
+	"corp": true, "corporation": true, "co": true, "company": true,
+	"gmbh": true, "ag": true, "plc": true, "sa": true, "bv": true,
+}
+
+// NormalizeVendorName reduces a vendor name to the key it is matched on:
+// lower case, punctuation replaced by spaces, whitespace collapsed and
+// trailing legal-form words removed.
+func NormalizeVendorName(name string) string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for len(words) > 1 && vendorSuffixes[words[len(words)-1]] {
+		words = words[:len(words)-1]
+	}
+	return strings.Join(words, " ")
+}
+
+type PaymentMethod string
+
+const (
+	PaymentCompanyCard  PaymentMethod = "company_card"
+	PaymentCash         PaymentMethod = "cash"
+	PaymentPersonal     PaymentMethod = "personal"
+	PaymentBankTransfer PaymentMethod = "bank_transfer"
+)
+
+// IsReimbursable reports whether the employee paid out of pocket and is owed
+// the amount back.
+func (m PaymentMethod) IsReimbursable() bool {
+	return m == PaymentPersonal
+}
+
+func (m PaymentMethod) Validate() error {
+	switch m {
+	case "", PaymentCompanyCard, PaymentCash, PaymentPersonal, PaymentBankTransfer:
+		return nil
+	}
+	return errors.New("payment method must be company_card, cash, personal or bank_transfer")
+}
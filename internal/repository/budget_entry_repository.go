@@ -1,70 +1,149 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"expense-tracker/internal/models"
 	"fmt"
-	"time"
+
+	"github.com/lib/pq"
 )
 
-type BudgetEntryRepository struct {
-	db *sql.DB
+type sqlBudgetEntryRepository struct {
+	db     *sql.DB
+	fiscal models.FiscalCalendar
 }
 
-func NewBudgetEntryRepository(db *sql.DB) *BudgetEntryRepository {
-	return &BudgetEntryRepository{db: db}
+func NewBudgetEntryRepository(db *sql.DB, fiscal models.FiscalCalendar) BudgetEntryRepository {
+	return &sqlBudgetEntryRepository{db: db, fiscal: fiscal}
 }
 
-func (r *BudgetEntryRepository) Create(budgetID int, amount float64, description string) (*models.BudgetEntry, error) {
-	var year int
-	err := r.db.QueryRow("SELECT year FROM budgets WHERE id = $1", budgetID).Scan(&year)
+func (r *sqlBudgetEntryRepository) AddAllocation(ctx context.Context, budgetID int, delta models.Money, entryType models.BudgetEntryType, reason string, userID int) (*models.BudgetEntry, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("budget with id %d not found", budgetID)
-		}
 		return nil, err
 	}
+	defer tx.Rollback()
 
-	entryDate := time.Date(year, time.January, 1, 0, 0, 0, 0, time.Local)
+	entry, err := addAllocationTx(ctx, tx, budgetID, delta, entryType, reason, userID, nil)
+	if err != nil {
+		return nil, err
+	}
 
-	query := `INSERT INTO budget_entries (budget_id, amount, description, date, updated_at)
-	          VALUES ($1, $2, $3, $4, CURRENT_TIMESTAMP)
-	          RETURNING id, created_at, updated_at`
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
 
-	var entry models.BudgetEntry
-	entry.BudgetID = budgetID
-	entry.Amount = amount
-	entry.Description = description
-	entry.Date = entryDate
+func (r *sqlBudgetEntryRepository) Transfer(ctx context.Context, req models.BudgetTransferRequest, userID int) (*models.BudgetTransfer, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
 
-	err = r.db.QueryRow(query, budgetID, amount, description, entryDate).Scan(
-		&entry.ID, &entry.CreatedAt, &entry.UpdatedAt,
-	)
+	// Lock both budgets in a fixed order so concurrent opposite transfers cannot deadlock.
+	rows, err := tx.QueryContext(ctx, `SELECT b.id, b.category_id, b.amount, c.name FROM budgets b
+	          JOIN categories c ON b.category_id = c.id
+	          WHERE b.year = $1 AND b.category_id IN ($2, $3)
+	          ORDER BY b.id FOR UPDATE OF b`, req.Year, req.FromCategoryID, req.ToCategoryID)
+	if err != nil {
+		return nil, err
+	}
+	type lockedBudget struct {
+		id     int
+		amount models.Money
+		name   string
+	}
+	locked := map[int]lockedBudget{}
+	for rows.Next() {
+		var b lockedBudget
+		var categoryID int
+		if err := rows.Scan(&b.id, &categoryID, &b.amount, &b.name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		locked[categoryID] = b
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
 
+	from, ok := locked[req.FromCategoryID]
+	if !ok {
+		return nil, fmt.Errorf("no budget for category %d in %d", req.FromCategoryID, req.Year)
+	}
+	to, ok := locked[req.ToCategoryID]
+	if !ok {
+		return nil, fmt.Errorf("no budget for category %d in %d", req.ToCategoryID, req.Year)
+	}
+
+	var spent models.Money
+	start, end := r.fiscal.Bounds(req.Year)
+	err = tx.QueryRowContext(ctx, `SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE category_id IN `+categorySubtree("$1")+` AND expense_date BETWEEN $2 AND $3 AND approval_status <> 'rejected'`,
+		req.FromCategoryID, start, end).Scan(&spent)
 	if err != nil {
 		return nil, err
 	}
+	if from.amount-req.Amount < spent {
+		return nil, fmt.Errorf("transfer would reduce the %s budget below its spent amount of %s", from.name, spent)
+	}
+	if from.amount-req.Amount <= models.MinBudgetAmount {
+		return nil, fmt.Errorf("transfer would reduce the %s budget below its minimum of more than %s", from.name, models.MinBudgetAmount)
+	}
 
-	return &entry, nil
+	t := models.BudgetTransfer{
+		FromBudgetID: from.id,
+		ToBudgetID:   to.id,
+		Amount:       req.Amount,
+		Reason:       req.Reason,
+	}
+	if userID > 0 {
+		t.UserID = &userID
+	}
+	err = tx.QueryRowContext(ctx, `INSERT INTO budget_transfers (from_budget_id, to_budget_id, amount, reason, user_id)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`,
+		t.FromBudgetID, t.ToBudgetID, t.Amount, t.Reason, t.UserID).Scan(&t.ID, &t.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := addAllocationTx(ctx, tx, from.id, -req.Amount, models.BudgetEntryTransfer, fmt.Sprintf("Transfer to %s: %s", to.name, req.Reason), userID, &t.ID)
+	if err != nil {
+		return nil, err
+	}
+	in, err := addAllocationTx(ctx, tx, to.id, req.Amount, models.BudgetEntryTransfer, fmt.Sprintf("Transfer from %s: %s", from.name, req.Reason), userID, &t.ID)
+	if err != nil {
+		return nil, err
+	}
+	t.Entries = []models.BudgetEntry{*out, *in}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &t, nil
 }
 
-func (r *BudgetEntryRepository) GetByBudgetID(budgetID int) ([]models.BudgetEntry, error) {
-	query := `SELECT id, budget_id, amount, description, date, created_at, updated_at 
-	          FROM budget_entries 
-	          WHERE budget_id = $1 
-	          ORDER BY created_at DESC`
+func (r *sqlBudgetEntryRepository) GetByBudgetID(ctx context.Context, budgetID int) ([]models.BudgetEntry, error) {
+	query := `SELECT be.id, be.budget_id, be.amount, be.entry_type, COALESCE(be.description, ''), be.user_id, be.transfer_id, be.date, be.created_at, be.updated_at, COALESCE(u.username, 'System')
+	          FROM budget_entries be
+	          LEFT JOIN users u ON be.user_id = u.id
+	          WHERE be.budget_id = $1 
+	          ORDER BY be.created_at DESC, be.id DESC`
 
-	rows, err := r.db.Query(query, budgetID)
+	rows, err := r.db.QueryContext(ctx, query, budgetID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var entries []models.BudgetEntry
+	entries := []models.BudgetEntry{}
 	for rows.Next() {
 		var e models.BudgetEntry
 		err := rows.Scan(
-			&e.ID, &e.BudgetID, &e.Amount, &e.Description, &e.Date, &e.CreatedAt, &e.UpdatedAt,
+			&e.ID, &e.BudgetID, &e.Amount, &e.EntryType, &e.Description, &e.UserID, &e.TransferID, &e.Date, &e.CreatedAt, &e.UpdatedAt, &e.UserName,
 		)
 		if err != nil {
 			return nil, err
@@ -72,5 +151,64 @@ func (r *BudgetEntryRepository) GetByBudgetID(budgetID int) ([]models.BudgetEntr
 		entries = append(entries, e)
 	}
 
-	return entries, nil
+	return entries, rows.Err()
+}
+
+// addAllocationTx moves a budget's effective amount by delta and records the
+// change in the ledger. The caller owns the transaction.
+func addAllocationTx(ctx context.Context, tx *sql.Tx, budgetID int, delta models.Money, entryType models.BudgetEntryType, reason string, userID int, transferID *int) (*models.BudgetEntry, error) {
+	var amount models.Money
+	var phasing []string
+	err := tx.QueryRowContext(ctx, "SELECT amount, phasing FROM budgets WHERE id = $1 FOR UPDATE", budgetID).Scan(&amount, pq.Array(&phasing))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("budget with id %d not found", budgetID)
+		}
+		return nil, err
+	}
+
+	current, err := parsePhasing(phasing)
+	if err != nil {
+		return nil, err
+	}
+
+	// A reduction spread evenly can exceed what is left in a lightly
+	// phased month.
+	spread := models.SpreadOverPhasing(current, delta)
+	if err := models.ValidatePhasing(amount+delta, spread); err != nil {
+		return nil, fmt.Errorf("change of %s would leave budget %d with a negative monthly allocation", delta, budgetID)
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE budgets SET amount = $1, phasing = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3",
+		amount+delta, phasingParam(spread), budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return insertBudgetEntry(ctx, tx, budgetID, delta, entryType, reason, userID, transferID)
+}
+
+func insertBudgetEntry(ctx context.Context, tx *sql.Tx, budgetID int, delta models.Money, entryType models.BudgetEntryType, reason string, userID int, transferID *int) (*models.BudgetEntry, error) {
+	query := `INSERT INTO budget_entries (budget_id, amount, entry_type, description, user_id, transfer_id, date, updated_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+	          RETURNING id, date, created_at, updated_at`
+
+	entry := models.BudgetEntry{
+		BudgetID:    budgetID,
+		Amount:      delta,
+		EntryType:   entryType,
+		Description: reason,
+		TransferID:  transferID,
+	}
+	if userID > 0 {
+		entry.UserID = &userID
+	}
+
+	err := tx.QueryRowContext(ctx, query, budgetID, delta, entryType, reason, entry.UserID, entry.TransferID).Scan(
+		&entry.ID, &entry.Date, &entry.CreatedAt, &entry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
 }
@@ -1,27 +1,33 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"expense-tracker/internal/models"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
 )
 
 type sqlBudgetRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	fiscal models.FiscalCalendar
 }
 
-func NewBudgetRepository(db *sql.DB) BudgetRepository {
-	return &sqlBudgetRepository{db: db}
+func NewBudgetRepository(db *sql.DB, fiscal models.FiscalCalendar) BudgetRepository {
+	return &sqlBudgetRepository{db: db, fiscal: fiscal}
 }
 
-func (r *sqlBudgetRepository) GetAll(year int) ([]models.Budget, error) {
-	query := `SELECT b.id, b.category_id, b.amount, b.year, b.created_at, b.updated_at, c.name as category_name, b.is_locked
+func (r *sqlBudgetRepository) GetAll(ctx context.Context, year int) ([]models.Budget, error) {
+	query := `SELECT b.id, b.category_id, b.amount, b.year, b.period, b.phasing, b.created_at, b.updated_at, c.name as category_name, b.is_locked, b.locked_at
 	          FROM budgets b 
 	          JOIN categories c ON b.category_id = c.id 
 	          WHERE b.year = $1 
 	          ORDER BY c.name ASC`
 
-	rows, err := r.db.Query(query, year)
+	rows, err := r.db.QueryContext(ctx, query, year)
 	if err != nil {
 		return nil, err
 	}
@@ -30,88 +36,275 @@ func (r *sqlBudgetRepository) GetAll(year int) ([]models.Budget, error) {
 	budgets := []models.Budget{}
 	for rows.Next() {
 		var b models.Budget
-		err := rows.Scan(&b.ID, &b.CategoryID, &b.Amount, &b.Year, &b.CreatedAt, &b.UpdatedAt, &b.CategoryName, &b.IsLocked)
+		var phasing []string
+		err := rows.Scan(&b.ID, &b.CategoryID, &b.Amount, &b.Year, &b.Period, pq.Array(&phasing), &b.CreatedAt, &b.UpdatedAt, &b.CategoryName, &b.IsLocked, &b.LockedAt)
 		if err != nil {
 			return nil, err
 		}
+		if b.Phasing, err = parsePhasing(phasing); err != nil {
+			return nil, err
+		}
 		budgets = append(budgets, b)
 	}
 
 	return budgets, nil
 }
 
-func (r *sqlBudgetRepository) CreateOrUpdate(categoryID int, amount float64, year int) (*models.Budget, error) {
+func (r *sqlBudgetRepository) CreateOrUpdate(ctx context.Context, req models.BudgetRequest) (*models.Budget, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	b, err := createOrUpdateTx(ctx, tx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// CreateOrUpdateMany saves several budgets in one transaction; if one fails
+// none are saved.
+func (r *sqlBudgetRepository) CreateOrUpdateMany(ctx context.Context, reqs []models.BudgetRequest) ([]models.Budget, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	budgets := make([]models.Budget, 0, len(reqs))
+	for _, req := range reqs {
+		b, err := createOrUpdateTx(ctx, tx, req)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, *b)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return budgets, nil
+}
+
+func createOrUpdateTx(ctx context.Context, tx *sql.Tx, req models.BudgetRequest) (*models.Budget, error) {
 	var b models.Budget
-	const minBudget = 10000
 
-	if amount <= minBudget {
+	if req.Amount <= models.MinBudgetAmount {
 		return nil, errors.New("budget amount must be greater than 10000")
 	}
-	query := `INSERT INTO budgets (category_id, amount, year, updated_at) 
-	          VALUES ($1, $2, $3, CURRENT_TIMESTAMP) 
+
+	var previous models.Money
+	exists := true
+	err := tx.QueryRowContext(ctx, "SELECT amount FROM budgets WHERE category_id = $1 AND year = $2 FOR UPDATE", req.CategoryID, req.Year).Scan(&previous)
+	if err == sql.ErrNoRows {
+		exists = false
+	} else if err != nil {
+		return nil, err
+	}
+
+	query := `INSERT INTO budgets (category_id, amount, year, period, phasing, updated_at) 
+	          VALUES ($1, $2, $3, $4, $5, CURRENT_TIMESTAMP) 
 	          ON CONFLICT (category_id, year) 
-	          DO UPDATE SET amount = EXCLUDED.amount, updated_at = CURRENT_TIMESTAMP 
-	          RETURNING id, category_id, amount, year, created_at, updated_at`
+	          DO UPDATE SET amount = EXCLUDED.amount, period = EXCLUDED.period, phasing = EXCLUDED.phasing, updated_at = CURRENT_TIMESTAMP 
+	          RETURNING id, category_id, amount, year, period, phasing, created_at, updated_at`
 
-	err := r.db.QueryRow(query, categoryID, amount, year).Scan(
-		&b.ID, &b.CategoryID, &b.Amount, &b.Year, &b.CreatedAt, &b.UpdatedAt,
+	var phasing []string
+	err = tx.QueryRowContext(ctx, query, req.CategoryID, req.Amount, req.Year, req.Period, phasingParam(req.Phasing)).Scan(
+		&b.ID, &b.CategoryID, &b.Amount, &b.Year, &b.Period, pq.Array(&phasing), &b.CreatedAt, &b.UpdatedAt,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if b.Phasing, err = parsePhasing(phasing); err != nil {
+		return nil, err
+	}
+
+	entryType := models.BudgetEntryRevision
+	if !exists {
+		entryType = models.BudgetEntryInitial
+	}
+	if delta := req.Amount - previous; delta != 0 || !exists {
+		if _, err := insertBudgetEntry(ctx, tx, b.ID, delta, entryType, req.Reason, req.ChangedBy, nil); err != nil {
+			return nil, err
+		}
+	}
 	return &b, nil
 }
 
-func (r *sqlBudgetRepository) GetDashboardSummary(year int) (*models.BudgetDashboardSummary, error) {
-	summary := &models.BudgetDashboardSummary{}
+func parsePhasing(values []string) ([]models.Money, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	phasing := make([]models.Money, len(values))
+	for i, v := range values {
+		m, err := models.ParseMoney(v)
+		if err != nil {
+			return nil, err
+		}
+		phasing[i] = m
+	}
+	return phasing, nil
+}
+
+func phasingParam(phasing []models.Money) interface{} {
+	if len(phasing) == 0 {
+		return nil
+	}
+	values := make([]string, len(phasing))
+	for i, m := range phasing {
+		values[i] = m.String()
+	}
+	return pq.Array(values)
+}
+
+func (r *sqlBudgetRepository) GetDashboardSummary(ctx context.Context, year int) (*models.BudgetDashboardSummary, error) {
+	summary := &models.BudgetDashboardSummary{Year: year, OverBudgetCategories: []string{}}
+	start, end := r.fiscal.Bounds(year)
+
+	// A budget on a subcategory is a limit within its parent's budget, so only
+	// the top-most budget on each branch counts towards the total.
+	topLevel := categoryClosureCTE + `SELECT COALESCE(SUM(b.amount), 0), COALESCE(MAX(b.amount), 0)
+	          FROM budgets b
+	          WHERE b.year = $1 AND NOT EXISTS (
+	              SELECT 1 FROM category_closure cc
+	              JOIN budgets pb ON pb.category_id = cc.ancestor_id AND pb.year = b.year
+	              WHERE cc.category_id = b.category_id AND cc.ancestor_id <> b.category_id
+	          )`
+	err := r.db.QueryRowContext(ctx, topLevel, year).Scan(&summary.TotalAnnualBudget, &summary.HighestAllocation)
+	if err != nil {
+		return nil, err
+	}
 
-	err := r.db.QueryRow("SELECT COALESCE(SUM(amount), 0) FROM budgets WHERE year = $1", year).Scan(&summary.TotalAnnualBudget)
+	// Spent counts approved expenses; committed is what still awaits approval.
+	// Only expenses under a budgeted category count, so spent, committed and
+	// remaining add up to the total budget.
+	query := categoryClosureCTE + `SELECT COALESCE(SUM(e.amount) FILTER (WHERE e.approval_status = 'approved'), 0),
+	                 COALESCE(SUM(e.amount) FILTER (WHERE e.approval_status = 'pending'), 0)
+	          FROM expenses e
+	          WHERE e.expense_date BETWEEN $2 AND $3 AND EXISTS (
+	              SELECT 1 FROM category_closure cc
+	              JOIN budgets b ON b.category_id = cc.ancestor_id AND b.year = $1
+	              WHERE cc.category_id = e.category_id
+	          )`
+	err = r.db.QueryRowContext(ctx, query, year, start, end).Scan(&summary.TotalSpent, &summary.Committed)
 	if err != nil {
 		return nil, err
 	}
 
-	err = r.db.QueryRow("SELECT COALESCE(MAX(amount), 0) FROM budgets WHERE year = $1", year).Scan(&summary.HighestAllocation)
+	err = r.db.QueryRowContext(ctx, "SELECT COALESCE((SELECT savings_target FROM budget_year_settings WHERE year = $1), 0)", year).Scan(&summary.SavingsTarget)
 	if err != nil {
 		return nil, err
 	}
 
-	summary.SavingsTarget = summary.TotalAnnualBudget * 0.2
+	query = categoryClosureCTE + `SELECT c.name
+	          FROM budgets b
+	          JOIN categories c ON b.category_id = c.id
+	          JOIN (SELECT cc.ancestor_id AS category_id, SUM(e.amount) AS spent
+	                FROM expenses e
+	                JOIN category_closure cc ON cc.category_id = e.category_id
+	                WHERE e.expense_date BETWEEN $2 AND $3 AND e.approval_status <> 'rejected'
+	                GROUP BY cc.ancestor_id) e
+	            ON e.category_id = b.category_id
+	          WHERE b.year = $1 AND e.spent > b.amount
+	          ORDER BY c.name ASC`
+	rows, err := r.db.QueryContext(ctx, query, year, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, err
+		}
+		summary.OverBudgetCategories = append(summary.OverBudgetCategories, name)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	summary.CategoriesOverBudget = len(summary.OverBudgetCategories)
 
-	summary.RemainingBudget = summary.TotalAnnualBudget * 0.8
+	summary.RemainingBudget = summary.TotalAnnualBudget - summary.TotalSpent - summary.Committed
+	summary.PercentConsumed = (summary.TotalSpent + summary.Committed).PercentOf(summary.TotalAnnualBudget)
 
 	return summary, nil
 }
 
-func (r *sqlBudgetRepository) GetByCategory(categoryID, year int) (*models.Budget, error) {
-	query := `SELECT id, category_id, amount, year, is_locked FROM budgets WHERE category_id = $1 AND year = $2`
+func (r *sqlBudgetRepository) SetSavingsTarget(ctx context.Context, year int, amount models.Money) error {
+	query := `INSERT INTO budget_year_settings (year, savings_target, updated_at) VALUES ($1, $2, CURRENT_TIMESTAMP)
+	          ON CONFLICT (year) DO UPDATE SET savings_target = EXCLUDED.savings_target, updated_at = CURRENT_TIMESTAMP`
+	_, err := r.db.ExecContext(ctx, query, year, amount)
+	return err
+}
+
+func (r *sqlBudgetRepository) GetByID(ctx context.Context, id int) (*models.Budget, error) {
+	query := `SELECT b.id, b.category_id, b.amount, b.year, b.period, b.phasing, b.created_at, b.updated_at, c.name, b.is_locked, b.locked_at,
+	                 b.auto_lock_percent, b.reject_over_budget, b.auto_locked, b.override_until, COALESCE(b.override_reason, ''), b.alert_thresholds
+	          FROM budgets b
+	          JOIN categories c ON b.category_id = c.id
+	          WHERE b.id = $1`
+	var b models.Budget
+	var phasing []string
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&b.ID, &b.CategoryID, &b.Amount, &b.Year, &b.Period, pq.Array(&phasing), &b.CreatedAt, &b.UpdatedAt, &b.CategoryName, &b.IsLocked, &b.LockedAt,
+		&b.AutoLockPercent, &b.RejectOverBudget, &b.AutoLocked, &b.OverrideUntil, &b.OverrideReason, (*pq.Float64Array)(&b.AlertThresholds))
+	if err != nil {
+		return nil, err
+	}
+	if b.Phasing, err = parsePhasing(phasing); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *sqlBudgetRepository) GetByCategory(ctx context.Context, categoryID, year int) (*models.Budget, error) {
+	query := `SELECT b.id, b.category_id, b.amount, b.year, b.period, b.phasing, c.name, b.is_locked, b.locked_at,
+	                 b.auto_lock_percent, b.reject_over_budget, b.auto_locked, b.override_until, COALESCE(b.override_reason, ''), b.alert_thresholds
+	          FROM budgets b
+	          JOIN categories c ON b.category_id = c.id
+	          WHERE b.category_id = $1 AND b.year = $2`
 	var b models.Budget
-	err := r.db.QueryRow(query, categoryID, year).Scan(&b.ID, &b.CategoryID, &b.Amount, &b.Year, &b.IsLocked)
+	var phasing []string
+	err := r.db.QueryRowContext(ctx, query, categoryID, year).Scan(&b.ID, &b.CategoryID, &b.Amount, &b.Year, &b.Period, pq.Array(&phasing), &b.CategoryName, &b.IsLocked, &b.LockedAt,
+		&b.AutoLockPercent, &b.RejectOverBudget, &b.AutoLocked, &b.OverrideUntil, &b.OverrideReason, (*pq.Float64Array)(&b.AlertThresholds))
 	if err != nil {
 		return nil, err
 	}
+	if b.Phasing, err = parsePhasing(phasing); err != nil {
+		return nil, err
+	}
 	return &b, nil
 }
 
-func (r *sqlBudgetRepository) GetMonitoringData(year int) ([]models.BudgetMonitoringItem, error) {
-	query := `
+func (r *sqlBudgetRepository) GetMonitoringData(ctx context.Context, year int) ([]models.BudgetMonitoringItem, error) {
+	query := categoryClosureCTE + `
 		SELECT 
 			b.id, 
 			b.category_id, 
 			c.name, 
 			b.amount as budget_amount, 
 			b.is_locked,
-			COALESCE(SUM(e.amount), 0) as spent_amount
+			b.locked_at,
+			b.period,
+			b.phasing,
+			b.override_until,
+			COALESCE((SELECT SUM(e.amount)
+			          FROM expenses e
+			          JOIN category_closure cc ON cc.category_id = e.category_id
+			          WHERE cc.ancestor_id = b.category_id AND e.expense_date BETWEEN $2 AND $3 AND e.approval_status <> 'rejected'), 0) as spent_amount
 		FROM budgets b
 		JOIN categories c ON b.category_id = c.id
-		LEFT JOIN expenses e ON b.category_id = e.category_id AND EXTRACT(YEAR FROM e.expense_date) = b.year
 		WHERE b.year = $1
-		GROUP BY b.id, b.category_id, c.name, b.amount, b.is_locked
 		ORDER BY c.name ASC
 	`
 
-	rows, err := r.db.Query(query, year)
+	start, end := r.fiscal.Bounds(year)
+	rows, err := r.db.QueryContext(ctx, query, year, start, end)
 	if err != nil {
 		return nil, err
 	}
@@ -120,33 +313,343 @@ func (r *sqlBudgetRepository) GetMonitoringData(year int) ([]models.BudgetMonito
 	items := []models.BudgetMonitoringItem{}
 	for rows.Next() {
 		var item models.BudgetMonitoringItem
-		err := rows.Scan(&item.BudgetID, &item.CategoryID, &item.CategoryName, &item.BudgetAmount, &item.IsLocked, &item.SpentAmount)
+		var phasing []string
+		err := rows.Scan(&item.BudgetID, &item.CategoryID, &item.CategoryName, &item.BudgetAmount, &item.IsLocked, &item.LockedAt, &item.Period, pq.Array(&phasing), &item.OverrideUntil, &item.SpentAmount)
 		if err != nil {
 			return nil, err
 		}
-
-		if item.BudgetAmount > 0 {
-			item.Percentage = (item.SpentAmount / item.BudgetAmount) * 100
+		if item.Phasing, err = parsePhasing(phasing); err != nil {
+			return nil, err
 		}
 		items = append(items, item)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	items, err = r.rollUp(ctx, items, start, end)
+	if err != nil {
+		return nil, err
+	}
+	for i := range items {
+		if items[i].BudgetAmount > 0 {
+			items[i].Percentage = items[i].SpentAmount.PercentOf(items[i].BudgetAmount)
+		}
+	}
 
 	return items, nil
 }
 
-func (r *sqlBudgetRepository) ToggleLock(budgetID int, isLocked bool) error {
-	_, err := r.db.Exec("UPDATE budgets SET is_locked = $1 WHERE id = $2", isLocked, budgetID)
+// rollUp completes the budget tree: every ancestor of a budgeted category gets
+// a row, parents without a budget of their own take the sum of their
+// subcategories' budgets, and items come back in tree order.
+func (r *sqlBudgetRepository) rollUp(ctx context.Context, items []models.BudgetMonitoringItem, start, end time.Time) ([]models.BudgetMonitoringItem, error) {
+	tree, err := loadCategoryTree(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	byCategory := make(map[int]*models.BudgetMonitoringItem, len(items))
+	for i := range items {
+		byCategory[items[i].CategoryID] = &items[i]
+	}
+	var missing []int
+	for _, item := range items {
+		for _, id := range tree.Ancestors(item.CategoryID) {
+			if _, ok := byCategory[id]; ok {
+				continue
+			}
+			category, _ := tree.Get(id)
+			byCategory[id] = &models.BudgetMonitoringItem{
+				CategoryID:   id,
+				CategoryName: category.Name,
+				Period:       models.BudgetPeriodAnnual,
+				RollUp:       true,
+			}
+			missing = append(missing, id)
+		}
+	}
+
+	if len(missing) > 0 {
+		query := categoryClosureCTE + `SELECT cc.ancestor_id, COALESCE(SUM(e.amount), 0)
+		          FROM expenses e
+		          JOIN category_closure cc ON cc.category_id = e.category_id
+		          WHERE cc.ancestor_id = ANY($1) AND e.expense_date BETWEEN $2 AND $3 AND e.approval_status <> 'rejected'
+		          GROUP BY cc.ancestor_id`
+		rows, err := r.db.QueryContext(ctx, query, pq.Array(missing), start, end)
+		if err != nil {
+			return nil, err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var id int
+			var spent models.Money
+			if err := rows.Scan(&id, &spent); err != nil {
+				return nil, err
+			}
+			byCategory[id].SpentAmount = spent
+		}
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	order := tree.Ordered()
+	// Walk bottom-up so subcategory totals are final before their parent sums them.
+	for i := len(order) - 1; i >= 0; i-- {
+		item, ok := byCategory[order[i]]
+		if !ok {
+			continue
+		}
+		category, _ := tree.Get(item.CategoryID)
+		item.ParentCategoryID = category.ParentID
+		item.Depth = tree.Depth(item.CategoryID)
+
+		var phasing []models.Money
+		if item.RollUp {
+			phasing = make([]models.Money, 12)
+		}
+		for _, childID := range tree.Children(item.CategoryID) {
+			child, ok := byCategory[childID]
+			if !ok {
+				continue
+			}
+			item.SubcategoryBudget += child.BudgetAmount
+			if item.RollUp {
+				b := models.Budget{Amount: child.BudgetAmount, Phasing: child.Phasing}
+				for m, amount := range b.MonthlyAllocations() {
+					phasing[m] += amount
+				}
+			}
+		}
+		if item.RollUp {
+			item.BudgetAmount = item.SubcategoryBudget
+			item.Phasing = phasing
+		}
+	}
+
+	ordered := make([]models.BudgetMonitoringItem, 0, len(byCategory))
+	for _, id := range order {
+		if item, ok := byCategory[id]; ok {
+			ordered = append(ordered, *item)
+		}
+	}
+	return ordered, nil
+}
+
+func (r *sqlBudgetRepository) ToggleLock(ctx context.Context, budgetID int, isLocked bool, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// A manual lock ends any override; a manual unlock keeps it so management can lift an automatic lock.
+	query := `UPDATE budgets SET is_locked = $1, auto_locked = FALSE,
+	                 locked_at = CASE WHEN $1 THEN CURRENT_DATE ELSE NULL END,
+	                 override_until = CASE WHEN $1 THEN NULL ELSE override_until END
+	          WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, isLocked, budgetID); err != nil {
+		return err
+	}
+
+	action := models.BudgetLockActionUnlock
+	if isLocked {
+		action = models.BudgetLockActionLock
+	}
+	if err := insertLockEvent(ctx, tx, budgetID, action, false, "Manual circuit breaker change", userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *sqlBudgetRepository) SetAutoLock(ctx context.Context, budgetID int, isLocked bool, lockedAt time.Time, reason string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	query := `UPDATE budgets SET is_locked = $1, auto_locked = $1, locked_at = CASE WHEN $1 THEN $2::date ELSE NULL END
+	          WHERE id = $3 AND (is_locked <> $1 OR ($1 AND locked_at IS DISTINCT FROM $2::date))`
+	res, err := tx.ExecContext(ctx, query, isLocked, lockedAt, budgetID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil
+	}
+
+	action := models.BudgetLockActionUnlock
+	if isLocked {
+		action = models.BudgetLockActionLock
+	}
+	if err := insertLockEvent(ctx, tx, budgetID, action, true, reason, 0); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *sqlBudgetRepository) UpdatePolicy(ctx context.Context, budgetID int, policy models.BudgetPolicy) error {
+	query := `UPDATE budgets SET auto_lock_percent = $1, reject_over_budget = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3`
+	res, err := r.db.ExecContext(ctx, query, policy.AutoLockPercent, policy.RejectOverBudget, budgetID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *sqlBudgetRepository) SetOverride(ctx context.Context, budgetID int, until *time.Time, reason string, userID int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE budgets SET override_until = $1, override_reason = $2 WHERE id = $3`, until, reason, budgetID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+
+	if until != nil {
+		reason = fmt.Sprintf("Override until %s: %s", until.Format("2006-01-02"), reason)
+	} else {
+		reason = "Override cleared"
+	}
+	if err := insertLockEvent(ctx, tx, budgetID, models.BudgetLockActionOverride, false, reason, userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *sqlBudgetRepository) GetLockEvents(ctx context.Context, budgetID int) ([]models.BudgetLockEvent, error) {
+	query := `SELECT le.id, le.budget_id, le.action, le.automatic, COALESCE(le.reason, ''), le.user_id, COALESCE(u.username, 'System'), le.created_at
+	          FROM budget_lock_events le
+	          LEFT JOIN users u ON le.user_id = u.id
+	          WHERE le.budget_id = $1
+	          ORDER BY le.created_at DESC, le.id DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.BudgetLockEvent{}
+	for rows.Next() {
+		var e models.BudgetLockEvent
+		if err := rows.Scan(&e.ID, &e.BudgetID, &e.Action, &e.Automatic, &e.Reason, &e.UserID, &e.UserName, &e.CreatedAt); err != nil {
+			return nil, err
+		}
+		events = append(events, e)
+	}
+	return events, rows.Err()
+}
+
+func (r *sqlBudgetRepository) UpdateAlertThresholds(ctx context.Context, budgetID int, thresholds []float64) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE budgets SET alert_thresholds = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`, pq.Float64Array(thresholds), budgetID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// RecordAlert marks threshold as crossed for the period starting at
+// periodStart and stores the alert's notifications in the same transaction,
+// so concurrent expenses cannot deliver an alert twice. It reports false,
+// storing nothing, when the alert was already recorded.
+func (r *sqlBudgetRepository) RecordAlert(ctx context.Context, budgetID int, threshold float64, periodStart time.Time, percent float64, notifications []models.Notification) (bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO budget_alerts (budget_id, threshold, period_start, percent)
+	          VALUES ($1, $2, $3, $4)
+	          ON CONFLICT (budget_id, threshold, period_start) DO NOTHING`
+	res, err := tx.ExecContext(ctx, query, budgetID, threshold, periodStart, percent)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	for i := range notifications {
+		if err := insertNotification(ctx, tx, &notifications[i]); err != nil {
+			return false, err
+		}
+	}
+	return true, tx.Commit()
+}
+
+func insertLockEvent(ctx context.Context, tx *sql.Tx, budgetID int, action models.BudgetLockAction, automatic bool, reason string, userID int) error {
+	var user *int
+	if userID > 0 {
+		user = &userID
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO budget_lock_events (budget_id, action, automatic, reason, user_id) VALUES ($1, $2, $3, $4, $5)`,
+		budgetID, action, automatic, reason, user)
 	return err
 }
 
-func (r *sqlBudgetRepository) IsLocked(categoryID, year int) (bool, error) {
-	var isLocked bool
-	err := r.db.QueryRow("SELECT is_locked FROM budgets WHERE category_id = $1 AND year = $2", categoryID, year).Scan(&isLocked)
+// GetByCategoryPath returns the year's budgets on categoryID and on each of
+// its ancestors, nearest first. Spend on a category counts against all of them.
+func (r *sqlBudgetRepository) GetByCategoryPath(ctx context.Context, categoryID, year int) ([]models.Budget, error) {
+	query := `WITH RECURSIVE ancestors AS (
+	              SELECT id, parent_id, 0 AS depth FROM categories WHERE id = $1
+	              UNION ALL
+	              SELECT c.id, c.parent_id, a.depth + 1 FROM categories c JOIN ancestors a ON c.id = a.parent_id
+	          )
+	          SELECT b.id, b.category_id, b.amount, b.year, b.period, b.phasing, c.name, b.is_locked, b.locked_at,
+	                 b.auto_lock_percent, b.reject_over_budget, b.auto_locked, b.override_until, COALESCE(b.override_reason, ''), b.alert_thresholds
+	          FROM ancestors a
+	          JOIN budgets b ON b.category_id = a.id AND b.year = $2
+	          JOIN categories c ON b.category_id = c.id
+	          ORDER BY a.depth ASC`
+	rows, err := r.db.QueryContext(ctx, query, categoryID, year)
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return false, nil
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []models.Budget{}
+	for rows.Next() {
+		var b models.Budget
+		var phasing []string
+		err := rows.Scan(&b.ID, &b.CategoryID, &b.Amount, &b.Year, &b.Period, pq.Array(&phasing), &b.CategoryName, &b.IsLocked, &b.LockedAt,
+			&b.AutoLockPercent, &b.RejectOverBudget, &b.AutoLocked, &b.OverrideUntil, &b.OverrideReason, (*pq.Float64Array)(&b.AlertThresholds))
+		if err != nil {
+			return nil, err
 		}
+		if b.Phasing, err = parsePhasing(phasing); err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, b)
+	}
+	return budgets, rows.Err()
+}
+
+// IsLocked reports whether the circuit breaker of the category's budget, or of
+// any budget above it, applies on date.
+func (r *sqlBudgetRepository) IsLocked(ctx context.Context, categoryID int, date time.Time) (bool, error) {
+	budgets, err := r.GetByCategoryPath(ctx, categoryID, r.fiscal.YearOf(date))
+	if err != nil {
 		return false, err
 	}
-	return isLocked, nil
+	for _, b := range budgets {
+		if b.LockApplies(r.fiscal, date) {
+			return true, nil
+		}
+	}
+	return false, nil
 }
@@ -0,0 +1,160 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"expense-tracker/internal/models"
+)
+
+type sqlCategorizationRuleRepository struct {
+	db *sql.DB
+}
+
+func NewCategorizationRuleRepository(db *sql.DB) CategorizationRuleRepository {
+	return &sqlCategorizationRuleRepository{db: db}
+}
+
+const ruleColumns = `r.id, r.name, r.category_id, r.priority, r.match_type, r.remarks_pattern, r.min_amount, r.max_amount,
+	r.user_id, r.auto_assign, r.is_active, r.created_at, r.updated_at, c.name`
+
+func scanRule(row interface{ Scan(...interface{}) error }) (*models.CategorizationRule, error) {
+	var rule models.CategorizationRule
+	err := row.Scan(&rule.ID, &rule.Name, &rule.CategoryID, &rule.Priority, &rule.MatchType, &rule.RemarksPattern, &rule.MinAmount, &rule.MaxAmount,
+		&rule.UserID, &rule.AutoAssign, &rule.IsActive, &rule.CreatedAt, &rule.UpdatedAt, &rule.CategoryName)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// GetAll returns rules in evaluation order: lowest priority first, then oldest.
+func (r *sqlCategorizationRuleRepository) GetAll(ctx context.Context, activeOnly bool) ([]models.CategorizationRule, error) {
+	query := `SELECT ` + ruleColumns + ` FROM categorization_rules r JOIN categories c ON r.category_id = c.id`
+	if activeOnly {
+		query += " WHERE r.is_active AND c.is_active"
+	}
+	query += " ORDER BY r.priority ASC, r.id ASC"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []models.CategorizationRule{}
+	for rows.Next() {
+		rule, err := scanRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *sqlCategorizationRuleRepository) GetByID(ctx context.Context, id int) (*models.CategorizationRule, error) {
+	query := `SELECT ` + ruleColumns + ` FROM categorization_rules r JOIN categories c ON r.category_id = c.id WHERE r.id = $1`
+	rule, err := scanRule(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("categorization rule not found")
+	}
+	return rule, err
+}
+
+func (r *sqlCategorizationRuleRepository) Create(ctx context.Context, req models.CategorizationRuleRequest) (*models.CategorizationRule, error) {
+	isActive := req.IsActive == nil || *req.IsActive
+	var id int
+	query := `INSERT INTO categorization_rules (name, category_id, priority, match_type, remarks_pattern, min_amount, max_amount, user_id, auto_assign, is_active)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id`
+	err := r.db.QueryRowContext(ctx, query, req.Name, req.CategoryID, req.Priority, req.MatchType, req.RemarksPattern, req.MinAmount, req.MaxAmount,
+		req.UserID, req.AutoAssign, isActive).Scan(&id)
+	if err != nil {
+		return nil, ruleWriteError(err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *sqlCategorizationRuleRepository) Update(ctx context.Context, id int, req models.CategorizationRuleRequest) (*models.CategorizationRule, error) {
+	isActive := req.IsActive == nil || *req.IsActive
+	query := `UPDATE categorization_rules SET name = $1, category_id = $2, priority = $3, match_type = $4, remarks_pattern = $5,
+	                 min_amount = $6, max_amount = $7, user_id = $8, auto_assign = $9, is_active = $10, updated_at = CURRENT_TIMESTAMP
+	          WHERE id = $11`
+	res, err := r.db.ExecContext(ctx, query, req.Name, req.CategoryID, req.Priority, req.MatchType, req.RemarksPattern, req.MinAmount, req.MaxAmount,
+		req.UserID, req.AutoAssign, isActive, id)
+	if err != nil {
+		return nil, ruleWriteError(err)
+	}
+	if rowsAffected(res) == 0 {
+		return nil, errors.New("categorization rule not found")
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *sqlCategorizationRuleRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM categorization_rules WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return errors.New("categorization rule not found")
+	}
+	return nil
+}
+
+// GetOverrideStats counts, for expenses dated between start and end
+// (YYYY-MM-DD, either may be empty), how often each rule's suggestion was kept.
+func (r *sqlCategorizationRuleRepository) GetOverrideStats(ctx context.Context, start, end string) ([]models.RuleOverrideStats, error) {
+	query := `SELECT r.id, r.name, r.category_id, c.name,
+	                 COUNT(e.id),
+	                 COUNT(e.id) FILTER (WHERE e.category_id = e.suggested_category_id)
+	          FROM categorization_rules r
+	          JOIN categories c ON r.category_id = c.id
+	          LEFT JOIN expenses e ON e.suggestion_rule_id = r.id`
+	var args []interface{}
+	argCount := 1
+	if start != "" {
+		query += fmt.Sprintf(" AND e.expense_date >= $%d", argCount)
+		args = append(args, start)
+		argCount++
+	}
+	if end != "" {
+		query += fmt.Sprintf(" AND e.expense_date <= $%d", argCount)
+		args = append(args, end)
+		argCount++
+	}
+	query += " GROUP BY r.id, r.name, r.category_id, c.name ORDER BY COUNT(e.id) - COUNT(e.id) FILTER (WHERE e.category_id = e.suggested_category_id) DESC, r.priority ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	stats := []models.RuleOverrideStats{}
+	for rows.Next() {
+		var s models.RuleOverrideStats
+		if err := rows.Scan(&s.RuleID, &s.RuleName, &s.CategoryID, &s.CategoryName, &s.Suggested, &s.Accepted); err != nil {
+			return nil, err
+		}
+		s.Overridden = s.Suggested - s.Accepted
+		if s.Suggested > 0 {
+			s.OverrideRate = float64(s.Overridden) * 100 / float64(s.Suggested)
+		}
+		stats = append(stats, s)
+	}
+	return stats, rows.Err()
+}
+
+func ruleWriteError(err error) error {
+	if strings.Contains(err.Error(), "categorization_rules_category_id_fkey") {
+		return errors.New("category not found")
+	}
+	if strings.Contains(err.Error(), "categorization_rules_user_id_fkey") {
+		return errors.New("user not found")
+	}
+	return err
+}
@@ -1,11 +1,15 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
 	"strings"
 
 	"expense-tracker/internal/models"
+
+	"github.com/lib/pq"
 )
 
 type sqlCategoryRepository struct {
@@ -16,15 +20,15 @@ func NewCategoryRepository(db *sql.DB) CategoryRepository {
 	return &sqlCategoryRepository{db: db}
 }
 
-func (r *sqlCategoryRepository) GetAll(activeOnly bool) ([]models.Category, error) {
+func (r *sqlCategoryRepository) GetAll(ctx context.Context, activeOnly bool) ([]models.Category, error) {
 	var query string
 	if activeOnly {
-		query = "SELECT id, name, is_active, created_at, updated_at FROM categories WHERE is_active = true ORDER BY name ASC"
+		query = "SELECT id, name, is_active, parent_id, created_at, updated_at FROM categories WHERE is_active = true ORDER BY name ASC"
 	} else {
-		query = "SELECT id, name, is_active, created_at, updated_at FROM categories ORDER BY name ASC"
+		query = "SELECT id, name, is_active, parent_id, created_at, updated_at FROM categories ORDER BY name ASC"
 	}
 
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -33,7 +37,7 @@ func (r *sqlCategoryRepository) GetAll(activeOnly bool) ([]models.Category, erro
 	categories := []models.Category{}
 	for rows.Next() {
 		var category models.Category
-		if err := rows.Scan(&category.ID, &category.Name, &category.IsActive, &category.CreatedAt, &category.UpdatedAt); err != nil {
+		if err := rows.Scan(&category.ID, &category.Name, &category.IsActive, &category.ParentID, &category.CreatedAt, &category.UpdatedAt); err != nil {
 			return nil, err
 		}
 		categories = append(categories, category)
@@ -42,14 +46,15 @@ func (r *sqlCategoryRepository) GetAll(activeOnly bool) ([]models.Category, erro
 	return categories, rows.Err()
 }
 
-func (r *sqlCategoryRepository) GetByID(id int) (*models.Category, error) {
+func (r *sqlCategoryRepository) GetByID(ctx context.Context, id int) (*models.Category, error) {
 	var category models.Category
-	query := "SELECT id, name, is_active, created_at, updated_at FROM categories WHERE id = $1"
+	query := "SELECT id, name, is_active, parent_id, created_at, updated_at FROM categories WHERE id = $1"
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&category.ID,
 		&category.Name,
 		&category.IsActive,
+		&category.ParentID,
 		&category.CreatedAt,
 		&category.UpdatedAt,
 	)
@@ -63,28 +68,32 @@ func (r *sqlCategoryRepository) GetByID(id int) (*models.Category, error) {
 	return &category, nil
 }
 
-func (r *sqlCategoryRepository) Create(name string, isActive bool) (*models.Category, error) {
+func (r *sqlCategoryRepository) Create(ctx context.Context, name string, isActive bool, parentID *int) (*models.Category, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil, errors.New("category name is required")
 	}
 
-	exists, err := r.ExistsByName(name)
+	exists, err := r.ExistsByName(ctx, name)
 	if err != nil {
 		return nil, err
 	}
 	if exists {
 		return nil, errors.New("category with this name already exists")
 	}
+	if err := r.checkParent(ctx, 0, parentID); err != nil {
+		return nil, err
+	}
 
 	var category models.Category
-	query := `INSERT INTO categories (name, is_active) VALUES ($1, $2) 
-	          RETURNING id, name, is_active, created_at, updated_at`
+	query := `INSERT INTO categories (name, is_active, parent_id) VALUES ($1, $2, $3) 
+	          RETURNING id, name, is_active, parent_id, created_at, updated_at`
 
-	err = r.db.QueryRow(query, name, isActive).Scan(
+	err = r.db.QueryRowContext(ctx, query, name, isActive, parentID).Scan(
 		&category.ID,
 		&category.Name,
 		&category.IsActive,
+		&category.ParentID,
 		&category.CreatedAt,
 		&category.UpdatedAt,
 	)
@@ -96,36 +105,40 @@ func (r *sqlCategoryRepository) Create(name string, isActive bool) (*models.Cate
 	return &category, nil
 }
 
-func (r *sqlCategoryRepository) ExistsByName(name string) (bool, error) {
+func (r *sqlCategoryRepository) ExistsByName(ctx context.Context, name string) (bool, error) {
 	var exists bool
 	query := "SELECT EXISTS(SELECT 1 FROM categories WHERE LOWER(name) = LOWER($1))"
-	err := r.db.QueryRow(query, name).Scan(&exists)
+	err := r.db.QueryRowContext(ctx, query, name).Scan(&exists)
 	return exists, err
 }
 
-func (r *sqlCategoryRepository) Update(id int, name string, isActive bool) (*models.Category, error) {
+func (r *sqlCategoryRepository) Update(ctx context.Context, id int, name string, isActive bool, parentID *int) (*models.Category, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil, errors.New("category name is required")
 	}
 
 	var duplicateExists bool
-	err := r.db.QueryRow("SELECT EXISTS(SELECT 1 FROM categories WHERE LOWER(name) = LOWER($1) AND id != $2)", name, id).Scan(&duplicateExists)
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM categories WHERE LOWER(name) = LOWER($1) AND id != $2)", name, id).Scan(&duplicateExists)
 	if err != nil {
 		return nil, err
 	}
 	if duplicateExists {
 		return nil, errors.New("another category with this name already exists")
 	}
+	if err := r.checkParent(ctx, id, parentID); err != nil {
+		return nil, err
+	}
 
 	var category models.Category
-	query := `UPDATE categories SET name = $1, is_active = $2, updated_at = CURRENT_TIMESTAMP 
-	          WHERE id = $3 RETURNING id, name, is_active, created_at, updated_at`
+	query := `UPDATE categories SET name = $1, is_active = $2, parent_id = $3, updated_at = CURRENT_TIMESTAMP 
+	          WHERE id = $4 RETURNING id, name, is_active, parent_id, created_at, updated_at`
 
-	err = r.db.QueryRow(query, name, isActive, id).Scan(
+	err = r.db.QueryRowContext(ctx, query, name, isActive, parentID, id).Scan(
 		&category.ID,
 		&category.Name,
 		&category.IsActive,
+		&category.ParentID,
 		&category.CreatedAt,
 		&category.UpdatedAt,
 	)
@@ -139,15 +152,45 @@ func (r *sqlCategoryRepository) Update(id int, name string, isActive bool) (*mod
 	return &category, nil
 }
 
-func (r *sqlCategoryRepository) ToggleStatus(id int) (*models.Category, error) {
+// checkParent rejects a parent that does not exist or that would make id its
+// own ancestor. id is 0 for a new category.
+func (r *sqlCategoryRepository) checkParent(ctx context.Context, id int, parentID *int) error {
+	if parentID == nil {
+		return nil
+	}
+	if *parentID == id {
+		return errors.New("a category cannot be its own parent")
+	}
+
+	var exists, cycle bool
+	query := `WITH RECURSIVE ancestors AS (
+	              SELECT id, parent_id FROM categories WHERE id = $1
+	              UNION ALL
+	              SELECT c.id, c.parent_id FROM categories c JOIN ancestors a ON c.id = a.parent_id
+	          )
+	          SELECT EXISTS(SELECT 1 FROM ancestors), EXISTS(SELECT 1 FROM ancestors WHERE id = $2)`
+	if err := r.db.QueryRowContext(ctx, query, *parentID, id).Scan(&exists, &cycle); err != nil {
+		return err
+	}
+	if !exists {
+		return errors.New("parent category not found")
+	}
+	if cycle {
+		return errors.New("a category cannot be moved under one of its own subcategories")
+	}
+	return nil
+}
+
+func (r *sqlCategoryRepository) ToggleStatus(ctx context.Context, id int) (*models.Category, error) {
 	var category models.Category
 	query := `UPDATE categories SET is_active = NOT is_active, updated_at = CURRENT_TIMESTAMP 
-	          WHERE id = $1 RETURNING id, name, is_active, created_at, updated_at`
+	          WHERE id = $1 RETURNING id, name, is_active, parent_id, created_at, updated_at`
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&category.ID,
 		&category.Name,
 		&category.IsActive,
+		&category.ParentID,
 		&category.CreatedAt,
 		&category.UpdatedAt,
 	)
@@ -161,7 +204,221 @@ func (r *sqlCategoryRepository) ToggleStatus(id int) (*models.Category, error) {
 	return &category, nil
 }
 
-func (r *sqlCategoryRepository) InitializeDefaults() error {
+// Merge moves every expense, budget, subcategory and rule of sourceID to targetID
+// and deletes sourceID. A budget both categories hold for the same year is
+// combined into the target's, carrying its ledger and lock history along.
+func (r *sqlCategoryRepository) Merge(ctx context.Context, sourceID, targetID, userID int) (*models.CategoryMerge, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, name FROM categories WHERE id IN ($1, $2) ORDER BY id FOR UPDATE", sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	names := map[int]string{}
+	for rows.Next() {
+		var id int
+		var name string
+		if err := rows.Scan(&id, &name); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names[id] = name
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if _, ok := names[sourceID]; !ok {
+		return nil, errors.New("category not found")
+	}
+	if _, ok := names[targetID]; !ok {
+		return nil, errors.New("target category not found")
+	}
+
+	var intoDescendant bool
+	err = tx.QueryRowContext(ctx, "SELECT $2 IN "+categorySubtree("$1"), sourceID, targetID).Scan(&intoDescendant)
+	if err != nil {
+		return nil, err
+	}
+	if intoDescendant {
+		return nil, errors.New("a category cannot be merged into one of its own subcategories")
+	}
+
+	merge := models.CategoryMerge{SourceCategoryID: sourceID, SourceName: names[sourceID], TargetCategoryID: targetID}
+
+	res, err := tx.ExecContext(ctx, "UPDATE categories SET parent_id = $1, updated_at = CURRENT_TIMESTAMP WHERE parent_id = $2", targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	merge.SubcategoriesMoved = rowsAffected(res)
+
+	res, err = tx.ExecContext(ctx, "UPDATE expenses SET category_id = $1, updated_at = CURRENT_TIMESTAMP WHERE category_id = $2", targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	merge.ExpensesMoved = rowsAffected(res)
+
+	if _, err := tx.ExecContext(ctx, "UPDATE expenses SET suggested_category_id = $1 WHERE suggested_category_id = $2", targetID, sourceID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE categorization_rules SET category_id = $1, updated_at = CURRENT_TIMESTAMP WHERE category_id = $2", targetID, sourceID); err != nil {
+		return nil, err
+	}
+
+	combined, err := combineBudgets(ctx, tx, sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	merge.BudgetsCombined = combined
+
+	res, err = tx.ExecContext(ctx, "UPDATE budgets SET category_id = $1, updated_at = CURRENT_TIMESTAMP WHERE category_id = $2", targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	merge.BudgetsMoved = rowsAffected(res)
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM categories WHERE id = $1", sourceID); err != nil {
+		return nil, err
+	}
+
+	if userID > 0 {
+		merge.UserID = &userID
+	}
+	err = tx.QueryRowContext(ctx, `INSERT INTO category_merges (source_category_id, source_name, target_category_id, expenses_moved, budgets_moved, budgets_combined, subcategories_moved, user_id)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at`,
+		merge.SourceCategoryID, merge.SourceName, merge.TargetCategoryID, merge.ExpensesMoved, merge.BudgetsMoved, merge.BudgetsCombined, merge.SubcategoriesMoved, merge.UserID).Scan(&merge.ID, &merge.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &merge, nil
+}
+
+// combineBudgets folds each of sourceID's budgets into targetID's budget for
+// the same year, summing amounts and phasing, and deletes the source budget.
+func combineBudgets(ctx context.Context, tx *sql.Tx, sourceID, targetID int) (int, error) {
+	rows, err := tx.QueryContext(ctx, `SELECT s.id, s.amount, s.phasing, t.id, t.amount, t.phasing
+	          FROM budgets s
+	          JOIN budgets t ON t.year = s.year AND t.category_id = $2
+	          WHERE s.category_id = $1
+	          ORDER BY s.year
+	          FOR UPDATE OF s, t`, sourceID, targetID)
+	if err != nil {
+		return 0, err
+	}
+	type pair struct {
+		sourceID, targetID int
+		amount             models.Money
+		phasing            []models.Money
+	}
+	var pairs []pair
+	for rows.Next() {
+		var p pair
+		var source, target models.Budget
+		var sourcePhasing, targetPhasing []string
+		if err := rows.Scan(&p.sourceID, &source.Amount, pq.Array(&sourcePhasing), &p.targetID, &target.Amount, pq.Array(&targetPhasing)); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if source.Phasing, err = parsePhasing(sourcePhasing); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		if target.Phasing, err = parsePhasing(targetPhasing); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		p.amount = source.Amount + target.Amount
+		if source.Phasing != nil || target.Phasing != nil {
+			p.phasing = target.MonthlyAllocations()
+			for m, amount := range source.MonthlyAllocations() {
+				p.phasing[m] += amount
+			}
+		}
+		pairs = append(pairs, p)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, p := range pairs {
+		_, err := tx.ExecContext(ctx, "UPDATE budgets SET amount = $1, phasing = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3",
+			p.amount, phasingParam(p.phasing), p.targetID)
+		if err != nil {
+			return 0, err
+		}
+
+		statements := []string{
+			// Transfers between the two budgets become internal to the merged one.
+			"DELETE FROM budget_transfers WHERE (from_budget_id = $1 AND to_budget_id = $2) OR (from_budget_id = $2 AND to_budget_id = $1)",
+			"UPDATE budget_transfers SET from_budget_id = $2 WHERE from_budget_id = $1",
+			"UPDATE budget_transfers SET to_budget_id = $2 WHERE to_budget_id = $1",
+			"UPDATE budget_entries SET budget_id = $2, updated_at = CURRENT_TIMESTAMP WHERE budget_id = $1",
+			"UPDATE budget_lock_events SET budget_id = $2 WHERE budget_id = $1",
+			`DELETE FROM budget_alerts s WHERE s.budget_id = $1 AND EXISTS (
+			     SELECT 1 FROM budget_alerts t WHERE t.budget_id = $2 AND t.threshold = s.threshold AND t.period_start = s.period_start)`,
+			"UPDATE budget_alerts SET budget_id = $2 WHERE budget_id = $1",
+			"UPDATE notifications SET budget_id = $2 WHERE budget_id = $1",
+			"DELETE FROM budgets WHERE id = $1",
+		}
+		for _, stmt := range statements {
+			if _, err := tx.ExecContext(ctx, stmt, p.sourceID, p.targetID); err != nil {
+				return 0, fmt.Errorf("combining budget %d into %d: %w", p.sourceID, p.targetID, err)
+			}
+		}
+	}
+	return len(pairs), nil
+}
+
+// Delete removes a category that no expense, budget, subcategory or
+// categorization rule refers to.
+func (r *sqlCategoryRepository) Delete(ctx context.Context, id int) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var locked int
+	err = tx.QueryRowContext(ctx, "SELECT id FROM categories WHERE id = $1 FOR UPDATE", id).Scan(&locked)
+	if err == sql.ErrNoRows {
+		return errors.New("category not found")
+	} else if err != nil {
+		return err
+	}
+
+	var expenses, budgets, children, rules int
+	query := `SELECT (SELECT COUNT(*) FROM expenses WHERE category_id = $1),
+	                 (SELECT COUNT(*) FROM budgets WHERE category_id = $1),
+	                 (SELECT COUNT(*) FROM categories WHERE parent_id = $1),
+	                 (SELECT COUNT(*) FROM categorization_rules WHERE category_id = $1)`
+	if err := tx.QueryRowContext(ctx, query, id).Scan(&expenses, &budgets, &children, &rules); err != nil {
+		return err
+	}
+	if expenses > 0 || budgets > 0 || children > 0 || rules > 0 {
+		return fmt.Errorf("category is in use by %d expenses, %d budgets, %d subcategories and %d categorization rules; merge it into another category instead", expenses, budgets, children, rules)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM categories WHERE id = $1", id); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func rowsAffected(res sql.Result) int {
+	n, _ := res.RowsAffected()
+	return int(n)
+}
+
+func (r *sqlCategoryRepository) InitializeDefaults(ctx context.Context) error {
 	defaultCategories := []string{
 		"Food",
 		"Transport",
@@ -175,13 +432,13 @@ func (r *sqlCategoryRepository) InitializeDefaults() error {
 	}
 
 	for _, name := range defaultCategories {
-		exists, err := r.ExistsByName(name)
+		exists, err := r.ExistsByName(ctx, name)
 		if err != nil {
 			return err
 		}
 
 		if !exists {
-			_, err := r.Create(name, true)
+			_, err := r.Create(ctx, name, true, nil)
 			if err != nil {
 				return err
 			}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"expense-tracker/internal/models"
+)
+
+// categoryClosureCTE pairs every category with itself and each of its
+// ancestors. Joining expenses on category_id and grouping by ancestor_id
+// rolls spend up the category tree.
+const categoryClosureCTE = `WITH RECURSIVE category_closure (ancestor_id, category_id) AS (
+	SELECT id, id FROM categories
+	UNION ALL
+	SELECT c.parent_id, cc.category_id
+	FROM category_closure cc
+	JOIN categories c ON c.id = cc.ancestor_id
+	WHERE c.parent_id IS NOT NULL
+)
+`
+
+// categorySubtree returns a subquery selecting the category bound to
+// placeholder and all of its descendants, for "category_id IN ..." filters.
+func categorySubtree(placeholder string) string {
+	return `(WITH RECURSIVE subtree AS (
+	SELECT id FROM categories WHERE id = ` + placeholder + `
+	UNION ALL
+	SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+) SELECT id FROM subtree)`
+}
+
+func loadCategoryTree(ctx context.Context, db *sql.DB) (*models.CategoryTree, error) {
+	rows, err := db.QueryContext(ctx, "SELECT id, name, is_active, parent_id FROM categories ORDER BY name ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var categories []models.Category
+	for rows.Next() {
+		var c models.Category
+		if err := rows.Scan(&c.ID, &c.Name, &c.IsActive, &c.ParentID); err != nil {
+			return nil, err
+		}
+		categories = append(categories, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return models.NewCategoryTree(categories), nil
+}
@@ -0,0 +1,245 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"expense-tracker/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type sqlCostCenterRepository struct {
+	db     *sql.DB
+	fiscal models.FiscalCalendar
+}
+
+func NewCostCenterRepository(db *sql.DB, fiscal models.FiscalCalendar) CostCenterRepository {
+	return &sqlCostCenterRepository{db: db, fiscal: fiscal}
+}
+
+const costCenterColumns = `id, code, name, is_active, created_at, updated_at`
+
+func scanCostCenter(row interface{ Scan(...interface{}) error }) (*models.CostCenter, error) {
+	var cc models.CostCenter
+	if err := row.Scan(&cc.ID, &cc.Code, &cc.Name, &cc.IsActive, &cc.CreatedAt, &cc.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &cc, nil
+}
+
+func (r *sqlCostCenterRepository) GetAll(ctx context.Context, activeOnly bool) ([]models.CostCenter, error) {
+	query := "SELECT " + costCenterColumns + " FROM cost_centers"
+	if activeOnly {
+		query += " WHERE is_active = true"
+	}
+	query += " ORDER BY code ASC"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	costCenters := []models.CostCenter{}
+	for rows.Next() {
+		cc, err := scanCostCenter(rows)
+		if err != nil {
+			return nil, err
+		}
+		costCenters = append(costCenters, *cc)
+	}
+	return costCenters, rows.Err()
+}
+
+func (r *sqlCostCenterRepository) GetByID(ctx context.Context, id int) (*models.CostCenter, error) {
+	cc, err := scanCostCenter(r.db.QueryRowContext(ctx, "SELECT "+costCenterColumns+" FROM cost_centers WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, ErrCostCenterNotFound
+	}
+	return cc, err
+}
+
+func (r *sqlCostCenterRepository) Create(ctx context.Context, req models.CostCenterRequest) (*models.CostCenter, error) {
+	isActive := req.IsActive == nil || *req.IsActive
+	query := `INSERT INTO cost_centers (code, name, is_active) VALUES ($1, $2, $3) RETURNING ` + costCenterColumns
+	cc, err := scanCostCenter(r.db.QueryRowContext(ctx, query, req.Code, req.Name, isActive))
+	if err != nil {
+		return nil, costCenterWriteError(err)
+	}
+	return cc, nil
+}
+
+func (r *sqlCostCenterRepository) Update(ctx context.Context, id int, req models.CostCenterRequest) (*models.CostCenter, error) {
+	isActive := req.IsActive == nil || *req.IsActive
+	query := `UPDATE cost_centers SET code = $1, name = $2, is_active = $3, updated_at = CURRENT_TIMESTAMP
+	          WHERE id = $4 RETURNING ` + costCenterColumns
+	cc, err := scanCostCenter(r.db.QueryRowContext(ctx, query, req.Code, req.Name, isActive, id))
+	if err == sql.ErrNoRows {
+		return nil, ErrCostCenterNotFound
+	} else if err != nil {
+		return nil, costCenterWriteError(err)
+	}
+	return cc, nil
+}
+
+func costCenterWriteError(err error) error {
+	if strings.Contains(err.Error(), "cost_centers_code_key") {
+		return errors.New("cost center with this code already exists")
+	}
+	return err
+}
+
+const costCenterBudgetQuery = `SELECT b.id, b.cost_center_id, cc.code, cc.name, b.year, b.amount, b.period, b.phasing, b.is_locked, b.locked_at,
+	                 b.auto_lock_percent, b.reject_over_budget, b.auto_locked, b.created_at, b.updated_at
+	          FROM cost_center_budgets b
+	          JOIN cost_centers cc ON cc.id = b.cost_center_id`
+
+func scanCostCenterBudget(row interface{ Scan(...interface{}) error }) (*models.CostCenterBudget, error) {
+	var b models.CostCenterBudget
+	var phasing []string
+	err := row.Scan(&b.ID, &b.CostCenterID, &b.CostCenterCode, &b.CostCenterName, &b.Year, &b.Amount, &b.Period, pq.Array(&phasing), &b.IsLocked, &b.LockedAt,
+		&b.AutoLockPercent, &b.RejectOverBudget, &b.AutoLocked, &b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if b.Phasing, err = parsePhasing(phasing); err != nil {
+		return nil, err
+	}
+	return &b, nil
+}
+
+func (r *sqlCostCenterRepository) GetBudgets(ctx context.Context, year int) ([]models.CostCenterBudget, error) {
+	rows, err := r.db.QueryContext(ctx, costCenterBudgetQuery+" WHERE b.year = $1 ORDER BY cc.code ASC", year)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []models.CostCenterBudget{}
+	for rows.Next() {
+		b, err := scanCostCenterBudget(rows)
+		if err != nil {
+			return nil, err
+		}
+		budgets = append(budgets, *b)
+	}
+	return budgets, rows.Err()
+}
+
+func (r *sqlCostCenterRepository) GetBudgetByID(ctx context.Context, id int) (*models.CostCenterBudget, error) {
+	b, err := scanCostCenterBudget(r.db.QueryRowContext(ctx, costCenterBudgetQuery+" WHERE b.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("cost center budget not found")
+	}
+	return b, err
+}
+
+// GetBudget returns the cost center's budget for year, or nil if it has none.
+func (r *sqlCostCenterRepository) GetBudget(ctx context.Context, costCenterID, year int) (*models.CostCenterBudget, error) {
+	b, err := scanCostCenterBudget(r.db.QueryRowContext(ctx, costCenterBudgetQuery+" WHERE b.cost_center_id = $1 AND b.year = $2", costCenterID, year))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return b, err
+}
+
+func (r *sqlCostCenterRepository) SetBudget(ctx context.Context, req models.CostCenterBudgetRequest) (*models.CostCenterBudget, error) {
+	query := `INSERT INTO cost_center_budgets (cost_center_id, year, amount, period, phasing) VALUES ($1, $2, $3, $4, $5)
+	          ON CONFLICT (cost_center_id, year)
+	          DO UPDATE SET amount = EXCLUDED.amount, period = EXCLUDED.period, phasing = EXCLUDED.phasing, updated_at = CURRENT_TIMESTAMP
+	          RETURNING id`
+	var id int
+	if err := r.db.QueryRowContext(ctx, query, req.CostCenterID, req.Year, req.Amount, req.Period, phasingParam(req.Phasing)).Scan(&id); err != nil {
+		if strings.Contains(err.Error(), "cost_center_budgets_cost_center_id_fkey") {
+			return nil, ErrCostCenterNotFound
+		}
+		return nil, err
+	}
+	return r.GetBudgetByID(ctx, id)
+}
+
+func (r *sqlCostCenterRepository) ToggleLock(ctx context.Context, budgetID int, isLocked bool) error {
+	query := `UPDATE cost_center_budgets SET is_locked = $1, auto_locked = FALSE,
+	                 locked_at = CASE WHEN $1 THEN CURRENT_TIMESTAMP ELSE NULL END, updated_at = CURRENT_TIMESTAMP
+	          WHERE id = $2`
+	res, err := r.db.ExecContext(ctx, query, isLocked, budgetID)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *sqlCostCenterRepository) SetAutoLock(ctx context.Context, budgetID int, isLocked bool, lockedAt time.Time) error {
+	query := `UPDATE cost_center_budgets SET is_locked = $1, auto_locked = $1, locked_at = CASE WHEN $1 THEN $2::timestamp ELSE NULL END
+	          WHERE id = $3 AND (is_locked <> $1 OR ($1 AND locked_at IS DISTINCT FROM $2::timestamp))`
+	_, err := r.db.ExecContext(ctx, query, isLocked, lockedAt, budgetID)
+	return err
+}
+
+func (r *sqlCostCenterRepository) UpdatePolicy(ctx context.Context, budgetID int, policy models.BudgetPolicy) error {
+	query := `UPDATE cost_center_budgets SET auto_lock_percent = $1, reject_over_budget = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3`
+	res, err := r.db.ExecContext(ctx, query, policy.AutoLockPercent, policy.RejectOverBudget, budgetID)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// GetTotalBetween sums the cost center's expenses dated between start and
+// end (inclusive), leaving out rejected ones.
+func (r *sqlCostCenterRepository) GetTotalBetween(ctx context.Context, costCenterID int, start, end time.Time) (models.Money, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE cost_center_id = $1 AND expense_date BETWEEN $2 AND $3 AND approval_status <> 'rejected'`
+	var total models.Money
+	err := r.db.QueryRowContext(ctx, query, costCenterID, start, end).Scan(&total)
+	return total, err
+}
+
+// GetMonitoringData returns spend against budget for every cost center that
+// has a budget or spend in the fiscal year.
+func (r *sqlCostCenterRepository) GetMonitoringData(ctx context.Context, year int) ([]models.CostCenterMonitoringItem, error) {
+	start, end := r.fiscal.Bounds(year)
+	query := `SELECT COALESCE(b.id, 0), cc.id, cc.code, cc.name, COALESCE(b.amount, 0), COALESCE(s.spent, 0), COALESCE(b.is_locked, false),
+	                 COALESCE(b.period, 'annual'), b.phasing, b.locked_at
+	          FROM cost_centers cc
+	          LEFT JOIN cost_center_budgets b ON b.cost_center_id = cc.id AND b.year = $1
+	          LEFT JOIN (
+	              SELECT cost_center_id, SUM(amount) AS spent FROM expenses
+	              WHERE cost_center_id IS NOT NULL AND expense_date BETWEEN $2 AND $3 AND approval_status <> 'rejected'
+	              GROUP BY cost_center_id
+	          ) s ON s.cost_center_id = cc.id
+	          WHERE b.id IS NOT NULL OR s.spent IS NOT NULL
+	          ORDER BY cc.code ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, year, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []models.CostCenterMonitoringItem{}
+	for rows.Next() {
+		var item models.CostCenterMonitoringItem
+		var phasing []string
+		if err := rows.Scan(&item.BudgetID, &item.CostCenterID, &item.CostCenterCode, &item.CostCenterName, &item.BudgetAmount, &item.SpentAmount, &item.IsLocked,
+			&item.Period, pq.Array(&phasing), &item.LockedAt); err != nil {
+			return nil, err
+		}
+		if item.Phasing, err = parsePhasing(phasing); err != nil {
+			return nil, err
+		}
+		item.Remaining = item.BudgetAmount - item.SpentAmount
+		item.Percentage = item.SpentAmount.PercentOf(item.BudgetAmount)
+		items = append(items, item)
+	}
+	return items, rows.Err()
+}
@@ -0,0 +1,166 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"expense-tracker/internal/models"
+)
+
+type sqlDepartmentRepository struct {
+	db     *sql.DB
+	fiscal models.FiscalCalendar
+}
+
+func NewDepartmentRepository(db *sql.DB, fiscal models.FiscalCalendar) DepartmentRepository {
+	return &sqlDepartmentRepository{db: db, fiscal: fiscal}
+}
+
+const departmentQuery = `SELECT d.id, d.name, d.manager_id, COALESCE(m.username, ''),
+	                 (SELECT COUNT(*) FROM users u WHERE u.department_id = d.id), d.created_at, d.updated_at
+	          FROM departments d
+	          LEFT JOIN users m ON m.id = d.manager_id`
+
+func scanDepartment(row interface{ Scan(...interface{}) error }) (*models.Department, error) {
+	var d models.Department
+	if err := row.Scan(&d.ID, &d.Name, &d.ManagerID, &d.ManagerName, &d.MemberCount, &d.CreatedAt, &d.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+func (r *sqlDepartmentRepository) GetAll(ctx context.Context) ([]models.Department, error) {
+	rows, err := r.db.QueryContext(ctx, departmentQuery+" ORDER BY d.name ASC")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	departments := []models.Department{}
+	for rows.Next() {
+		d, err := scanDepartment(rows)
+		if err != nil {
+			return nil, err
+		}
+		departments = append(departments, *d)
+	}
+	return departments, rows.Err()
+}
+
+func (r *sqlDepartmentRepository) GetByID(ctx context.Context, id int) (*models.Department, error) {
+	d, err := scanDepartment(r.db.QueryRowContext(ctx, departmentQuery+" WHERE d.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("department not found")
+	}
+	return d, err
+}
+
+func (r *sqlDepartmentRepository) Create(ctx context.Context, req models.DepartmentRequest) (*models.Department, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, "INSERT INTO departments (name, manager_id) VALUES ($1, $2) RETURNING id", req.Name, req.ManagerID).Scan(&id)
+	if err != nil {
+		return nil, departmentWriteError(err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *sqlDepartmentRepository) Update(ctx context.Context, id int, req models.DepartmentRequest) (*models.Department, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE departments SET name = $1, manager_id = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3", req.Name, req.ManagerID, id)
+	if err != nil {
+		return nil, departmentWriteError(err)
+	}
+	if rowsAffected(res) == 0 {
+		return nil, errors.New("department not found")
+	}
+	return r.GetByID(ctx, id)
+}
+
+func departmentWriteError(err error) error {
+	if strings.Contains(err.Error(), "departments_name_key") {
+		return errors.New("department with this name already exists")
+	}
+	if strings.Contains(err.Error(), "departments_manager_id_fkey") {
+		return errors.New("manager not found")
+	}
+	return err
+}
+
+// ManagedBy returns the IDs of the departments userID manages.
+func (r *sqlDepartmentRepository) ManagedBy(ctx context.Context, userID int) ([]int, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT id FROM departments WHERE manager_id = $1 ORDER BY id", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *sqlDepartmentRepository) SetUserDepartment(ctx context.Context, userID int, departmentID *int) error {
+	res, err := r.db.ExecContext(ctx, "UPDATE users SET department_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2", departmentID, userID)
+	if err != nil {
+		if strings.Contains(err.Error(), "users_department_id_fkey") {
+			return errors.New("department not found")
+		}
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return errors.New("user not found")
+	}
+	return nil
+}
+
+// GetBudgets returns the department budgets for year with approved spend and
+// pending (committed) amounts. departmentIDs limits the result; nil means all.
+func (r *sqlDepartmentRepository) GetBudgets(ctx context.Context, year int, departmentIDs []int) ([]models.DepartmentBudget, error) {
+	start, end := r.fiscal.Bounds(year)
+	query := `SELECT b.id, d.id, d.name, b.year, b.amount,
+	                 COALESCE(SUM(e.amount) FILTER (WHERE e.approval_status = 'approved'), 0),
+	                 COALESCE(SUM(e.amount) FILTER (WHERE e.approval_status = 'pending'), 0)
+	          FROM department_budgets b
+	          JOIN departments d ON d.id = b.department_id
+	          LEFT JOIN expenses e ON e.department_id = b.department_id AND e.expense_date BETWEEN $2 AND $3
+	          WHERE b.year = $1 AND ($4::int[] IS NULL OR b.department_id = ANY($4))
+	          GROUP BY b.id, d.id, d.name, b.year, b.amount
+	          ORDER BY d.name ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, year, start, end, scopeArg(departmentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	budgets := []models.DepartmentBudget{}
+	for rows.Next() {
+		var b models.DepartmentBudget
+		if err := rows.Scan(&b.ID, &b.DepartmentID, &b.DepartmentName, &b.Year, &b.Amount, &b.SpentAmount, &b.Committed); err != nil {
+			return nil, err
+		}
+		b.Remaining = b.Amount - b.SpentAmount - b.Committed
+		b.Percentage = (b.SpentAmount + b.Committed).PercentOf(b.Amount)
+		budgets = append(budgets, b)
+	}
+	return budgets, rows.Err()
+}
+
+func (r *sqlDepartmentRepository) SetBudget(ctx context.Context, req models.DepartmentBudgetRequest) error {
+	query := `INSERT INTO department_budgets (department_id, year, amount) VALUES ($1, $2, $3)
+	          ON CONFLICT (department_id, year) DO UPDATE SET amount = EXCLUDED.amount, updated_at = CURRENT_TIMESTAMP`
+	if _, err := r.db.ExecContext(ctx, query, req.DepartmentID, req.Year, req.Amount); err != nil {
+		if strings.Contains(err.Error(), "department_budgets_department_id_fkey") {
+			return errors.New("department not found")
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package repository
+
+import "errors"
+
+// Circuit breaker errors returned when a new expense is refused. Handlers
+// report them as 403 regardless of which budget refused the expense.
+var (
+	ErrBudgetLocked = errors.New("spending is temporarily locked for this category")
+	ErrOverBudget   = errors.New("expense would exceed the budget for this category")
+
+	ErrCostCenterLocked     = errors.New("spending is temporarily locked for this cost center")
+	ErrCostCenterOverBudget = errors.New("expense would exceed the budget for this cost center")
+)
+
+// Cost center errors an expense can be refused with before any budget check.
+var (
+	ErrCostCenterNotFound = errors.New("cost center not found")
+	ErrCostCenterInactive = errors.New("cost center must be active")
+)
+
+// ErrExpenseInPayout is returned when deleting an expense that has already
+// been collected into a payout batch; the batch totals depend on it.
+var ErrExpenseInPayout = errors.New("expense is in a payout batch and cannot be deleted")
+
+// ErrExpenseReconciled is returned when matching a statement line to an
+// expense that another line is already matched to.
+var ErrExpenseReconciled = errors.New("expense is already reconciled")
+
+// ErrVendorExists is returned when a vendor name or alias is already taken
+// by another vendor's normalized name.
+var ErrVendorExists = errors.New("vendor with this name already exists")
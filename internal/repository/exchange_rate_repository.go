@@ -0,0 +1,94 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"expense-tracker/internal/models"
+)
+
+type sqlExchangeRateRepository struct {
+	db *sql.DB
+}
+
+func NewExchangeRateRepository(db *sql.DB) ExchangeRateRepository {
+	return &sqlExchangeRateRepository{db: db}
+}
+
+func (r *sqlExchangeRateRepository) Upsert(ctx context.Context, rate *models.ExchangeRate) error {
+	query := `INSERT INTO exchange_rates (base_currency, quote_currency, rate, effective_date, source)
+	          VALUES ($1, $2, $3, $4, $5)
+	          ON CONFLICT (base_currency, quote_currency, effective_date)
+	          DO UPDATE SET rate = EXCLUDED.rate, source = EXCLUDED.source
+	          RETURNING id, rate::text, created_at`
+
+	return r.db.QueryRowContext(ctx, query, rate.BaseCurrency, rate.QuoteCurrency, rate.Rate, rate.EffectiveDate, rate.Source).Scan(
+		&rate.ID, &rate.Rate, &rate.CreatedAt,
+	)
+}
+
+func (r *sqlExchangeRateRepository) GetAll(ctx context.Context, currency string) ([]models.ExchangeRate, error) {
+	query := `SELECT id, base_currency, quote_currency, rate::text, effective_date, source, created_at
+	          FROM exchange_rates`
+	var args []interface{}
+	if currency != "" {
+		query += " WHERE base_currency = $1 OR quote_currency = $1"
+		args = append(args, currency)
+	}
+	query += " ORDER BY effective_date DESC, base_currency, quote_currency LIMIT 500"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.ExchangeRate{}
+	for rows.Next() {
+		var rate models.ExchangeRate
+		if err := rows.Scan(&rate.ID, &rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate, &rate.EffectiveDate, &rate.Source, &rate.CreatedAt); err != nil {
+			return nil, err
+		}
+		rates = append(rates, rate)
+	}
+	return rates, rows.Err()
+}
+
+func (r *sqlExchangeRateRepository) FindEffective(ctx context.Context, base, quote string, date time.Time) (*models.ExchangeRate, error) {
+	query := `SELECT id, base_currency, quote_currency, rate::text, effective_date, source, created_at
+	          FROM exchange_rates
+	          WHERE base_currency = $1 AND quote_currency = $2 AND effective_date <= $3
+	          ORDER BY effective_date DESC
+	          LIMIT 1`
+
+	var rate models.ExchangeRate
+	err := r.db.QueryRowContext(ctx, query, base, quote, date).Scan(
+		&rate.ID, &rate.BaseCurrency, &rate.QuoteCurrency, &rate.Rate, &rate.EffectiveDate, &rate.Source, &rate.CreatedAt,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rate, nil
+}
+
+func (r *sqlExchangeRateRepository) FindCrossBase(ctx context.Context, from, to string, date time.Time) (string, error) {
+	query := `SELECT a.base_currency
+	          FROM exchange_rates a
+	          JOIN exchange_rates b ON a.base_currency = b.base_currency
+	          WHERE a.quote_currency = $1 AND b.quote_currency = $2
+	            AND a.effective_date <= $3 AND b.effective_date <= $3
+	          ORDER BY GREATEST(a.effective_date, b.effective_date) DESC
+	          LIMIT 1`
+
+	var base string
+	err := r.db.QueryRowContext(ctx, query, from, to, date).Scan(&base)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return base, err
+}
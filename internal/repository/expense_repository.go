@@ -1,102 +1,224 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"expense-tracker/internal/models"
 	"fmt"
 	"strings"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type sqlExpenseRepository struct {
-	db *sql.DB
+	db     *sql.DB
+	fiscal models.FiscalCalendar
 }
 
-func NewExpenseRepository(db *sql.DB) ExpenseRepository {
-	return &sqlExpenseRepository{db: db}
+func NewExpenseRepository(db *sql.DB, fiscal models.FiscalCalendar) ExpenseRepository {
+	return &sqlExpenseRepository{db: db, fiscal: fiscal}
 }
 
-func (r *sqlExpenseRepository) Create(req models.ExpenseRequest) (*models.Expense, error) {
+func (r *sqlExpenseRepository) Create(ctx context.Context, req models.ExpenseRequest) (*models.Expense, error) {
 	expenseDate, err := time.Parse("2006-01-02", req.ExpenseDate)
 	if err != nil {
 		return nil, fmt.Errorf("invalid date format: %v", err)
 	}
 
-	var e models.Expense
-	query := `INSERT INTO expenses (category_id, user_id, amount, expense_date, remarks) 
-	          VALUES ($1, $2, $3, $4, $5) 
-	          RETURNING id, category_id, user_id, amount, expense_date, remarks, created_at, updated_at`
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	status := models.ApprovalPending
+	if req.ApprovedBy != nil {
+		status = models.ApprovalApproved
+	}
+
+	var taxRateID *int
+	var taxRate *float64
+	var taxRecoverable *bool
+	var netAmount, taxAmount *models.Money
+	if req.Tax != nil {
+		taxRateID, taxRate, taxRecoverable = &req.Tax.Rate.ID, &req.Tax.Rate.Rate, &req.Tax.Rate.Recoverable
+		netAmount, taxAmount = &req.Tax.NetAmount, &req.Tax.TaxAmount
+	}
 
-	err = r.db.QueryRow(query, req.CategoryID, req.UserID, req.Amount, expenseDate, req.Remarks).Scan(
-		&e.ID, &e.CategoryID, &e.UserID, &e.Amount, &e.ExpenseDate, &e.Remarks, &e.CreatedAt, &e.UpdatedAt,
+	var e models.Expense
+	query := `INSERT INTO expenses (category_id, user_id, amount, expense_date, remarks, currency, original_amount, exchange_rate, suggested_category_id, suggestion_rule_id, cost_center_id,
+	                                department_id, approval_status, approved_by, approved_at, vendor_id, payment_method,
+	                                tax_rate_id, tax_rate, tax_recoverable, net_amount, tax_amount) 
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+	                  (SELECT department_id FROM users WHERE id = $2), $12, $13, CASE WHEN $13::int IS NULL THEN NULL ELSE CURRENT_TIMESTAMP END, $14, NULLIF($15, ''),
+	                  $16, $17, $18, $19, $20) 
+	          RETURNING id, category_id, user_id, amount, expense_date, remarks, currency, original_amount, exchange_rate::text, cost_center_id,
+	                    department_id, approval_status, approved_by, approved_at, vendor_id, COALESCE(payment_method, ''), created_at, updated_at,
+	                    tax_rate_id, tax_rate, tax_recoverable, net_amount, tax_amount`
+
+	err = tx.QueryRowContext(ctx, query, req.CategoryID, req.UserID, req.BaseAmount, expenseDate, req.Remarks, req.Currency, req.Amount, req.ExchangeRate, req.SuggestedCategoryID, req.SuggestionRuleID, req.CostCenterID,
+		status, req.ApprovedBy, req.VendorID, req.PaymentMethod,
+		taxRateID, taxRate, taxRecoverable, netAmount, taxAmount).Scan(
+		&e.ID, &e.CategoryID, &e.UserID, &e.Amount, &e.ExpenseDate, &e.Remarks, &e.Currency, &e.OriginalAmount, &e.ExchangeRate, &e.CostCenterID,
+		&e.DepartmentID, &e.ApprovalStatus, &e.ApprovedBy, &e.ApprovedAt, &e.VendorID, &e.PaymentMethod, &e.CreatedAt, &e.UpdatedAt,
+		&e.TaxRateID, &e.TaxRate, &e.TaxRecoverable, &e.NetAmount, &e.TaxAmount,
 	)
 
 	if err != nil {
 		return nil, err
 	}
 
+	if err := setExpenseTags(ctx, tx, e.ID, req.Tags); err != nil {
+		return nil, err
+	}
+	e.Tags = req.Tags
+	if e.Tags == nil {
+		e.Tags = []string{}
+	}
+	e.ReconciliationStatus = models.Unreconciled
+	if req.Tax != nil {
+		e.TaxRateName = req.Tax.Rate.Name
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
 	return &e, nil
 }
 
-func (r *sqlExpenseRepository) GetAll(filter models.ExpenseFilter) ([]models.Expense, error) {
-	query := `SELECT e.id, e.category_id, e.user_id, e.amount, e.expense_date, e.remarks, e.created_at, e.updated_at, c.name as category_name, COALESCE(u.username, 'System') as user_name
-	          FROM expenses e 
-	          JOIN categories c ON e.category_id = c.id
-	          LEFT JOIN users u ON e.user_id = u.id`
+// setExpenseTags creates any tags that do not exist yet and links them to
+// the expense. tags must already be normalized.
+func setExpenseTags(ctx context.Context, tx *sql.Tx, expenseID int, tags []string) error {
+	if len(tags) == 0 {
+		return nil
+	}
+	_, err := tx.ExecContext(ctx, `INSERT INTO tags (name) SELECT UNNEST($1::text[]) ON CONFLICT (name) DO NOTHING`, pq.Array(tags))
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO expense_tags (expense_id, tag_id)
+	          SELECT $1, id FROM tags WHERE name = ANY($2)
+	          ON CONFLICT DO NOTHING`, expenseID, pq.Array(tags))
+	return err
+}
 
+// tagCondition restricts idColumn (an expense id) to expenses carrying any or
+// all of filter.Tags. It returns "" when the filter has no tags.
+func tagCondition(filter models.ExpenseFilter, idColumn string, argCount int) (string, []interface{}) {
+	if len(filter.Tags) == 0 {
+		return "", nil
+	}
+	tagged := fmt.Sprintf(`SELECT et.expense_id FROM expense_tags et JOIN tags t ON t.id = et.tag_id WHERE t.name = ANY($%d)`, argCount)
+	if filter.TagMatch == models.TagMatchAll {
+		tagged += fmt.Sprintf(" GROUP BY et.expense_id HAVING COUNT(*) = $%d", argCount+1)
+		return idColumn + " IN (" + tagged + ")", []interface{}{pq.Array(filter.Tags), len(filter.Tags)}
+	}
+	return idColumn + " IN (" + tagged + ")", []interface{}{pq.Array(filter.Tags)}
+}
+
+// reconciledExpenses selects the ids of expenses matched to a statement line.
+const reconciledExpenses = `SELECT expense_id FROM bank_statement_lines WHERE expense_id IS NOT NULL`
+
+// scopeConditions builds the WHERE conditions for filter, shared by the
+// expense list and every insights query: dates, category (with its
+// subcategories), user, search, amount range, cost center, tags, department,
+// the departments a manager may see, approval status, vendor, payment method
+// and reconciliation. prefix is the expenses table alias ("e." or ""). With
+// spendOnly, rejected expenses are left out unless a status is asked for.
+func scopeConditions(filter models.ExpenseFilter, prefix string, spendOnly bool) ([]string, []interface{}) {
 	var conditions []string
 	var args []interface{}
-	argCount := 1
+	add := func(format string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(format, prefix, len(args)))
+	}
 
 	if filter.StartDate != "" {
-		conditions = append(conditions, fmt.Sprintf("e.expense_date >= $%d", argCount))
-		args = append(args, filter.StartDate)
-		argCount++
+		add("%sexpense_date >= $%d", filter.StartDate)
 	}
-
 	if filter.EndDate != "" {
-		conditions = append(conditions, fmt.Sprintf("e.expense_date <= $%d", argCount))
-		args = append(args, filter.EndDate)
-		argCount++
+		add("%sexpense_date <= $%d", filter.EndDate)
 	}
-
 	if filter.CategoryID > 0 {
-		conditions = append(conditions, fmt.Sprintf("e.category_id = $%d", argCount))
 		args = append(args, filter.CategoryID)
-		argCount++
+		conditions = append(conditions, prefix+"category_id IN "+categorySubtree(fmt.Sprintf("$%d", len(args))))
 	}
-
 	if filter.UserID > 0 {
-		conditions = append(conditions, fmt.Sprintf("e.user_id = $%d", argCount))
-		args = append(args, filter.UserID)
-		argCount++
+		add("%suser_id = $%d", filter.UserID)
 	}
-
 	if filter.SearchText != "" {
-		conditions = append(conditions, fmt.Sprintf("e.remarks ILIKE $%d", argCount))
-		args = append(args, "%"+filter.SearchText+"%")
-		argCount++
+		add("%sremarks ILIKE $%d", "%"+filter.SearchText+"%")
 	}
-
 	if filter.MinAmount > 0 {
-		conditions = append(conditions, fmt.Sprintf("e.amount >= $%d", argCount))
-		args = append(args, filter.MinAmount)
-		argCount++
+		add("%samount >= $%d", filter.MinAmount)
 	}
-
 	if filter.MaxAmount > 0 {
-		conditions = append(conditions, fmt.Sprintf("e.amount <= $%d", argCount))
-		args = append(args, filter.MaxAmount)
-		argCount++
+		add("%samount <= $%d", filter.MaxAmount)
 	}
+	if filter.CostCenterID > 0 {
+		add("%scost_center_id = $%d", filter.CostCenterID)
+	}
+	if cond, tagArgs := tagCondition(filter, prefix+"id", len(args)+1); cond != "" {
+		conditions = append(conditions, cond)
+		args = append(args, tagArgs...)
+	}
+	if filter.DepartmentID > 0 {
+		add("%sdepartment_id = $%d", filter.DepartmentID)
+	}
+	if filter.VisibleDepartments != nil {
+		add("%sdepartment_id = ANY($%d)", pq.Array(filter.VisibleDepartments))
+	}
+	if filter.ApprovalStatus != "" {
+		add("%sapproval_status = $%d", filter.ApprovalStatus)
+	} else if spendOnly {
+		conditions = append(conditions, prefix+"approval_status <> 'rejected'")
+	}
+	if filter.VendorID > 0 {
+		add("%svendor_id = $%d", filter.VendorID)
+	}
+	if filter.PaymentMethod != "" {
+		add("%spayment_method = $%d", filter.PaymentMethod)
+	}
+	switch filter.Reconciliation {
+	case models.Reconciled:
+		conditions = append(conditions, prefix+"id IN ("+reconciledExpenses+")")
+	case models.Unreconciled:
+		conditions = append(conditions, prefix+"id NOT IN ("+reconciledExpenses+")")
+	}
+	return conditions, args
+}
 
-	if len(conditions) > 0 {
-		query += " WHERE " + strings.Join(conditions, " AND ")
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return ""
 	}
+	return " WHERE " + strings.Join(conditions, " AND ")
+}
+
+func (r *sqlExpenseRepository) GetAll(ctx context.Context, filter models.ExpenseFilter) ([]models.Expense, error) {
+	query := `SELECT e.id, e.category_id, e.user_id, e.amount, e.expense_date, e.remarks, e.currency, COALESCE(e.original_amount, e.amount), e.exchange_rate::text, e.created_at, e.updated_at, c.name as category_name, COALESCE(u.username, 'System') as user_name,
+	                 ARRAY(SELECT t.name FROM expense_tags et JOIN tags t ON t.id = et.tag_id WHERE et.expense_id = e.id ORDER BY t.name) as tags,
+	                 e.cost_center_id, COALESCE(cc.code, '') as cost_center_code,
+	                 e.department_id, COALESCE(d.name, '') as department_name, e.approval_status, e.approved_by, e.approved_at, COALESCE(e.approval_note, ''),
+	                 e.vendor_id, COALESCE(v.name, '') as vendor_name, COALESCE(e.payment_method, ''), e.payout_batch_id,
+	                 CASE WHEN e.id IN (` + reconciledExpenses + `) THEN 'reconciled' ELSE 'unreconciled' END,
+	                 e.tax_rate_id, COALESCE(tr.name, ''), e.tax_rate, e.tax_recoverable, e.net_amount, e.tax_amount
+	          FROM expenses e 
+	          JOIN categories c ON e.category_id = c.id
+	          LEFT JOIN users u ON e.user_id = u.id
+	          LEFT JOIN cost_centers cc ON e.cost_center_id = cc.id
+	          LEFT JOIN departments d ON e.department_id = d.id
+	          LEFT JOIN vendors v ON e.vendor_id = v.id
+	          LEFT JOIN tax_rates tr ON e.tax_rate_id = tr.id`
+
+	conditions, args := scopeConditions(filter, "e.", false)
+	query += whereClause(conditions)
 
 	query += " ORDER BY e.expense_date DESC, e.created_at DESC"
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -105,7 +227,10 @@ func (r *sqlExpenseRepository) GetAll(filter models.ExpenseFilter) ([]models.Exp
 	expenses := []models.Expense{}
 	for rows.Next() {
 		var e models.Expense
-		err := rows.Scan(&e.ID, &e.CategoryID, &e.UserID, &e.Amount, &e.ExpenseDate, &e.Remarks, &e.CreatedAt, &e.UpdatedAt, &e.CategoryName, &e.UserName)
+		err := rows.Scan(&e.ID, &e.CategoryID, &e.UserID, &e.Amount, &e.ExpenseDate, &e.Remarks, &e.Currency, &e.OriginalAmount, &e.ExchangeRate, &e.CreatedAt, &e.UpdatedAt, &e.CategoryName, &e.UserName, pq.Array(&e.Tags), &e.CostCenterID, &e.CostCenterCode,
+			&e.DepartmentID, &e.DepartmentName, &e.ApprovalStatus, &e.ApprovedBy, &e.ApprovedAt, &e.ApprovalNote,
+			&e.VendorID, &e.VendorName, &e.PaymentMethod, &e.PayoutBatchID, &e.ReconciliationStatus,
+			&e.TaxRateID, &e.TaxRateName, &e.TaxRate, &e.TaxRecoverable, &e.NetAmount, &e.TaxAmount)
 		if err != nil {
 			return nil, err
 		}
@@ -115,22 +240,68 @@ func (r *sqlExpenseRepository) GetAll(filter models.ExpenseFilter) ([]models.Exp
 	return expenses, nil
 }
 
-func (r *sqlExpenseRepository) Delete(id int) error {
-	_, err := r.db.Exec("DELETE FROM expenses WHERE id = $1", id)
-	return err
+// Delete removes an expense unless it has been collected into a payout
+// batch, which would leave the batch totals wrong.
+func (r *sqlExpenseRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM expenses WHERE id = $1 AND payout_batch_id IS NULL", id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) > 0 {
+		return nil
+	}
+	var batched bool
+	err = r.db.QueryRowContext(ctx, "SELECT payout_batch_id IS NOT NULL FROM expenses WHERE id = $1", id).Scan(&batched)
+	if err == sql.ErrNoRows {
+		return errors.New("expense not found")
+	}
+	if err != nil {
+		return err
+	}
+	if batched {
+		return ErrExpenseInPayout
+	}
+	return nil
+}
+
+func (r *sqlExpenseRepository) GetByID(ctx context.Context, id int) (*models.Expense, error) {
+	var e models.Expense
+	query := `SELECT id, category_id, user_id, amount, expense_date, remarks, cost_center_id, department_id, approval_status, approved_by, approved_at, COALESCE(approval_note, ''),
+	                 vendor_id, COALESCE(payment_method, ''), payout_batch_id
+	          FROM expenses WHERE id = $1`
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&e.ID, &e.CategoryID, &e.UserID, &e.Amount, &e.ExpenseDate, &e.Remarks, &e.CostCenterID,
+		&e.DepartmentID, &e.ApprovalStatus, &e.ApprovedBy, &e.ApprovedAt, &e.ApprovalNote, &e.VendorID, &e.PaymentMethod, &e.PayoutBatchID)
+	if err == sql.ErrNoRows {
+		return nil, errors.New("expense not found")
+	}
+	return &e, err
+}
+
+// SetApproval approves or rejects a pending expense.
+func (r *sqlExpenseRepository) SetApproval(ctx context.Context, id int, status models.ApprovalStatus, userID int, note string) error {
+	query := `UPDATE expenses SET approval_status = $1, approved_by = $2, approved_at = CURRENT_TIMESTAMP, approval_note = NULLIF($3, ''), updated_at = CURRENT_TIMESTAMP
+	          WHERE id = $4 AND approval_status = 'pending'`
+	res, err := r.db.ExecContext(ctx, query, status, userID, note, id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return errors.New("expense is no longer pending approval")
+	}
+	return nil
 }
 
-func (r *sqlExpenseRepository) GetInsights(filter models.ExpenseFilter) (*models.ExpenseInsights, error) {
+func (r *sqlExpenseRepository) GetInsights(ctx context.Context, filter models.ExpenseFilter) (*models.ExpenseInsights, error) {
 	insights := &models.ExpenseInsights{}
 
-	currentStats, err := r.getPeriodStats(filter)
+	currentStats, err := r.getPeriodStats(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 	insights.TotalSpent = currentStats.total
 	insights.TransactionCount = currentStats.count
 	if currentStats.count > 0 {
-		insights.AverageExpense = currentStats.total / float64(currentStats.count)
+		insights.AverageExpense = currentStats.total.Div(int64(currentStats.count))
 	}
 
 	if filter.StartDate != "" && filter.EndDate != "" {
@@ -138,30 +309,51 @@ func (r *sqlExpenseRepository) GetInsights(filter models.ExpenseFilter) (*models
 		end, _ := time.Parse("2006-01-02", filter.EndDate)
 		duration := end.Sub(start)
 
-		prevFilter := models.ExpenseFilter{
-			StartDate:  start.Add(-duration - 24*time.Hour).Format("2006-01-02"),
-			EndDate:    start.Add(-24 * time.Hour).Format("2006-01-02"),
-			CategoryID: filter.CategoryID,
-			UserID:     filter.UserID,
-		}
-		prevStats, err := r.getPeriodStats(prevFilter)
+		prevFilter := filter
+		prevFilter.StartDate = start.Add(-duration - 24*time.Hour).Format("2006-01-02")
+		prevFilter.EndDate = start.Add(-24 * time.Hour).Format("2006-01-02")
+		prevStats, err := r.getPeriodStats(ctx, prevFilter)
 		if err == nil {
 			insights.PreviousPeriodTotal = prevStats.total
 			if prevStats.total > 0 {
-				insights.SpendingChange = ((currentStats.total - prevStats.total) / prevStats.total) * 100
+				insights.SpendingChange = (currentStats.total - prevStats.total).PercentOf(prevStats.total)
 			} else if currentStats.total > 0 {
 				insights.SpendingChange = 100
 			}
 		}
 	}
 
-	topCategories, err := r.getTopCategories(filter)
+	topCategories, err := r.getTopCategories(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
 	insights.TopCategories = topCategories
 
-	spendingByDay, err := r.getSpendingByDay(filter)
+	topLevel, err := r.getTopLevelCategories(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	insights.TopLevelCategories = topLevel
+
+	topTags, err := r.getTopTags(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	insights.TopTags = topTags
+
+	topDepartments, err := r.getTopDepartments(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	insights.TopDepartments = topDepartments
+
+	topVendors, err := r.getTopVendors(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	insights.TopVendors = topVendors
+
+	spendingByDay, err := r.getSpendingByDay(ctx, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -171,73 +363,58 @@ func (r *sqlExpenseRepository) GetInsights(filter models.ExpenseFilter) (*models
 }
 
 type periodStats struct {
-	total float64
+	total models.Money
 	count int
 }
 
-func (r *sqlExpenseRepository) getPeriodStats(filter models.ExpenseFilter) (*periodStats, error) {
-	query := `SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM expenses WHERE 1=1`
-	var args []interface{}
-	argCount := 1
-
-	if filter.StartDate != "" {
-		query += fmt.Sprintf(" AND expense_date >= $%d", argCount)
-		args = append(args, filter.StartDate)
-		argCount++
-	}
-	if filter.EndDate != "" {
-		query += fmt.Sprintf(" AND expense_date <= $%d", argCount)
-		args = append(args, filter.EndDate)
-		argCount++
-	}
-	if filter.CategoryID > 0 {
-		query += fmt.Sprintf(" AND category_id = $%d", argCount)
-		args = append(args, filter.CategoryID)
-		argCount++
-	}
-	if filter.UserID > 0 {
-		query += fmt.Sprintf(" AND user_id = $%d", argCount)
-		args = append(args, filter.UserID)
-		argCount++
-	}
+func (r *sqlExpenseRepository) getPeriodStats(ctx context.Context, filter models.ExpenseFilter) (*periodStats, error) {
+	query := `SELECT COALESCE(SUM(amount), 0), COUNT(*) FROM expenses`
+	conditions, args := scopeConditions(filter, "", true)
+	query += whereClause(conditions)
 
 	var stats periodStats
-	err := r.db.QueryRow(query, args...).Scan(&stats.total, &stats.count)
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&stats.total, &stats.count)
 	return &stats, err
 }
 
-func (r *sqlExpenseRepository) getTopCategories(filter models.ExpenseFilter) ([]models.CategorySpending, error) {
+func (r *sqlExpenseRepository) getTopCategories(ctx context.Context, filter models.ExpenseFilter) ([]models.CategorySpending, error) {
 	query := `SELECT e.category_id, c.name, COALESCE(SUM(e.amount), 0) as total, COUNT(*) as cnt
 	          FROM expenses e
-	          JOIN categories c ON e.category_id = c.id
-	          WHERE 1=1`
-	var args []interface{}
-	argCount := 1
+	          JOIN categories c ON e.category_id = c.id`
+	conditions, args := scopeConditions(filter, "e.", true)
+	query += whereClause(conditions)
 
-	if filter.StartDate != "" {
-		query += fmt.Sprintf(" AND e.expense_date >= $%d", argCount)
-		args = append(args, filter.StartDate)
-		argCount++
-	}
-	if filter.EndDate != "" {
-		query += fmt.Sprintf(" AND e.expense_date <= $%d", argCount)
-		args = append(args, filter.EndDate)
-		argCount++
-	}
-	if filter.CategoryID > 0 {
-		query += fmt.Sprintf(" AND e.category_id = $%d", argCount)
-		args = append(args, filter.CategoryID)
-		argCount++
+	query += " GROUP BY e.category_id, c.name ORDER BY total DESC LIMIT 5"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
-	if filter.UserID > 0 {
-		query += fmt.Sprintf(" AND e.user_id = $%d", argCount)
-		args = append(args, filter.UserID)
-		argCount++
+	defer rows.Close()
+
+	var results []models.CategorySpending
+	for rows.Next() {
+		var cs models.CategorySpending
+		if err := rows.Scan(&cs.CategoryID, &cs.CategoryName, &cs.TotalAmount, &cs.Count); err != nil {
+			return nil, err
+		}
+		results = append(results, cs)
 	}
+	return results, nil
+}
 
-	query += " GROUP BY e.category_id, c.name ORDER BY total DESC LIMIT 5"
+// getTopLevelCategories rolls spend up to the root of each category's tree.
+func (r *sqlExpenseRepository) getTopLevelCategories(ctx context.Context, filter models.ExpenseFilter) ([]models.CategorySpending, error) {
+	query := categoryClosureCTE + `SELECT c.id, c.name, COALESCE(SUM(e.amount), 0) as total, COUNT(*) as cnt
+	          FROM expenses e
+	          JOIN category_closure cc ON cc.category_id = e.category_id
+	          JOIN categories c ON c.id = cc.ancestor_id AND c.parent_id IS NULL`
+	conditions, args := scopeConditions(filter, "e.", true)
+	query += whereClause(conditions)
 
-	rows, err := r.db.Query(query, args...)
+	query += " GROUP BY c.id, c.name ORDER BY total DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -254,39 +431,97 @@ func (r *sqlExpenseRepository) getTopCategories(filter models.ExpenseFilter) ([]
 	return results, nil
 }
 
-func (r *sqlExpenseRepository) getSpendingByDay(filter models.ExpenseFilter) ([]models.DaySpending, error) {
-	dayNames := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+func (r *sqlExpenseRepository) getTopTags(ctx context.Context, filter models.ExpenseFilter) ([]models.TagSpending, error) {
+	query := `SELECT t.name, COALESCE(SUM(e.amount), 0) as total, COUNT(*) as cnt
+	          FROM expenses e
+	          JOIN expense_tags et ON et.expense_id = e.id
+	          JOIN tags t ON t.id = et.tag_id`
+	conditions, args := scopeConditions(filter, "e.", true)
+	query += whereClause(conditions)
 
-	query := `SELECT EXTRACT(DOW FROM expense_date)::int as dow, 
-	                 COALESCE(SUM(amount), 0) as total, COUNT(*) as cnt
-	          FROM expenses WHERE 1=1`
-	var args []interface{}
-	argCount := 1
+	query += " GROUP BY t.name ORDER BY total DESC LIMIT 10"
 
-	if filter.StartDate != "" {
-		query += fmt.Sprintf(" AND expense_date >= $%d", argCount)
-		args = append(args, filter.StartDate)
-		argCount++
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
-	if filter.EndDate != "" {
-		query += fmt.Sprintf(" AND expense_date <= $%d", argCount)
-		args = append(args, filter.EndDate)
-		argCount++
+	defer rows.Close()
+
+	var results []models.TagSpending
+	for rows.Next() {
+		var ts models.TagSpending
+		if err := rows.Scan(&ts.Tag, &ts.TotalAmount, &ts.Count); err != nil {
+			return nil, err
+		}
+		results = append(results, ts)
 	}
-	if filter.CategoryID > 0 {
-		query += fmt.Sprintf(" AND category_id = $%d", argCount)
-		args = append(args, filter.CategoryID)
-		argCount++
+	return results, nil
+}
+
+func (r *sqlExpenseRepository) getTopDepartments(ctx context.Context, filter models.ExpenseFilter) ([]models.DepartmentSpending, error) {
+	query := `SELECT e.department_id, COALESCE(d.name, 'Unassigned'), COALESCE(SUM(e.amount), 0) as total, COUNT(*) as cnt
+	          FROM expenses e
+	          LEFT JOIN departments d ON d.id = e.department_id`
+	conditions, args := scopeConditions(filter, "e.", true)
+	query += whereClause(conditions)
+
+	query += " GROUP BY e.department_id, d.name ORDER BY total DESC LIMIT 10"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
-	if filter.UserID > 0 {
-		query += fmt.Sprintf(" AND user_id = $%d", argCount)
-		args = append(args, filter.UserID)
-		argCount++
+	defer rows.Close()
+
+	var results []models.DepartmentSpending
+	for rows.Next() {
+		var ds models.DepartmentSpending
+		if err := rows.Scan(&ds.DepartmentID, &ds.DepartmentName, &ds.TotalAmount, &ds.Count); err != nil {
+			return nil, err
+		}
+		results = append(results, ds)
+	}
+	return results, nil
+}
+
+func (r *sqlExpenseRepository) getTopVendors(ctx context.Context, filter models.ExpenseFilter) ([]models.VendorSpending, error) {
+	query := `SELECT v.id, v.name, COALESCE(SUM(e.amount), 0) as total, COUNT(*) as cnt
+	          FROM expenses e
+	          JOIN vendors v ON v.id = e.vendor_id`
+	conditions, args := scopeConditions(filter, "e.", true)
+	query += whereClause(conditions)
+
+	query += " GROUP BY v.id, v.name ORDER BY total DESC LIMIT 10"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
 	}
+	defer rows.Close()
+
+	var results []models.VendorSpending
+	for rows.Next() {
+		var vs models.VendorSpending
+		if err := rows.Scan(&vs.VendorID, &vs.VendorName, &vs.TotalAmount, &vs.Count); err != nil {
+			return nil, err
+		}
+		results = append(results, vs)
+	}
+	return results, nil
+}
+
+func (r *sqlExpenseRepository) getSpendingByDay(ctx context.Context, filter models.ExpenseFilter) ([]models.DaySpending, error) {
+	dayNames := []string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"}
+
+	query := `SELECT EXTRACT(DOW FROM expense_date)::int as dow, 
+	                 COALESCE(SUM(amount), 0) as total, COUNT(*) as cnt
+	          FROM expenses`
+	conditions, args := scopeConditions(filter, "", true)
+	query += whereClause(conditions)
 
 	query += " GROUP BY dow ORDER BY cnt DESC"
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -304,9 +539,47 @@ func (r *sqlExpenseRepository) getSpendingByDay(filter models.ExpenseFilter) ([]
 	return results, nil
 }
 
-func (r *sqlExpenseRepository) GetYearlyTotal(categoryID, year int) (float64, error) {
-	query := `SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE category_id = $1 AND EXTRACT(YEAR FROM expense_date) = $2`
-	var total float64
-	err := r.db.QueryRow(query, categoryID, year).Scan(&total)
+func (r *sqlExpenseRepository) GetYearlyTotal(ctx context.Context, categoryID, year int) (models.Money, error) {
+	start, end := r.fiscal.Bounds(year)
+	query := `SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE category_id IN ` + categorySubtree("$1") + ` AND expense_date BETWEEN $2 AND $3 AND approval_status <> 'rejected'`
+	var total models.Money
+	err := r.db.QueryRowContext(ctx, query, categoryID, start, end).Scan(&total)
 	return total, err
 }
+
+func (r *sqlExpenseRepository) GetTotalBetween(ctx context.Context, categoryID int, start, end time.Time) (models.Money, error) {
+	query := `SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE category_id IN ` + categorySubtree("$1") + ` AND expense_date BETWEEN $2 AND $3 AND approval_status <> 'rejected'`
+	var total models.Money
+	err := r.db.QueryRowContext(ctx, query, categoryID, start, end).Scan(&total)
+	return total, err
+}
+
+func (r *sqlExpenseRepository) GetMonthlyTotals(ctx context.Context, year int) (map[int][]models.Money, error) {
+	start, end := r.fiscal.Bounds(year)
+	query := categoryClosureCTE + `SELECT cc.ancestor_id, EXTRACT(MONTH FROM e.expense_date)::int AS month, COALESCE(SUM(e.amount), 0)
+	          FROM expenses e
+	          JOIN category_closure cc ON cc.category_id = e.category_id
+	          WHERE e.expense_date BETWEEN $1 AND $2 AND e.approval_status <> 'rejected'
+	          GROUP BY cc.ancestor_id, month`
+
+	rows, err := r.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	totals := map[int][]models.Money{}
+	for rows.Next() {
+		var categoryID, month int
+		var total models.Money
+		if err := rows.Scan(&categoryID, &month, &total); err != nil {
+			return nil, err
+		}
+		if _, ok := totals[categoryID]; !ok {
+			totals[categoryID] = make([]models.Money, 12)
+		}
+		fiscalMonth := r.fiscal.MonthOf(time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC))
+		totals[categoryID][fiscalMonth-1] = total
+	}
+	return totals, rows.Err()
+}
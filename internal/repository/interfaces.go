@@ -1,45 +1,173 @@
 package repository
 
 import (
+	"context"
 	"expense-tracker/internal/models"
 	"time"
 )
 
 type CategoryRepository interface {
-	GetAll(activeOnly bool) ([]models.Category, error)
-	GetByID(id int) (*models.Category, error)
-	Create(name string, isActive bool) (*models.Category, error)
-	Update(id int, name string, isActive bool) (*models.Category, error)
-	ToggleStatus(id int) (*models.Category, error)
-	ExistsByName(name string) (bool, error)
+	GetAll(ctx context.Context, activeOnly bool) ([]models.Category, error)
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	Create(ctx context.Context, name string, isActive bool, parentID *int) (*models.Category, error)
+	Update(ctx context.Context, id int, name string, isActive bool, parentID *int) (*models.Category, error)
+	ToggleStatus(ctx context.Context, id int) (*models.Category, error)
+	ExistsByName(ctx context.Context, name string) (bool, error)
+	Merge(ctx context.Context, sourceID, targetID, userID int) (*models.CategoryMerge, error)
+	Delete(ctx context.Context, id int) error
+}
+
+type CategorizationRuleRepository interface {
+	GetAll(ctx context.Context, activeOnly bool) ([]models.CategorizationRule, error)
+	GetByID(ctx context.Context, id int) (*models.CategorizationRule, error)
+	Create(ctx context.Context, req models.CategorizationRuleRequest) (*models.CategorizationRule, error)
+	Update(ctx context.Context, id int, req models.CategorizationRuleRequest) (*models.CategorizationRule, error)
+	Delete(ctx context.Context, id int) error
+	GetOverrideStats(ctx context.Context, start, end string) ([]models.RuleOverrideStats, error)
 }
 
 type BudgetRepository interface {
-	GetAll(year int) ([]models.Budget, error)
-	CreateOrUpdate(categoryID int, amount float64, year int) (*models.Budget, error)
-	GetDashboardSummary(year int) (*models.BudgetDashboardSummary, error)
-	GetByCategory(categoryID, year int) (*models.Budget, error)
-	GetMonitoringData(year int) ([]models.BudgetMonitoringItem, error)
-	ToggleLock(budgetID int, isLocked bool) error
-	IsLocked(categoryID, year int) (bool, error)
+	GetAll(ctx context.Context, year int) ([]models.Budget, error)
+	CreateOrUpdate(ctx context.Context, req models.BudgetRequest) (*models.Budget, error)
+	CreateOrUpdateMany(ctx context.Context, reqs []models.BudgetRequest) ([]models.Budget, error)
+	GetDashboardSummary(ctx context.Context, year int) (*models.BudgetDashboardSummary, error)
+	SetSavingsTarget(ctx context.Context, year int, amount models.Money) error
+	GetByID(ctx context.Context, id int) (*models.Budget, error)
+	GetByCategory(ctx context.Context, categoryID, year int) (*models.Budget, error)
+	GetByCategoryPath(ctx context.Context, categoryID, year int) ([]models.Budget, error)
+	GetMonitoringData(ctx context.Context, year int) ([]models.BudgetMonitoringItem, error)
+	ToggleLock(ctx context.Context, budgetID int, isLocked bool, userID int) error
+	SetAutoLock(ctx context.Context, budgetID int, isLocked bool, lockedAt time.Time, reason string) error
+	UpdatePolicy(ctx context.Context, budgetID int, policy models.BudgetPolicy) error
+	SetOverride(ctx context.Context, budgetID int, until *time.Time, reason string, userID int) error
+	GetLockEvents(ctx context.Context, budgetID int) ([]models.BudgetLockEvent, error)
+	UpdateAlertThresholds(ctx context.Context, budgetID int, thresholds []float64) error
+	RecordAlert(ctx context.Context, budgetID int, threshold float64, periodStart time.Time, percent float64, notifications []models.Notification) (bool, error)
+	IsLocked(ctx context.Context, categoryID int, date time.Time) (bool, error)
 }
 
 type UserRepository interface {
-	Create(user *models.User) error
-	GetByID(id int) (*models.User, error)
-	GetByEmail(email string) (*models.User, error)
-	GetByDisplayID(displayID string) (*models.User, error)
-	UpdatePassword(id int, passwordHash string) error
-	UpdateRole(id int, role models.UserRole) error
-	SetPasswordToken(email, token string, expiry time.Time) error
-	GetByToken(token string) (*models.User, error)
-	GetAll() ([]models.User, error)
+	Create(ctx context.Context, user *models.User) error
+	GetByID(ctx context.Context, id int) (*models.User, error)
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByDisplayID(ctx context.Context, displayID string) (*models.User, error)
+	UpdatePassword(ctx context.Context, id int, passwordHash string) error
+	UpdateRole(ctx context.Context, id int, role models.UserRole) error
+	SetPasswordToken(ctx context.Context, email, token string, expiry time.Time) error
+	GetByToken(ctx context.Context, token string) (*models.User, error)
+	GetAll(ctx context.Context) ([]models.User, error)
 }
 
 type ExpenseRepository interface {
-	Create(req models.ExpenseRequest) (*models.Expense, error)
-	GetAll(filter models.ExpenseFilter) ([]models.Expense, error)
-	Delete(id int) error
-	GetInsights(filter models.ExpenseFilter) (*models.ExpenseInsights, error)
-	GetYearlyTotal(categoryID, year int) (float64, error)
+	Create(ctx context.Context, req models.ExpenseRequest) (*models.Expense, error)
+	GetAll(ctx context.Context, filter models.ExpenseFilter) ([]models.Expense, error)
+	Delete(ctx context.Context, id int) error
+	GetByID(ctx context.Context, id int) (*models.Expense, error)
+	SetApproval(ctx context.Context, id int, status models.ApprovalStatus, userID int, note string) error
+	GetInsights(ctx context.Context, filter models.ExpenseFilter) (*models.ExpenseInsights, error)
+	GetYearlyTotal(ctx context.Context, categoryID, year int) (models.Money, error)
+	GetTotalBetween(ctx context.Context, categoryID int, start, end time.Time) (models.Money, error)
+	GetMonthlyTotals(ctx context.Context, year int) (map[int][]models.Money, error)
+}
+
+type CostCenterRepository interface {
+	GetAll(ctx context.Context, activeOnly bool) ([]models.CostCenter, error)
+	GetByID(ctx context.Context, id int) (*models.CostCenter, error)
+	Create(ctx context.Context, req models.CostCenterRequest) (*models.CostCenter, error)
+	Update(ctx context.Context, id int, req models.CostCenterRequest) (*models.CostCenter, error)
+	GetBudgets(ctx context.Context, year int) ([]models.CostCenterBudget, error)
+	GetBudgetByID(ctx context.Context, id int) (*models.CostCenterBudget, error)
+	GetBudget(ctx context.Context, costCenterID, year int) (*models.CostCenterBudget, error)
+	SetBudget(ctx context.Context, req models.CostCenterBudgetRequest) (*models.CostCenterBudget, error)
+	ToggleLock(ctx context.Context, budgetID int, isLocked bool) error
+	SetAutoLock(ctx context.Context, budgetID int, isLocked bool, lockedAt time.Time) error
+	UpdatePolicy(ctx context.Context, budgetID int, policy models.BudgetPolicy) error
+	GetTotalBetween(ctx context.Context, costCenterID int, start, end time.Time) (models.Money, error)
+	GetMonitoringData(ctx context.Context, year int) ([]models.CostCenterMonitoringItem, error)
+}
+
+type DepartmentRepository interface {
+	GetAll(ctx context.Context) ([]models.Department, error)
+	GetByID(ctx context.Context, id int) (*models.Department, error)
+	Create(ctx context.Context, req models.DepartmentRequest) (*models.Department, error)
+	Update(ctx context.Context, id int, req models.DepartmentRequest) (*models.Department, error)
+	ManagedBy(ctx context.Context, userID int) ([]int, error)
+	SetUserDepartment(ctx context.Context, userID int, departmentID *int) error
+	GetBudgets(ctx context.Context, year int, departmentIDs []int) ([]models.DepartmentBudget, error)
+	SetBudget(ctx context.Context, req models.DepartmentBudgetRequest) error
+}
+
+type VendorRepository interface {
+	GetAll(ctx context.Context, search string) ([]models.Vendor, error)
+	GetByID(ctx context.Context, id int) (*models.Vendor, error)
+	FindByNormalizedName(ctx context.Context, normalized string) (*models.Vendor, error)
+	Create(ctx context.Context, name, normalized string) (*models.Vendor, error)
+	Update(ctx context.Context, id int, name, normalized string) (*models.Vendor, error)
+	AddAlias(ctx context.Context, vendorID int, alias string) error
+	RemoveAlias(ctx context.Context, vendorID int, alias string) error
+	Merge(ctx context.Context, sourceID, targetID int) (*models.VendorMerge, error)
+}
+
+type PayoutRepository interface {
+	GetPending(ctx context.Context, departmentIDs []int) ([]models.PendingReimbursement, error)
+	CreateBatches(ctx context.Context, req models.PayoutBatchRequest, departmentIDs []int, currency string, createdBy int) ([]models.PayoutBatch, error)
+	GetAll(ctx context.Context, status models.PayoutStatus, departmentIDs []int) ([]models.PayoutBatch, error)
+	GetByIDs(ctx context.Context, ids []int, departmentIDs []int) ([]models.PayoutBatch, error)
+	GetByID(ctx context.Context, id int, departmentIDs []int) (*models.PayoutBatch, error)
+	GetExpenses(ctx context.Context, batchID int) ([]models.Expense, error)
+	MarkPaid(ctx context.Context, id, userID int, reference string) error
+	Delete(ctx context.Context, id int) error
+	GetBankAccounts(ctx context.Context, userIDs []int) (map[int]models.BankAccount, error)
+	SetBankAccount(ctx context.Context, req models.BankAccountRequest) error
+}
+
+type TagRepository interface {
+	Search(ctx context.Context, prefix string, limit int) ([]models.Tag, error)
+}
+
+type ExchangeRateRepository interface {
+	Upsert(ctx context.Context, rate *models.ExchangeRate) error
+	GetAll(ctx context.Context, currency string) ([]models.ExchangeRate, error)
+	FindEffective(ctx context.Context, base, quote string, date time.Time) (*models.ExchangeRate, error)
+	FindCrossBase(ctx context.Context, from, to string, date time.Time) (string, error)
+}
+
+type SettingsRepository interface {
+	Get(ctx context.Context, key string) (string, bool, error)
+	Set(ctx context.Context, key, value string) error
+}
+
+type BudgetEntryRepository interface {
+	AddAllocation(ctx context.Context, budgetID int, delta models.Money, entryType models.BudgetEntryType, reason string, userID int) (*models.BudgetEntry, error)
+	GetByBudgetID(ctx context.Context, budgetID int) ([]models.BudgetEntry, error)
+	Transfer(ctx context.Context, req models.BudgetTransferRequest, userID int) (*models.BudgetTransfer, error)
+}
+
+type NotificationRepository interface {
+	Create(ctx context.Context, n *models.Notification) error
+	GetByUser(ctx context.Context, userID int, unreadOnly bool, limit int) ([]models.Notification, error)
+	CountUnread(ctx context.Context, userID int) (int, error)
+	MarkRead(ctx context.Context, id, userID int) error
+	MarkAllRead(ctx context.Context, userID int) error
+}
+
+type StatementRepository interface {
+	Create(ctx context.Context, statement models.BankStatement, lines []models.ParsedStatementLine) (*models.BankStatement, []models.StatementLine, error)
+	GetAll(ctx context.Context) ([]models.BankStatement, error)
+	Delete(ctx context.Context, id int) error
+	GetLines(ctx context.Context, filter models.StatementLineFilter) ([]models.StatementLine, error)
+	GetLine(ctx context.Context, id int) (*models.StatementLine, error)
+	FindCandidates(ctx context.Context, line models.StatementLine, windowDays int) ([]models.MatchCandidate, error)
+	Match(ctx context.Context, lineID, expenseID, userID int, auto bool) error
+	SetIgnored(ctx context.Context, lineID, userID int) error
+	Reset(ctx context.Context, lineID int) error
+}
+
+type TaxRateRepository interface {
+	GetAll(ctx context.Context, activeOnly bool) ([]models.TaxRate, error)
+	GetByID(ctx context.Context, id int) (*models.TaxRate, error)
+	Create(ctx context.Context, req models.TaxRateRequest) (*models.TaxRate, error)
+	Update(ctx context.Context, id int, req models.TaxRateRequest) (*models.TaxRate, error)
+	GetVATSummary(ctx context.Context, start, end time.Time) ([]models.VATReportLine, error)
+	GetUntaxedTotal(ctx context.Context, start, end time.Time) (int, models.Money, error)
 }
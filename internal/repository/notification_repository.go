@@ -0,0 +1,74 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"expense-tracker/internal/models"
+)
+
+type sqlNotificationRepository struct {
+	db *sql.DB
+}
+
+func NewNotificationRepository(db *sql.DB) NotificationRepository {
+	return &sqlNotificationRepository{db: db}
+}
+
+func (r *sqlNotificationRepository) Create(ctx context.Context, n *models.Notification) error {
+	return insertNotification(ctx, r.db, n)
+}
+
+// insertNotification stores n, directly or as part of a transaction.
+func insertNotification(ctx context.Context, q interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}, n *models.Notification) error {
+	query := `INSERT INTO notifications (user_id, type, title, message, budget_id)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING id, created_at`
+	return q.QueryRowContext(ctx, query, n.UserID, n.Type, n.Title, n.Message, n.BudgetID).Scan(&n.ID, &n.CreatedAt)
+}
+
+func (r *sqlNotificationRepository) GetByUser(ctx context.Context, userID int, unreadOnly bool, limit int) ([]models.Notification, error) {
+	query := `SELECT id, user_id, type, title, message, budget_id, read_at, created_at
+	          FROM notifications
+	          WHERE user_id = $1 AND ($2 = FALSE OR read_at IS NULL)
+	          ORDER BY created_at DESC, id DESC
+	          LIMIT $3`
+
+	rows, err := r.db.QueryContext(ctx, query, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	notifications := []models.Notification{}
+	for rows.Next() {
+		var n models.Notification
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Type, &n.Title, &n.Message, &n.BudgetID, &n.ReadAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		notifications = append(notifications, n)
+	}
+	return notifications, rows.Err()
+}
+
+func (r *sqlNotificationRepository) CountUnread(ctx context.Context, userID int) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+func (r *sqlNotificationRepository) MarkRead(ctx context.Context, id, userID int) error {
+	res, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = COALESCE(read_at, CURRENT_TIMESTAMP) WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *sqlNotificationRepository) MarkAllRead(ctx context.Context, userID int) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notifications SET read_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND read_at IS NULL`, userID)
+	return err
+}
@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"expense-tracker/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type sqlPayoutRepository struct {
+	db *sql.DB
+}
+
+func NewPayoutRepository(db *sql.DB) PayoutRepository {
+	return &sqlPayoutRepository{db: db}
+}
+
+// reimbursableCondition selects approved personally paid expenses that are
+// not in a payout batch yet.
+const reimbursableCondition = `e.payment_method = 'personal' AND e.approval_status = 'approved' AND e.payout_batch_id IS NULL AND e.user_id IS NOT NULL`
+
+// scopeArg turns a department scope into a query argument; nil (no
+// restriction) becomes NULL so queries can test `$n::int[] IS NULL`.
+func scopeArg(departmentIDs []int) interface{} {
+	if departmentIDs == nil {
+		return nil
+	}
+	return pq.Array(departmentIDs)
+}
+
+func (r *sqlPayoutRepository) GetPending(ctx context.Context, departmentIDs []int) ([]models.PendingReimbursement, error) {
+	query := `SELECT e.user_id, u.username, COUNT(*), COALESCE(SUM(e.amount), 0), ba.user_id IS NOT NULL
+	          FROM expenses e
+	          JOIN users u ON u.id = e.user_id
+	          LEFT JOIN user_bank_accounts ba ON ba.user_id = e.user_id
+	          WHERE ` + reimbursableCondition + ` AND ($1::int[] IS NULL OR e.department_id = ANY($1))
+	          GROUP BY e.user_id, u.username, ba.user_id
+	          ORDER BY u.username ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, scopeArg(departmentIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pending := []models.PendingReimbursement{}
+	for rows.Next() {
+		var p models.PendingReimbursement
+		if err := rows.Scan(&p.UserID, &p.UserName, &p.ExpenseCount, &p.TotalAmount, &p.HasBankAccount); err != nil {
+			return nil, err
+		}
+		pending = append(pending, p)
+	}
+	return pending, rows.Err()
+}
+
+// CreateBatches groups the matching reimbursable expenses into one open batch
+// per user and returns the new batches.
+func (r *sqlPayoutRepository) CreateBatches(ctx context.Context, req models.PayoutBatchRequest, departmentIDs []int, currency string, createdBy int) ([]models.PayoutBatch, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	query := `SELECT e.id, e.user_id FROM expenses e
+	          WHERE ` + reimbursableCondition + ` AND ($1::int[] IS NULL OR e.department_id = ANY($1))`
+	args := []interface{}{scopeArg(departmentIDs)}
+	if len(req.UserIDs) > 0 {
+		args = append(args, pq.Array(req.UserIDs))
+		query += fmt.Sprintf(" AND e.user_id = ANY($%d)", len(args))
+	}
+	if req.EndDate != "" {
+		args = append(args, req.EndDate)
+		query += fmt.Sprintf(" AND e.expense_date <= $%d", len(args))
+	}
+	query += " ORDER BY e.user_id, e.expense_date, e.id FOR UPDATE"
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	var userIDs []int
+	byUser := map[int][]int{}
+	for rows.Next() {
+		var expenseID, userID int
+		if err := rows.Scan(&expenseID, &userID); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if _, ok := byUser[userID]; !ok {
+			userIDs = append(userIDs, userID)
+		}
+		byUser[userID] = append(byUser[userID], expenseID)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	var batchIDs []int
+	for _, userID := range userIDs {
+		var batchID int
+		err := tx.QueryRowContext(ctx, "INSERT INTO payout_batches (user_id, currency, created_by) VALUES ($1, $2, $3) RETURNING id",
+			userID, currency, createdBy).Scan(&batchID)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE expenses SET payout_batch_id = $1, updated_at = CURRENT_TIMESTAMP WHERE id = ANY($2)", batchID, pq.Array(byUser[userID])); err != nil {
+			return nil, err
+		}
+		query := `UPDATE payout_batches SET
+		              total_amount = (SELECT COALESCE(SUM(amount), 0) FROM expenses WHERE payout_batch_id = $1),
+		              expense_count = (SELECT COUNT(*) FROM expenses WHERE payout_batch_id = $1),
+		              payment_reference = 'PAYOUT-' || LPAD($1::text, 6, '0')
+		          WHERE id = $1`
+		if _, err := tx.ExecContext(ctx, query, batchID); err != nil {
+			return nil, err
+		}
+		batchIDs = append(batchIDs, batchID)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	if len(batchIDs) == 0 {
+		return []models.PayoutBatch{}, nil
+	}
+	return r.getBatches(ctx, "b.id = ANY($1)", pq.Array(batchIDs))
+}
+
+const payoutBatchQuery = `SELECT b.id, b.user_id, u.username, b.status, b.total_amount, b.expense_count, b.currency,
+	                 b.created_by, b.created_at, b.paid_by, b.paid_at, COALESCE(b.payment_reference, '')
+	          FROM payout_batches b
+	          JOIN users u ON u.id = b.user_id`
+
+// batchScope limits batches to those holding expenses of the given departments.
+const batchScope = `($%d::int[] IS NULL OR EXISTS (SELECT 1 FROM expenses e WHERE e.payout_batch_id = b.id AND e.department_id = ANY($%d)))`
+
+func (r *sqlPayoutRepository) getBatches(ctx context.Context, where string, args ...interface{}) ([]models.PayoutBatch, error) {
+	rows, err := r.db.QueryContext(ctx, payoutBatchQuery+" WHERE "+where+" ORDER BY b.created_at DESC, b.id DESC", args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := []models.PayoutBatch{}
+	for rows.Next() {
+		var b models.PayoutBatch
+		err := rows.Scan(&b.ID, &b.UserID, &b.UserName, &b.Status, &b.TotalAmount, &b.ExpenseCount, &b.Currency,
+			&b.CreatedBy, &b.CreatedAt, &b.PaidBy, &b.PaidAt, &b.PaymentReference)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, b)
+	}
+	return batches, rows.Err()
+}
+
+// GetAll returns batches in status (all when empty), newest first.
+func (r *sqlPayoutRepository) GetAll(ctx context.Context, status models.PayoutStatus, departmentIDs []int) ([]models.PayoutBatch, error) {
+	return r.getBatches(ctx, "($1 = '' OR b.status = $1) AND "+fmt.Sprintf(batchScope, 2, 2), string(status), scopeArg(departmentIDs))
+}
+
+// GetByIDs returns the listed batches that are visible in the department scope.
+func (r *sqlPayoutRepository) GetByIDs(ctx context.Context, ids []int, departmentIDs []int) ([]models.PayoutBatch, error) {
+	return r.getBatches(ctx, "b.id = ANY($1) AND "+fmt.Sprintf(batchScope, 2, 2), pq.Array(ids), scopeArg(departmentIDs))
+}
+
+func (r *sqlPayoutRepository) GetByID(ctx context.Context, id int, departmentIDs []int) (*models.PayoutBatch, error) {
+	batches, err := r.GetByIDs(ctx, []int{id}, departmentIDs)
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return nil, errors.New("payout batch not found")
+	}
+	return &batches[0], nil
+}
+
+func (r *sqlPayoutRepository) GetExpenses(ctx context.Context, batchID int) ([]models.Expense, error) {
+	query := `SELECT e.id, e.category_id, e.user_id, e.amount, e.expense_date, e.remarks, c.name, COALESCE(v.name, ''), e.payout_batch_id
+	          FROM expenses e
+	          JOIN categories c ON c.id = e.category_id
+	          LEFT JOIN vendors v ON v.id = e.vendor_id
+	          WHERE e.payout_batch_id = $1
+	          ORDER BY e.expense_date ASC, e.id ASC`
+	rows, err := r.db.QueryContext(ctx, query, batchID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	expenses := []models.Expense{}
+	for rows.Next() {
+		var e models.Expense
+		if err := rows.Scan(&e.ID, &e.CategoryID, &e.UserID, &e.Amount, &e.ExpenseDate, &e.Remarks, &e.CategoryName, &e.VendorName, &e.PayoutBatchID); err != nil {
+			return nil, err
+		}
+		expenses = append(expenses, e)
+	}
+	return expenses, rows.Err()
+}
+
+func (r *sqlPayoutRepository) MarkPaid(ctx context.Context, id, userID int, reference string) error {
+	query := `UPDATE payout_batches SET status = 'paid', paid_by = $1, paid_at = CURRENT_TIMESTAMP,
+	                 payment_reference = COALESCE(NULLIF($2, ''), payment_reference)
+	          WHERE id = $3 AND status = 'open'`
+	res, err := r.db.ExecContext(ctx, query, userID, reference, id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return errors.New("payout batch is already paid")
+	}
+	return nil
+}
+
+// Delete cancels an open batch; its expenses become pending reimbursement again.
+func (r *sqlPayoutRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM payout_batches WHERE id = $1 AND status = 'open'", id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return errors.New("payout batch is already paid")
+	}
+	return nil
+}
+
+func (r *sqlPayoutRepository) GetBankAccounts(ctx context.Context, userIDs []int) (map[int]models.BankAccount, error) {
+	query := `SELECT user_id, account_holder, iban, COALESCE(bic, ''), updated_at FROM user_bank_accounts WHERE user_id = ANY($1)`
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(userIDs))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	accounts := map[int]models.BankAccount{}
+	for rows.Next() {
+		var a models.BankAccount
+		if err := rows.Scan(&a.UserID, &a.AccountHolder, &a.IBAN, &a.BIC, &a.UpdatedAt); err != nil {
+			return nil, err
+		}
+		accounts[a.UserID] = a
+	}
+	return accounts, rows.Err()
+}
+
+func (r *sqlPayoutRepository) SetBankAccount(ctx context.Context, req models.BankAccountRequest) error {
+	query := `INSERT INTO user_bank_accounts (user_id, account_holder, iban, bic) VALUES ($1, $2, $3, NULLIF($4, ''))
+	          ON CONFLICT (user_id) DO UPDATE SET account_holder = EXCLUDED.account_holder, iban = EXCLUDED.iban,
+	                                              bic = EXCLUDED.bic, updated_at = CURRENT_TIMESTAMP`
+	if _, err := r.db.ExecContext(ctx, query, req.UserID, req.AccountHolder, req.IBAN, req.BIC); err != nil {
+		if strings.Contains(err.Error(), "user_bank_accounts_user_id_fkey") {
+			return errors.New("user not found")
+		}
+		return err
+	}
+	return nil
+}
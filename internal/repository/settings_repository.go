@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+)
+
+type sqlSettingsRepository struct {
+	db *sql.DB
+}
+
+func NewSettingsRepository(db *sql.DB) SettingsRepository {
+	return &sqlSettingsRepository{db: db}
+}
+
+func (r *sqlSettingsRepository) Get(ctx context.Context, key string) (string, bool, error) {
+	var value string
+	err := r.db.QueryRowContext(ctx, "SELECT value FROM app_settings WHERE key = $1", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+func (r *sqlSettingsRepository) Set(ctx context.Context, key, value string) error {
+	query := `INSERT INTO app_settings (key, value, updated_at) VALUES ($1, $2, CURRENT_TIMESTAMP)
+	          ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP`
+	_, err := r.db.ExecContext(ctx, query, key, value)
+	return err
+}
@@ -0,0 +1,265 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+
+	"expense-tracker/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type sqlStatementRepository struct {
+	db *sql.DB
+}
+
+func NewStatementRepository(db *sql.DB) StatementRepository {
+	return &sqlStatementRepository{db: db}
+}
+
+// Create stores the statement and its lines, skipping lines whose fingerprint
+// was imported before, and returns the lines that were new. Nothing is stored
+// (and the statement is nil) when every line was imported before.
+func (r *sqlStatementRepository) Create(ctx context.Context, statement models.BankStatement, lines []models.ParsedStatementLine) (*models.BankStatement, []models.StatementLine, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer tx.Rollback()
+
+	err = tx.QueryRowContext(ctx, `INSERT INTO bank_statements (filename, format, account_reference, imported_by)
+	          VALUES ($1, $2, $3, $4) RETURNING id, imported_at`,
+		statement.Filename, statement.Format, statement.AccountReference, statement.ImportedBy).Scan(&statement.ID, &statement.ImportedAt)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	query := `INSERT INTO bank_statement_lines (statement_id, booking_date, amount, currency, counterparty, description, reference, fingerprint)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	          ON CONFLICT (fingerprint) DO NOTHING
+	          RETURNING id`
+	inserted := []models.StatementLine{}
+	for _, l := range lines {
+		line := models.StatementLine{
+			StatementID:  statement.ID,
+			BookingDate:  l.BookingDate,
+			Amount:       l.Amount,
+			Currency:     l.Currency,
+			Counterparty: l.Counterparty,
+			Description:  l.Description,
+			Reference:    l.Reference,
+			Status:       models.LineUnmatched,
+		}
+		err := tx.QueryRowContext(ctx, query, statement.ID, l.BookingDate, l.Amount, l.Currency, l.Counterparty, l.Description, l.Reference, l.Fingerprint).Scan(&line.ID)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		inserted = append(inserted, line)
+	}
+	if len(inserted) == 0 {
+		return nil, inserted, nil
+	}
+	statement.LineCount = len(inserted)
+
+	if err := tx.Commit(); err != nil {
+		return nil, nil, err
+	}
+	return &statement, inserted, nil
+}
+
+func (r *sqlStatementRepository) GetAll(ctx context.Context) ([]models.BankStatement, error) {
+	query := `SELECT s.id, s.filename, s.format, s.account_reference, s.imported_by, s.imported_at,
+	                 COUNT(l.id), COUNT(l.expense_id), COUNT(*) FILTER (WHERE l.expense_id IS NULL AND l.ignored)
+	          FROM bank_statements s
+	          LEFT JOIN bank_statement_lines l ON l.statement_id = s.id
+	          GROUP BY s.id
+	          ORDER BY s.imported_at DESC, s.id DESC`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	statements := []models.BankStatement{}
+	for rows.Next() {
+		var s models.BankStatement
+		if err := rows.Scan(&s.ID, &s.Filename, &s.Format, &s.AccountReference, &s.ImportedBy, &s.ImportedAt,
+			&s.LineCount, &s.MatchedCount, &s.IgnoredCount); err != nil {
+			return nil, err
+		}
+		statements = append(statements, s)
+	}
+	return statements, rows.Err()
+}
+
+// Delete removes a statement with its lines; matched expenses become
+// unreconciled again.
+func (r *sqlStatementRepository) Delete(ctx context.Context, id int) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM bank_statements WHERE id = $1", id)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return errors.New("bank statement not found")
+	}
+	return nil
+}
+
+const statementLineQuery = `SELECT l.id, l.statement_id, l.booking_date, l.amount, l.currency, l.counterparty, l.description, l.reference,
+	                 CASE WHEN l.expense_id IS NOT NULL THEN 'matched' WHEN l.ignored THEN 'ignored' ELSE 'unmatched' END,
+	                 l.expense_id, l.auto_matched, l.matched_by, l.matched_at
+	          FROM bank_statement_lines l`
+
+func scanStatementLine(scanner interface{ Scan(...interface{}) error }) (models.StatementLine, error) {
+	var l models.StatementLine
+	err := scanner.Scan(&l.ID, &l.StatementID, &l.BookingDate, &l.Amount, &l.Currency, &l.Counterparty, &l.Description, &l.Reference,
+		&l.Status, &l.ExpenseID, &l.AutoMatched, &l.MatchedBy, &l.MatchedAt)
+	return l, err
+}
+
+func (r *sqlStatementRepository) GetLines(ctx context.Context, filter models.StatementLineFilter) ([]models.StatementLine, error) {
+	var conditions []string
+	var args []interface{}
+	if filter.StatementID > 0 {
+		args = append(args, filter.StatementID)
+		conditions = append(conditions, fmt.Sprintf("l.statement_id = $%d", len(args)))
+	}
+	switch filter.Status {
+	case models.LineMatched:
+		conditions = append(conditions, "l.expense_id IS NOT NULL")
+	case models.LineIgnored:
+		conditions = append(conditions, "l.expense_id IS NULL AND l.ignored")
+	case models.LineUnmatched:
+		conditions = append(conditions, "l.expense_id IS NULL AND NOT l.ignored")
+	}
+
+	query := statementLineQuery
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY l.booking_date DESC, l.id ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []models.StatementLine{}
+	for rows.Next() {
+		l, err := scanStatementLine(rows)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+func (r *sqlStatementRepository) GetLine(ctx context.Context, id int) (*models.StatementLine, error) {
+	l, err := scanStatementLine(r.db.QueryRowContext(ctx, statementLineQuery+" WHERE l.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("statement line not found")
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &l, nil
+}
+
+// FindCandidates returns unreconciled, non-rejected expenses entered in the
+// line's currency for the same amount, dated within windowDays of the line.
+func (r *sqlStatementRepository) FindCandidates(ctx context.Context, line models.StatementLine, windowDays int) ([]models.MatchCandidate, error) {
+	query := `SELECT e.id, e.expense_date, COALESCE(e.original_amount, e.amount), e.currency, c.name, COALESCE(u.username, 'System'),
+	                 COALESCE(v.name, ''), COALESCE(e.remarks, ''),
+	                 CASE WHEN v.id IS NULL THEN ARRAY[]::text[]
+	                      ELSE ARRAY(SELECT v.normalized_name UNION SELECT a.alias FROM vendor_aliases a WHERE a.vendor_id = v.id) END
+	          FROM expenses e
+	          JOIN categories c ON c.id = e.category_id
+	          LEFT JOIN users u ON u.id = e.user_id
+	          LEFT JOIN vendors v ON v.id = e.vendor_id
+	          WHERE e.currency = $1 AND COALESCE(e.original_amount, e.amount) = $2
+	            AND e.expense_date BETWEEN $3::date - $4::int AND $3::date + $4::int
+	            AND e.approval_status <> 'rejected'
+	            AND e.id NOT IN (` + reconciledExpenses + `)
+	          ORDER BY e.expense_date ASC, e.id ASC`
+	rows, err := r.db.QueryContext(ctx, query, line.Currency, line.Amount, line.BookingDate, windowDays)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	candidates := []models.MatchCandidate{}
+	for rows.Next() {
+		var c models.MatchCandidate
+		if err := rows.Scan(&c.ExpenseID, &c.ExpenseDate, &c.Amount, &c.Currency, &c.CategoryName, &c.UserName,
+			&c.VendorName, &c.Remarks, pq.Array(&c.VendorNames)); err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates, rows.Err()
+}
+
+// Match links an unmatched line to an expense.
+func (r *sqlStatementRepository) Match(ctx context.Context, lineID, expenseID, userID int, auto bool) error {
+	query := `UPDATE bank_statement_lines
+	          SET expense_id = $1, auto_matched = $2, ignored = FALSE, matched_by = $3, matched_at = CURRENT_TIMESTAMP
+	          WHERE id = $4 AND expense_id IS NULL`
+	res, err := r.db.ExecContext(ctx, query, expenseID, auto, userID, lineID)
+	if err != nil {
+		switch {
+		case strings.Contains(err.Error(), "idx_bank_statement_lines_expense_id"):
+			return ErrExpenseReconciled
+		case strings.Contains(err.Error(), "bank_statement_lines_expense_id_fkey"):
+			return errors.New("expense not found")
+		}
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		if _, err := r.GetLine(ctx, lineID); err != nil {
+			return err
+		}
+		return errors.New("statement line is already matched")
+	}
+	return nil
+}
+
+// SetIgnored marks an unmatched line as not needing an expense, such as a
+// bank fee or a transfer between own accounts.
+func (r *sqlStatementRepository) SetIgnored(ctx context.Context, lineID, userID int) error {
+	query := `UPDATE bank_statement_lines SET ignored = TRUE, matched_by = $1, matched_at = CURRENT_TIMESTAMP
+	          WHERE id = $2 AND expense_id IS NULL`
+	res, err := r.db.ExecContext(ctx, query, userID, lineID)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		if _, err := r.GetLine(ctx, lineID); err != nil {
+			return err
+		}
+		return errors.New("statement line is already matched")
+	}
+	return nil
+}
+
+// Reset returns a matched or ignored line to the review queue.
+func (r *sqlStatementRepository) Reset(ctx context.Context, lineID int) error {
+	query := `UPDATE bank_statement_lines
+	          SET expense_id = NULL, auto_matched = FALSE, ignored = FALSE, matched_by = NULL, matched_at = NULL
+	          WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, query, lineID)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return errors.New("statement line not found")
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"expense-tracker/internal/models"
+)
+
+type sqlTagRepository struct {
+	db *sql.DB
+}
+
+func NewTagRepository(db *sql.DB) TagRepository {
+	return &sqlTagRepository{db: db}
+}
+
+// Search returns tags starting with prefix, most used first.
+func (r *sqlTagRepository) Search(ctx context.Context, prefix string, limit int) ([]models.Tag, error) {
+	query := `SELECT t.id, t.name, COUNT(et.expense_id) AS usage
+	          FROM tags t
+	          LEFT JOIN expense_tags et ON et.tag_id = t.id
+	          WHERE t.name LIKE $1
+	          GROUP BY t.id, t.name
+	          ORDER BY usage DESC, t.name ASC
+	          LIMIT $2`
+
+	rows, err := r.db.QueryContext(ctx, query, escapeLike(prefix)+"%", limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tags := []models.Tag{}
+	for rows.Next() {
+		var t models.Tag
+		if err := rows.Scan(&t.ID, &t.Name, &t.UsageCount); err != nil {
+			return nil, err
+		}
+		tags = append(tags, t)
+	}
+	return tags, rows.Err()
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"expense-tracker/internal/models"
+)
+
+type sqlTaxRateRepository struct {
+	db *sql.DB
+}
+
+func NewTaxRateRepository(db *sql.DB) TaxRateRepository {
+	return &sqlTaxRateRepository{db: db}
+}
+
+const taxRateColumns = `id, name, rate, recoverable, is_active, created_at, updated_at`
+
+func scanTaxRate(row interface{ Scan(...interface{}) error }) (*models.TaxRate, error) {
+	var t models.TaxRate
+	if err := row.Scan(&t.ID, &t.Name, &t.Rate, &t.Recoverable, &t.IsActive, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+func (r *sqlTaxRateRepository) GetAll(ctx context.Context, activeOnly bool) ([]models.TaxRate, error) {
+	query := "SELECT " + taxRateColumns + " FROM tax_rates"
+	if activeOnly {
+		query += " WHERE is_active = true"
+	}
+	query += " ORDER BY rate DESC, name ASC"
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rates := []models.TaxRate{}
+	for rows.Next() {
+		t, err := scanTaxRate(rows)
+		if err != nil {
+			return nil, err
+		}
+		rates = append(rates, *t)
+	}
+	return rates, rows.Err()
+}
+
+func (r *sqlTaxRateRepository) GetByID(ctx context.Context, id int) (*models.TaxRate, error) {
+	t, err := scanTaxRate(r.db.QueryRowContext(ctx, "SELECT "+taxRateColumns+" FROM tax_rates WHERE id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("tax rate not found")
+	}
+	return t, err
+}
+
+func (r *sqlTaxRateRepository) Create(ctx context.Context, req models.TaxRateRequest) (*models.TaxRate, error) {
+	recoverable := req.Recoverable == nil || *req.Recoverable
+	isActive := req.IsActive == nil || *req.IsActive
+	query := `INSERT INTO tax_rates (name, rate, recoverable, is_active) VALUES ($1, $2, $3, $4) RETURNING ` + taxRateColumns
+	t, err := scanTaxRate(r.db.QueryRowContext(ctx, query, req.Name, req.Rate, recoverable, isActive))
+	if err != nil {
+		return nil, taxRateWriteError(err)
+	}
+	return t, nil
+}
+
+// Update changes a rate for future expenses; expenses already entered keep
+// the rate they were split with.
+func (r *sqlTaxRateRepository) Update(ctx context.Context, id int, req models.TaxRateRequest) (*models.TaxRate, error) {
+	recoverable := req.Recoverable == nil || *req.Recoverable
+	isActive := req.IsActive == nil || *req.IsActive
+	query := `UPDATE tax_rates SET name = $1, rate = $2, recoverable = $3, is_active = $4, updated_at = CURRENT_TIMESTAMP
+	          WHERE id = $5 RETURNING ` + taxRateColumns
+	t, err := scanTaxRate(r.db.QueryRowContext(ctx, query, req.Name, req.Rate, recoverable, isActive, id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("tax rate not found")
+	} else if err != nil {
+		return nil, taxRateWriteError(err)
+	}
+	return t, nil
+}
+
+func taxRateWriteError(err error) error {
+	if strings.Contains(err.Error(), "tax_rates_name_key") {
+		return errors.New("tax rate with this name already exists")
+	}
+	return err
+}
+
+// GetVATSummary sums approved taxed expenses dated between start and end by
+// rate (as applied on the expense) and category.
+func (r *sqlTaxRateRepository) GetVATSummary(ctx context.Context, start, end time.Time) ([]models.VATReportLine, error) {
+	query := `SELECT e.tax_rate_id, t.name, e.tax_rate, e.category_id, c.name, COUNT(*),
+	                 COALESCE(SUM(e.net_amount), 0), COALESCE(SUM(e.tax_amount), 0), COALESCE(SUM(e.amount), 0),
+	                 COALESCE(SUM(e.tax_amount) FILTER (WHERE e.tax_recoverable), 0)
+	          FROM expenses e
+	          JOIN tax_rates t ON t.id = e.tax_rate_id
+	          JOIN categories c ON c.id = e.category_id
+	          WHERE e.approval_status = 'approved' AND e.expense_date BETWEEN $1 AND $2
+	          GROUP BY e.tax_rate_id, t.name, e.tax_rate, e.category_id, c.name
+	          ORDER BY e.tax_rate DESC, t.name ASC, c.name ASC`
+	rows, err := r.db.QueryContext(ctx, query, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	lines := []models.VATReportLine{}
+	for rows.Next() {
+		var l models.VATReportLine
+		if err := rows.Scan(&l.TaxRateID, &l.TaxRateName, &l.Rate, &l.CategoryID, &l.CategoryName, &l.ExpenseCount,
+			&l.NetAmount, &l.TaxAmount, &l.GrossAmount, &l.RecoverableTax); err != nil {
+			return nil, err
+		}
+		lines = append(lines, l)
+	}
+	return lines, rows.Err()
+}
+
+// GetUntaxedTotal counts and sums approved expenses in the period that have no
+// tax rate.
+func (r *sqlTaxRateRepository) GetUntaxedTotal(ctx context.Context, start, end time.Time) (int, models.Money, error) {
+	var count int
+	var total models.Money
+	query := `SELECT COUNT(*), COALESCE(SUM(amount), 0) FROM expenses
+	          WHERE tax_rate_id IS NULL AND approval_status = 'approved' AND expense_date BETWEEN $1 AND $2`
+	err := r.db.QueryRowContext(ctx, query, start, end).Scan(&count, &total)
+	return count, total, err
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"expense-tracker/internal/models"
@@ -15,11 +16,11 @@ func NewUserRepository(db *sql.DB) UserRepository {
 	return &sqlUserRepository{db: db}
 }
 
-func (r *sqlUserRepository) Create(user *models.User) error {
+func (r *sqlUserRepository) Create(ctx context.Context, user *models.User) error {
 	query := `INSERT INTO users (username, user_display_id, email, password_hash, role, is_active, password_set_token, password_set_token_expiry) 
 	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id, created_at, updated_at`
 
-	return r.db.QueryRow(
+	return r.db.QueryRowContext(ctx,
 		query,
 		user.Username,
 		user.UserDisplayID,
@@ -32,12 +33,12 @@ func (r *sqlUserRepository) Create(user *models.User) error {
 	).Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 }
 
-func (r *sqlUserRepository) GetByID(id int) (*models.User, error) {
+func (r *sqlUserRepository) GetByID(ctx context.Context, id int) (*models.User, error) {
 	var user models.User
 	query := `SELECT id, username, user_display_id, email, password_hash, role, is_active, password_set_token, password_set_token_expiry, created_at, updated_at 
 	          FROM users WHERE id = $1`
 
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&user.ID, &user.Username, &user.UserDisplayID, &user.Email, &user.PasswordHash,
 		&user.Role, &user.IsActive, &user.PasswordSetToken, &user.PasswordSetTokenExpiry,
 		&user.CreatedAt, &user.UpdatedAt,
@@ -49,12 +50,12 @@ func (r *sqlUserRepository) GetByID(id int) (*models.User, error) {
 	return &user, err
 }
 
-func (r *sqlUserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *sqlUserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
 	var user models.User
 	query := `SELECT id, username, user_display_id, email, password_hash, role, is_active, password_set_token, password_set_token_expiry, created_at, updated_at 
 	          FROM users WHERE email = $1`
 
-	err := r.db.QueryRow(query, email).Scan(
+	err := r.db.QueryRowContext(ctx, query, email).Scan(
 		&user.ID, &user.Username, &user.UserDisplayID, &user.Email, &user.PasswordHash,
 		&user.Role, &user.IsActive, &user.PasswordSetToken, &user.PasswordSetTokenExpiry,
 		&user.CreatedAt, &user.UpdatedAt,
@@ -66,12 +67,12 @@ func (r *sqlUserRepository) GetByEmail(email string) (*models.User, error) {
 	return &user, err
 }
 
-func (r *sqlUserRepository) GetByDisplayID(displayID string) (*models.User, error) {
+func (r *sqlUserRepository) GetByDisplayID(ctx context.Context, displayID string) (*models.User, error) {
 	var user models.User
 	query := `SELECT id, username, user_display_id, email, password_hash, role, is_active, password_set_token, password_set_token_expiry, created_at, updated_at 
 	          FROM users WHERE user_display_id = $1`
 
-	err := r.db.QueryRow(query, displayID).Scan(
+	err := r.db.QueryRowContext(ctx, query, displayID).Scan(
 		&user.ID, &user.Username, &user.UserDisplayID, &user.Email, &user.PasswordHash,
 		&user.Role, &user.IsActive, &user.PasswordSetToken, &user.PasswordSetTokenExpiry,
 		&user.CreatedAt, &user.UpdatedAt,
@@ -83,30 +84,30 @@ func (r *sqlUserRepository) GetByDisplayID(displayID string) (*models.User, erro
 	return &user, err
 }
 
-func (r *sqlUserRepository) UpdatePassword(id int, passwordHash string) error {
+func (r *sqlUserRepository) UpdatePassword(ctx context.Context, id int, passwordHash string) error {
 	query := `UPDATE users SET password_hash = $1, is_active = TRUE, password_set_token = NULL, password_set_token_expiry = NULL, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
-	_, err := r.db.Exec(query, passwordHash, id)
+	_, err := r.db.ExecContext(ctx, query, passwordHash, id)
 	return err
 }
 
-func (r *sqlUserRepository) UpdateRole(id int, role models.UserRole) error {
+func (r *sqlUserRepository) UpdateRole(ctx context.Context, id int, role models.UserRole) error {
 	query := `UPDATE users SET role = $1, updated_at = CURRENT_TIMESTAMP WHERE id = $2`
-	_, err := r.db.Exec(query, role, id)
+	_, err := r.db.ExecContext(ctx, query, role, id)
 	return err
 }
 
-func (r *sqlUserRepository) SetPasswordToken(email, token string, expiry time.Time) error {
+func (r *sqlUserRepository) SetPasswordToken(ctx context.Context, email, token string, expiry time.Time) error {
 	query := `UPDATE users SET password_set_token = $1, password_set_token_expiry = $2, updated_at = CURRENT_TIMESTAMP WHERE email = $3`
-	_, err := r.db.Exec(query, token, expiry, email)
+	_, err := r.db.ExecContext(ctx, query, token, expiry, email)
 	return err
 }
 
-func (r *sqlUserRepository) GetByToken(token string) (*models.User, error) {
+func (r *sqlUserRepository) GetByToken(ctx context.Context, token string) (*models.User, error) {
 	var user models.User
 	query := `SELECT id, username, user_display_id, email, password_hash, role, is_active, password_set_token, password_set_token_expiry, created_at, updated_at 
 	          FROM users WHERE password_set_token = $1 AND password_set_token_expiry > CURRENT_TIMESTAMP`
 
-	err := r.db.QueryRow(query, token).Scan(
+	err := r.db.QueryRowContext(ctx, query, token).Scan(
 		&user.ID, &user.Username, &user.UserDisplayID, &user.Email, &user.PasswordHash,
 		&user.Role, &user.IsActive, &user.PasswordSetToken, &user.PasswordSetTokenExpiry,
 		&user.CreatedAt, &user.UpdatedAt,
@@ -118,9 +119,9 @@ func (r *sqlUserRepository) GetByToken(token string) (*models.User, error) {
 	return &user, err
 }
 
-func (r *sqlUserRepository) GetAll() ([]models.User, error) {
+func (r *sqlUserRepository) GetAll(ctx context.Context) ([]models.User, error) {
 	query := `SELECT id, username, user_display_id, email, role, is_active, created_at, updated_at FROM users ORDER BY created_at DESC`
-	rows, err := r.db.Query(query)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,209 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"expense-tracker/internal/models"
+
+	"github.com/lib/pq"
+)
+
+type sqlVendorRepository struct {
+	db *sql.DB
+}
+
+func NewVendorRepository(db *sql.DB) VendorRepository {
+	return &sqlVendorRepository{db: db}
+}
+
+const vendorQuery = `SELECT v.id, v.name,
+	                 ARRAY(SELECT a.alias FROM vendor_aliases a WHERE a.vendor_id = v.id ORDER BY a.alias),
+	                 (SELECT COUNT(*) FROM expenses e WHERE e.vendor_id = v.id), v.created_at, v.updated_at
+	          FROM vendors v`
+
+func scanVendor(row interface{ Scan(...interface{}) error }) (*models.Vendor, error) {
+	var v models.Vendor
+	if err := row.Scan(&v.ID, &v.Name, pq.Array(&v.Aliases), &v.ExpenseCount, &v.CreatedAt, &v.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if v.Aliases == nil {
+		v.Aliases = []string{}
+	}
+	return &v, nil
+}
+
+// GetAll returns vendors whose normalized name or an alias contains search
+// (all when empty), most used first. search must already be normalized.
+func (r *sqlVendorRepository) GetAll(ctx context.Context, search string) ([]models.Vendor, error) {
+	query := vendorQuery
+	var args []interface{}
+	if search != "" {
+		query += ` WHERE v.normalized_name LIKE $1
+		           OR EXISTS (SELECT 1 FROM vendor_aliases a WHERE a.vendor_id = v.id AND a.alias LIKE $1)`
+		args = append(args, "%"+escapeLike(search)+"%")
+	}
+	query += " ORDER BY 4 DESC, v.name ASC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	vendors := []models.Vendor{}
+	for rows.Next() {
+		v, err := scanVendor(rows)
+		if err != nil {
+			return nil, err
+		}
+		vendors = append(vendors, *v)
+	}
+	return vendors, rows.Err()
+}
+
+func (r *sqlVendorRepository) GetByID(ctx context.Context, id int) (*models.Vendor, error) {
+	v, err := scanVendor(r.db.QueryRowContext(ctx, vendorQuery+" WHERE v.id = $1", id))
+	if err == sql.ErrNoRows {
+		return nil, errors.New("vendor not found")
+	}
+	return v, err
+}
+
+// FindByNormalizedName matches a normalized name against vendor names and
+// aliases. It returns nil when nothing matches.
+func (r *sqlVendorRepository) FindByNormalizedName(ctx context.Context, normalized string) (*models.Vendor, error) {
+	query := vendorQuery + ` WHERE v.normalized_name = $1
+	          OR v.id = (SELECT vendor_id FROM vendor_aliases WHERE alias = $1)`
+	v, err := scanVendor(r.db.QueryRowContext(ctx, query, normalized))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+func (r *sqlVendorRepository) Create(ctx context.Context, name, normalized string) (*models.Vendor, error) {
+	var id int
+	err := r.db.QueryRowContext(ctx, "INSERT INTO vendors (name, normalized_name) VALUES ($1, $2) RETURNING id", name, normalized).Scan(&id)
+	if err != nil {
+		return nil, vendorWriteError(err)
+	}
+	return r.GetByID(ctx, id)
+}
+
+func (r *sqlVendorRepository) Update(ctx context.Context, id int, name, normalized string) (*models.Vendor, error) {
+	res, err := r.db.ExecContext(ctx, "UPDATE vendors SET name = $1, normalized_name = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $3", name, normalized, id)
+	if err != nil {
+		return nil, vendorWriteError(err)
+	}
+	if rowsAffected(res) == 0 {
+		return nil, errors.New("vendor not found")
+	}
+	return r.GetByID(ctx, id)
+}
+
+func vendorWriteError(err error) error {
+	if strings.Contains(err.Error(), "vendors_normalized_name_key") {
+		return ErrVendorExists
+	}
+	if strings.Contains(err.Error(), "vendor_aliases_alias_key") {
+		return errors.New("vendor alias already exists")
+	}
+	if strings.Contains(err.Error(), "vendor_aliases_vendor_id_fkey") {
+		return errors.New("vendor not found")
+	}
+	return err
+}
+
+// AddAlias records another normalized spelling for the vendor. An alias may
+// not shadow another vendor's name.
+func (r *sqlVendorRepository) AddAlias(ctx context.Context, vendorID int, alias string) error {
+	var taken bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM vendors WHERE normalized_name = $1)", alias).Scan(&taken)
+	if err != nil {
+		return err
+	}
+	if taken {
+		return ErrVendorExists
+	}
+	if _, err := r.db.ExecContext(ctx, "INSERT INTO vendor_aliases (vendor_id, alias) VALUES ($1, $2)", vendorID, alias); err != nil {
+		return vendorWriteError(err)
+	}
+	return nil
+}
+
+func (r *sqlVendorRepository) RemoveAlias(ctx context.Context, vendorID int, alias string) error {
+	res, err := r.db.ExecContext(ctx, "DELETE FROM vendor_aliases WHERE vendor_id = $1 AND alias = $2", vendorID, alias)
+	if err != nil {
+		return err
+	}
+	if rowsAffected(res) == 0 {
+		return errors.New("vendor alias not found")
+	}
+	return nil
+}
+
+// Merge moves the source vendor's expenses and aliases to the target, keeps
+// the source name as an alias so future entries resolve to the target, and
+// deletes the source.
+func (r *sqlVendorRepository) Merge(ctx context.Context, sourceID, targetID int) (*models.VendorMerge, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, "SELECT id, name, normalized_name FROM vendors WHERE id IN ($1, $2) ORDER BY id FOR UPDATE", sourceID, targetID)
+	if err != nil {
+		return nil, err
+	}
+	names := map[int][2]string{}
+	for rows.Next() {
+		var id int
+		var name, normalized string
+		if err := rows.Scan(&id, &name, &normalized); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		names[id] = [2]string{name, normalized}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	source, ok := names[sourceID]
+	if !ok {
+		return nil, errors.New("vendor not found")
+	}
+	if _, ok := names[targetID]; !ok {
+		return nil, errors.New("target vendor not found")
+	}
+
+	merge := models.VendorMerge{SourceVendorID: sourceID, SourceName: source[0], TargetVendorID: targetID}
+
+	res, err := tx.ExecContext(ctx, "UPDATE expenses SET vendor_id = $1, updated_at = CURRENT_TIMESTAMP WHERE vendor_id = $2", targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	merge.ExpensesMoved = rowsAffected(res)
+
+	res, err = tx.ExecContext(ctx, "UPDATE vendor_aliases SET vendor_id = $1 WHERE vendor_id = $2", targetID, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	merge.AliasesMoved = rowsAffected(res)
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM vendors WHERE id = $1", sourceID); err != nil {
+		return nil, err
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO vendor_aliases (vendor_id, alias) VALUES ($1, $2) ON CONFLICT (alias) DO NOTHING", targetID, source[1]); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return &merge, nil
+}
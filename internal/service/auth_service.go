@@ -1,25 +1,31 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"expense-tracker/internal/models"
 	"expense-tracker/internal/repository"
 
 	"strings"
+	"sync"
 
 	"golang.org/x/crypto/bcrypt"
 )
 
 type AuthService interface {
-	Login(email, password string) (*models.User, string, error)
-	SetPassword(token, password string) error
-	ValidateToken(token string) (*models.User, error)
+	Login(ctx context.Context, email, password string) (*models.User, string, error)
+	SetPassword(ctx context.Context, token, password string) error
+	ValidateToken(ctx context.Context, token string) (*models.User, error)
 	Logout(sessionToken string) error
 	IsAuthenticated(sessionToken string) (*models.User, bool)
+	ActiveSessions() int
 }
 
 type authService struct {
-	userRepo     repository.UserRepository
+	userRepo repository.UserRepository
+
+	// mu guards sessionStore, which concurrent requests read and write.
+	mu           sync.RWMutex
 	sessionStore map[string]*models.User
 }
 
@@ -30,9 +36,9 @@ func NewAuthService(userRepo repository.UserRepository) AuthService {
 	}
 }
 
-func (s *authService) Login(email, password string) (*models.User, string, error) {
+func (s *authService) Login(ctx context.Context, email, password string) (*models.User, string, error) {
 	email = strings.ToLower(email)
-	user, err := s.userRepo.GetByEmail(email)
+	user, err := s.userRepo.GetByEmail(ctx, email)
 	if err != nil {
 		return nil, "", errors.New("invalid email or password")
 	}
@@ -47,13 +53,15 @@ func (s *authService) Login(email, password string) (*models.User, string, error
 	}
 
 	sessionToken, _ := generateRandomToken(32)
+	s.mu.Lock()
 	s.sessionStore[sessionToken] = user
+	s.mu.Unlock()
 
 	return user, sessionToken, nil
 }
 
-func (s *authService) SetPassword(token, password string) error {
-	user, err := s.userRepo.GetByToken(token)
+func (s *authService) SetPassword(ctx context.Context, token, password string) error {
+	user, err := s.userRepo.GetByToken(ctx, token)
 	if err != nil {
 		return err
 	}
@@ -63,19 +71,29 @@ func (s *authService) SetPassword(token, password string) error {
 		return err
 	}
 
-	return s.userRepo.UpdatePassword(user.ID, string(hashedPassword))
+	return s.userRepo.UpdatePassword(ctx, user.ID, string(hashedPassword))
 }
 
-func (s *authService) ValidateToken(token string) (*models.User, error) {
-	return s.userRepo.GetByToken(token)
+func (s *authService) ValidateToken(ctx context.Context, token string) (*models.User, error) {
+	return s.userRepo.GetByToken(ctx, token)
 }
 
 func (s *authService) Logout(sessionToken string) error {
+	s.mu.Lock()
 	delete(s.sessionStore, sessionToken)
+	s.mu.Unlock()
 	return nil
 }
 
 func (s *authService) IsAuthenticated(sessionToken string) (*models.User, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 	user, ok := s.sessionStore[sessionToken]
 	return user, ok
 }
+
+func (s *authService) ActiveSessions() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.sessionStore)
+}
@@ -1,53 +1,115 @@
 package service
 
 import (
+	"context"
 	"database/sql"
 	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
 
+	"expense-tracker/internal/logging"
 	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
 )
 
 type BudgetRepository interface {
-	GetAll(year int) ([]models.Budget, error)
-	CreateOrUpdate(categoryID int, amount float64, year int) (*models.Budget, error)
-	GetDashboardSummary(year int) (*models.BudgetDashboardSummary, error)
-	GetByCategory(categoryID, year int) (*models.Budget, error)
-	GetMonitoringData(year int) ([]models.BudgetMonitoringItem, error)
-	ToggleLock(budgetID int, isLocked bool) error
-	IsLocked(categoryID, year int) (bool, error)
+	GetAll(ctx context.Context, year int) ([]models.Budget, error)
+	CreateOrUpdate(ctx context.Context, req models.BudgetRequest) (*models.Budget, error)
+	CreateOrUpdateMany(ctx context.Context, reqs []models.BudgetRequest) ([]models.Budget, error)
+	GetDashboardSummary(ctx context.Context, year int) (*models.BudgetDashboardSummary, error)
+	SetSavingsTarget(ctx context.Context, year int, amount models.Money) error
+	GetByID(ctx context.Context, id int) (*models.Budget, error)
+	GetByCategory(ctx context.Context, categoryID, year int) (*models.Budget, error)
+	GetByCategoryPath(ctx context.Context, categoryID, year int) ([]models.Budget, error)
+	GetMonitoringData(ctx context.Context, year int) ([]models.BudgetMonitoringItem, error)
+	ToggleLock(ctx context.Context, budgetID int, isLocked bool, userID int) error
+	SetAutoLock(ctx context.Context, budgetID int, isLocked bool, lockedAt time.Time, reason string) error
+	UpdatePolicy(ctx context.Context, budgetID int, policy models.BudgetPolicy) error
+	SetOverride(ctx context.Context, budgetID int, until *time.Time, reason string, userID int) error
+	GetLockEvents(ctx context.Context, budgetID int) ([]models.BudgetLockEvent, error)
+	UpdateAlertThresholds(ctx context.Context, budgetID int, thresholds []float64) error
+	RecordAlert(ctx context.Context, budgetID int, threshold float64, periodStart time.Time, percent float64, notifications []models.Notification) (bool, error)
+	IsLocked(ctx context.Context, categoryID int, date time.Time) (bool, error)
 }
 
 type ExpenseRepository interface {
-	GetYearlyTotal(categoryID, year int) (float64, error)
+	GetYearlyTotal(ctx context.Context, categoryID, year int) (models.Money, error)
+	GetTotalBetween(ctx context.Context, categoryID int, start, end time.Time) (models.Money, error)
+	GetMonthlyTotals(ctx context.Context, year int) (map[int][]models.Money, error)
+}
+
+type BudgetEntryRepository interface {
+	AddAllocation(ctx context.Context, budgetID int, delta models.Money, entryType models.BudgetEntryType, reason string, userID int) (*models.BudgetEntry, error)
+	GetByBudgetID(ctx context.Context, budgetID int) ([]models.BudgetEntry, error)
+	Transfer(ctx context.Context, req models.BudgetTransferRequest, userID int) (*models.BudgetTransfer, error)
+}
+
+// BudgetNotifier names who receives budget alerts and emails them; the
+// in-app notifications are stored together with the alert.
+type BudgetNotifier interface {
+	Managers(ctx context.Context) ([]models.User, error)
+	Email(ctx context.Context, users []models.User, n models.Notification)
+}
+
+// LockCounter counts circuit breaker locks for the metrics endpoint.
+type LockCounter interface {
+	IncBudgetLocks()
 }
 
 type BudgetService struct {
 	repo        BudgetRepository
 	expenseRepo ExpenseRepository
+	entryRepo   BudgetEntryRepository
+	notifier    BudgetNotifier
+	locks       LockCounter
+	fiscal      models.FiscalCalendar
 }
 
-func NewBudgetService(repo BudgetRepository, expenseRepo ExpenseRepository) *BudgetService {
+func NewBudgetService(repo BudgetRepository, expenseRepo ExpenseRepository, entryRepo BudgetEntryRepository, notifier BudgetNotifier, locks LockCounter, fiscal models.FiscalCalendar) *BudgetService {
 	return &BudgetService{
 		repo:        repo,
 		expenseRepo: expenseRepo,
+		locks:       locks,
+		entryRepo:   entryRepo,
+		notifier:    notifier,
+		fiscal:      fiscal,
 	}
 }
 
-func (s *BudgetService) GetAll(year int) ([]models.Budget, error) {
+func (s *BudgetService) FiscalCalendar() models.FiscalCalendar {
+	return s.fiscal
+}
+
+func (s *BudgetService) GetAll(ctx context.Context, year int) ([]models.Budget, error) {
 	if year <= 0 {
 		return nil, errors.New("year must be greater than 0")
 	}
-	return s.repo.GetAll(year)
+	return s.repo.GetAll(ctx, year)
 }
 
-func (s *BudgetService) GetDashboardSummary(year int) (*models.BudgetDashboardSummary, error) {
+func (s *BudgetService) GetDashboardSummary(ctx context.Context, year int) (*models.BudgetDashboardSummary, error) {
 	if year <= 0 {
 		return nil, errors.New("year must be greater than 0")
 	}
-	return s.repo.GetDashboardSummary(year)
+	return s.repo.GetDashboardSummary(ctx, year)
 }
 
-func (s *BudgetService) GetStatus(categoryID, year int) (*models.BudgetStatus, error) {
+func (s *BudgetService) SetSavingsTarget(ctx context.Context, req models.SavingsTargetRequest) (*models.BudgetDashboardSummary, error) {
+	if req.Year <= 0 {
+		return nil, errors.New("year must be greater than 0")
+	}
+	if req.Amount < 0 {
+		return nil, errors.New("savings target must be zero or greater")
+	}
+	if err := s.repo.SetSavingsTarget(ctx, req.Year, req.Amount); err != nil {
+		return nil, err
+	}
+	return s.repo.GetDashboardSummary(ctx, req.Year)
+}
+
+func (s *BudgetService) GetStatus(ctx context.Context, categoryID, year int) (*models.BudgetStatus, error) {
 	if categoryID <= 0 {
 		return nil, errors.New("category ID must be greater than 0")
 	}
@@ -55,7 +117,7 @@ func (s *BudgetService) GetStatus(categoryID, year int) (*models.BudgetStatus, e
 		return nil, errors.New("year must be greater than 0")
 	}
 
-	budget, err := s.repo.GetByCategory(categoryID, year)
+	budget, err := s.repo.GetByCategory(ctx, categoryID, year)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return &models.BudgetStatus{
@@ -69,60 +131,605 @@ func (s *BudgetService) GetStatus(categoryID, year int) (*models.BudgetStatus, e
 		return nil, err
 	}
 
-	spent, err := s.expenseRepo.GetYearlyTotal(categoryID, year)
+	spent, err := s.expenseRepo.GetYearlyTotal(ctx, categoryID, year)
 	if err != nil {
 		return nil, err
 	}
 
 	remaining := budget.Amount - spent
-	var percent float64
-	if budget.Amount > 0 {
-		percent = (spent / budget.Amount) * 100
-	}
+	percent := spent.PercentOf(budget.Amount)
 
 	return &models.BudgetStatus{
-		Allocated: budget.Amount,
-		Spent:     spent,
-		Remaining: remaining,
-		Percent:   percent,
-		IsLocked:  budget.IsLocked,
+		Allocated:       budget.Amount,
+		Spent:           spent,
+		Remaining:       remaining,
+		Percent:         percent,
+		IsLocked:        budget.IsLocked,
+		Period:          models.BudgetPeriodAnnual,
+		PeriodLabel:     models.BudgetPeriodAnnual.Label(s.fiscal, year, 1),
+		AnnualAllocated: budget.Amount,
+		AnnualSpent:     spent,
 	}, nil
 }
 
-func (s *BudgetService) CreateOrUpdate(categoryID int, amount float64, year int) (*models.Budget, error) {
+func (s *BudgetService) GetPeriodStatus(ctx context.Context, categoryID int, date time.Time) (*models.BudgetStatus, error) {
 	if categoryID <= 0 {
 		return nil, errors.New("category ID must be greater than 0")
 	}
-	if amount <= 0 {
-		return nil, errors.New("amount must be greater than 0")
+
+	budget, err := s.repo.GetByCategory(ctx, categoryID, s.fiscal.YearOf(date))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return &models.BudgetStatus{}, nil
+		}
+		return nil, err
 	}
-	if year <= 0 {
+
+	annualSpent, err := s.expenseRepo.GetYearlyTotal(ctx, categoryID, budget.Year)
+	if err != nil {
+		return nil, err
+	}
+
+	month := s.fiscal.MonthOf(date)
+	allocated, spent, err := s.periodFigures(ctx, budget, date)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BudgetStatus{
+		Allocated:       allocated,
+		Spent:           spent,
+		Remaining:       allocated - spent,
+		Percent:         spent.PercentOf(allocated),
+		IsLocked:        budget.LockApplies(s.fiscal, date),
+		Period:          budget.Period,
+		PeriodLabel:     budget.Period.Label(s.fiscal, budget.Year, month),
+		AnnualAllocated: budget.Amount,
+		AnnualSpent:     annualSpent,
+	}, nil
+}
+
+func (s *BudgetService) CreateOrUpdate(ctx context.Context, req models.BudgetRequest) (*models.Budget, error) {
+	if err := validateBudgetRequest(&req); err != nil {
+		return nil, err
+	}
+
+	budget, err := s.repo.CreateOrUpdate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.reevaluate(ctx, budget.ID)
+	return budget, nil
+}
+
+func validateBudgetRequest(req *models.BudgetRequest) error {
+	if req.CategoryID <= 0 {
+		return errors.New("category ID must be greater than 0")
+	}
+	if req.Amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	if req.Amount <= models.MinBudgetAmount {
+		return errors.New("budget amount must be greater than 10000")
+	}
+	if req.Year <= 0 {
+		return errors.New("year must be greater than 0")
+	}
+	if req.Period == "" {
+		req.Period = models.BudgetPeriodAnnual
+	}
+	if !req.Period.Valid() {
+		return errors.New("period must be annual, quarterly or monthly")
+	}
+	return models.ValidatePhasing(req.Amount, req.Phasing)
+}
+
+// RollForward proposes (and unless req.Preview is set, creates) budgets for
+// req.ToYear from the budgets and actual spend of req.FromYear. Items are
+// validated the same way for the preview and the commit, and the commit
+// saves all budgets in one transaction.
+func (s *BudgetService) RollForward(ctx context.Context, req models.RollForwardRequest, user *models.User) (*models.RollForwardResult, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	source, err := s.repo.GetAll(ctx, req.FromYear)
+	if err != nil {
+		return nil, err
+	}
+	target, err := s.repo.GetAll(ctx, req.ToYear)
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[int]models.Money, len(target))
+	for _, b := range target {
+		existing[b.CategoryID] = b.Amount
+	}
+
+	result := &models.RollForwardResult{FromYear: req.FromYear, ToYear: req.ToYear, Preview: req.Preview, Items: []models.RollForwardItem{}}
+	for _, b := range source {
+		if len(req.CategoryIDs) > 0 && !slices.Contains(req.CategoryIDs, b.CategoryID) {
+			continue
+		}
+
+		spent, err := s.expenseRepo.GetYearlyTotal(ctx, b.CategoryID, req.FromYear)
+		if err != nil {
+			return nil, err
+		}
+		_, carry, proposed := req.Apply(b.Amount, spent)
+
+		item := models.RollForwardItem{
+			CategoryID:     b.CategoryID,
+			CategoryName:   b.CategoryName,
+			Period:         b.Period,
+			SourceAmount:   b.Amount,
+			SourceSpent:    spent,
+			CarryOver:      carry,
+			ProposedAmount: proposed,
+			Action:         "create",
+			Phasing:        models.ScalePhasing(b.Phasing, proposed),
+		}
+		if amount, ok := existing[b.CategoryID]; ok {
+			item.ExistingAmount = &amount
+			item.Action = "update"
+			if !req.Overwrite {
+				item.Action = "skip"
+				item.Note = fmt.Sprintf("a %d budget already exists", req.ToYear)
+			}
+		}
+		if item.Action != "skip" && proposed <= 0 {
+			item.Action = "skip"
+			item.Note = "proposed amount is zero"
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	var reqs []models.BudgetRequest
+	for i := range result.Items {
+		item := &result.Items[i]
+		if item.Action != "skip" {
+			budgetReq := models.BudgetRequest{
+				CategoryID: item.CategoryID,
+				Amount:     item.ProposedAmount,
+				Year:       req.ToYear,
+				Period:     item.Period,
+				Phasing:    item.Phasing,
+				Reason:     fmt.Sprintf("Rolled forward from %d", req.FromYear),
+				ChangedBy:  user.ID,
+			}
+			if err := validateBudgetRequest(&budgetReq); err != nil {
+				item.Action = "skip"
+				item.Note = err.Error()
+			} else {
+				reqs = append(reqs, budgetReq)
+			}
+		}
+
+		switch {
+		case item.Action == "skip":
+			result.Skipped++
+			continue
+		case item.ExistingAmount != nil:
+			result.Updated++
+		default:
+			result.Created++
+		}
+		result.TotalProposed += item.ProposedAmount
+	}
+
+	if !req.Preview && len(reqs) > 0 {
+		budgets, err := s.repo.CreateOrUpdateMany(ctx, reqs)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range budgets {
+			s.reevaluate(ctx, b.ID)
+		}
+	}
+
+	if !req.Preview {
+		logging.FromContext(ctx).Info("budgets rolled forward", "from_year", req.FromYear, "to_year", req.ToYear,
+			"created", result.Created, "updated", result.Updated, "skipped", result.Skipped)
+	}
+	return result, nil
+}
+
+func (s *BudgetService) GetHistory(ctx context.Context, budgetID int) (*models.BudgetHistory, error) {
+	if budgetID <= 0 {
+		return nil, errors.New("budget ID must be greater than 0")
+	}
+
+	budget, err := s.repo.GetByID(ctx, budgetID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("budget not found")
+		}
+		return nil, err
+	}
+
+	entries, err := s.entryRepo.GetByBudgetID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BudgetHistory{
+		Budget:          budget,
+		Entries:         entries,
+		EffectiveAmount: budget.Amount,
+	}, nil
+}
+
+func (s *BudgetService) AddSupplementary(ctx context.Context, budgetID int, req models.BudgetAllocationRequest, user *models.User) (*models.BudgetEntry, error) {
+	if budgetID <= 0 {
+		return nil, errors.New("budget ID must be greater than 0")
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("supplementary amount must be greater than 0")
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return nil, errors.New("a reason is required for supplementary allocations")
+	}
+
+	entry, err := s.entryRepo.AddAllocation(ctx, budgetID, req.Amount, models.BudgetEntrySupplementary, req.Reason, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.reevaluate(ctx, budgetID)
+	return entry, nil
+}
+
+func (s *BudgetService) Transfer(ctx context.Context, req models.BudgetTransferRequest, user *models.User) (*models.BudgetTransfer, error) {
+	if req.FromCategoryID <= 0 || req.ToCategoryID <= 0 {
+		return nil, errors.New("source and target category IDs must be greater than 0")
+	}
+	if req.FromCategoryID == req.ToCategoryID {
+		return nil, errors.New("source and target category must be different")
+	}
+	if req.Year <= 0 {
 		return nil, errors.New("year must be greater than 0")
 	}
+	if req.Amount <= 0 {
+		return nil, errors.New("transfer amount must be greater than 0")
+	}
+	req.Reason = strings.TrimSpace(req.Reason)
+	if req.Reason == "" {
+		return nil, errors.New("a reason is required for transfers")
+	}
 
-	return s.repo.CreateOrUpdate(categoryID, amount, year)
+	transfer, err := s.entryRepo.Transfer(ctx, req, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	s.reevaluate(ctx, transfer.FromBudgetID)
+	s.reevaluate(ctx, transfer.ToBudgetID)
+	return transfer, nil
 }
 
-func (s *BudgetService) ToggleLock(budgetID int, isLocked bool) error {
+// reevaluate re-applies the auto-lock policy after a budget's allocation
+// changed. Failures are logged rather than undoing the committed change.
+func (s *BudgetService) reevaluate(ctx context.Context, budgetID int) {
+	budget, err := s.repo.GetByID(ctx, budgetID)
+	if err == nil {
+		if now := time.Now(); s.fiscal.YearOf(now) == budget.Year {
+			err = s.EvaluateLock(ctx, budget.CategoryID, now)
+		}
+	}
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to re-evaluate budget lock", "budget_id", budgetID, "error", err)
+	}
+}
+
+func (s *BudgetService) ToggleLock(ctx context.Context, budgetID int, isLocked bool, user *models.User) error {
+	if budgetID <= 0 {
+		return errors.New("budget ID must be greater than 0")
+	}
+	return s.repo.ToggleLock(ctx, budgetID, isLocked, user.ID)
+}
+
+func (s *BudgetService) UpdatePolicy(ctx context.Context, budgetID int, policy models.BudgetPolicy) (*models.Budget, error) {
+	if budgetID <= 0 {
+		return nil, errors.New("budget ID must be greater than 0")
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdatePolicy(ctx, budgetID, policy); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("budget not found")
+		}
+		return nil, err
+	}
+
+	budget, err := s.repo.GetByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	if now := time.Now(); s.fiscal.YearOf(now) == budget.Year {
+		if err := s.EvaluateLock(ctx, budget.CategoryID, now); err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.GetByID(ctx, budgetID)
+}
+
+func (s *BudgetService) SetOverride(ctx context.Context, budgetID int, req models.BudgetOverrideRequest, user *models.User) error {
 	if budgetID <= 0 {
 		return errors.New("budget ID must be greater than 0")
 	}
-	return s.repo.ToggleLock(budgetID, isLocked)
+
+	var until *time.Time
+	if req.Until != "" {
+		d, err := time.Parse("2006-01-02", req.Until)
+		if err != nil {
+			return errors.New("until must be in YYYY-MM-DD format")
+		}
+		until = &d
+		req.Reason = strings.TrimSpace(req.Reason)
+		if req.Reason == "" {
+			return errors.New("a reason is required for overrides")
+		}
+	}
+
+	if err := s.repo.SetOverride(ctx, budgetID, until, req.Reason, user.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("budget not found")
+		}
+		return err
+	}
+	logging.FromContext(ctx).Info("budget override changed", "budget_id", budgetID, "until", req.Until, "user_id", user.ID)
+	return nil
+}
+
+func (s *BudgetService) GetLockEvents(ctx context.Context, budgetID int) ([]models.BudgetLockEvent, error) {
+	if budgetID <= 0 {
+		return nil, errors.New("budget ID must be greater than 0")
+	}
+	return s.repo.GetLockEvents(ctx, budgetID)
+}
+
+// CheckExpense applies the circuit breaker and the over-budget policy of the
+// category's budget, and of every budget above it, to an expense of amount
+// (in base currency) dated date.
+func (s *BudgetService) CheckExpense(ctx context.Context, categoryID int, date time.Time, amount models.Money) error {
+	budgets, err := s.repo.GetByCategoryPath(ctx, categoryID, s.fiscal.YearOf(date))
+	if err != nil {
+		return err
+	}
+	for i := range budgets {
+		if err := s.checkBudget(ctx, &budgets[i], date, amount); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BudgetService) checkBudget(ctx context.Context, budget *models.Budget, date time.Time, amount models.Money) error {
+	if budget.LockApplies(s.fiscal, date) {
+		return repository.ErrBudgetLocked
+	}
+	if !budget.RejectOverBudget || budget.OverrideActive(date) {
+		return nil
+	}
+
+	allocated, spent, err := s.periodFigures(ctx, budget, date)
+	if err != nil {
+		return err
+	}
+	if spent+amount > allocated {
+		return repository.ErrOverBudget
+	}
+	return nil
+}
+
+// EvaluateLock engages the circuit breaker once spend for the period
+// containing date reaches the budget's auto-lock threshold, and releases an
+// automatic lock when spend falls back below it (e.g. after a budget increase).
+// Budgets above the category are evaluated too, as its spend rolls up to them.
+func (s *BudgetService) EvaluateLock(ctx context.Context, categoryID int, date time.Time) error {
+	budgets, err := s.repo.GetByCategoryPath(ctx, categoryID, s.fiscal.YearOf(date))
+	if err != nil {
+		return err
+	}
+	for i := range budgets {
+		if err := s.evaluateLock(ctx, &budgets[i], date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BudgetService) evaluateLock(ctx context.Context, budget *models.Budget, date time.Time) error {
+	if budget.AutoLockPercent == nil || budget.OverrideActive(date) {
+		return nil
+	}
+
+	allocated, spent, err := s.periodFigures(ctx, budget, date)
+	if err != nil {
+		return err
+	}
+	percent := spent.PercentOf(allocated)
+	threshold := *budget.AutoLockPercent
+	label := budget.Period.Label(s.fiscal, budget.Year, s.fiscal.MonthOf(date))
+	logger := logging.FromContext(ctx)
+
+	switch {
+	case percent >= threshold && !budget.LockApplies(s.fiscal, date):
+		reason := fmt.Sprintf("Spend for %s reached %.2f%% of budget (threshold %.2f%%)", label, percent, threshold)
+		if err := s.repo.SetAutoLock(ctx, budget.ID, true, date, reason); err != nil {
+			return err
+		}
+		s.locks.IncBudgetLocks()
+		logger.Warn("budget automatically locked", "budget_id", budget.ID, "category_id", budget.CategoryID, "period", label, "percent", percent, "threshold", threshold)
+	case percent < threshold && budget.AutoLocked && budget.LockApplies(s.fiscal, date):
+		reason := fmt.Sprintf("Spend for %s fell to %.2f%% of budget (threshold %.2f%%)", label, percent, threshold)
+		if err := s.repo.SetAutoLock(ctx, budget.ID, false, date, reason); err != nil {
+			return err
+		}
+		logger.Info("budget automatically unlocked", "budget_id", budget.ID, "category_id", budget.CategoryID, "period", label, "percent", percent, "threshold", threshold)
+	}
+	return nil
+}
+
+func (s *BudgetService) UpdateAlertThresholds(ctx context.Context, budgetID int, req models.BudgetAlertRequest) (*models.Budget, error) {
+	if budgetID <= 0 {
+		return nil, errors.New("budget ID must be greater than 0")
+	}
+
+	thresholds := make([]float64, 0, len(req.Thresholds))
+	for _, t := range req.Thresholds {
+		if t <= 0 || t > 999 {
+			return nil, errors.New("alert thresholds must be between 0 and 999")
+		}
+		if !slices.Contains(thresholds, t) {
+			thresholds = append(thresholds, t)
+		}
+	}
+	slices.Sort(thresholds)
+
+	if err := s.repo.UpdateAlertThresholds(ctx, budgetID, thresholds); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("budget not found")
+		}
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, budgetID)
+}
+
+// EvaluateAlerts notifies management of every alert threshold that spend for
+// the period containing date has reached, on the category's budget and every
+// budget above it. Each threshold fires once per period.
+func (s *BudgetService) EvaluateAlerts(ctx context.Context, categoryID int, date time.Time) error {
+	budgets, err := s.repo.GetByCategoryPath(ctx, categoryID, s.fiscal.YearOf(date))
+	if err != nil {
+		return err
+	}
+	for i := range budgets {
+		if err := s.evaluateAlerts(ctx, &budgets[i], date); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BudgetService) evaluateAlerts(ctx context.Context, budget *models.Budget, date time.Time) error {
+	if len(budget.AlertThresholds) == 0 {
+		return nil
+	}
+
+	allocated, spent, err := s.periodFigures(ctx, budget, date)
+	if err != nil {
+		return err
+	}
+	percent := spent.PercentOf(allocated)
+	month := s.fiscal.MonthOf(date)
+	periodStart, _ := budget.PeriodBounds(s.fiscal, month)
+	label := budget.Period.Label(s.fiscal, budget.Year, month)
+
+	var managers []models.User
+	loaded := false
+	for _, threshold := range budget.AlertThresholds {
+		if percent < threshold {
+			continue
+		}
+		if !loaded {
+			if managers, err = s.notifier.Managers(ctx); err != nil {
+				return err
+			}
+			loaded = true
+		}
+
+		budgetID := budget.ID
+		n := models.Notification{
+			Type:     models.NotificationBudgetThreshold,
+			Title:    fmt.Sprintf("%s budget reached %g%%", budget.CategoryName, threshold),
+			Message:  fmt.Sprintf("%s has spent %s of its %s budget of %s for %s (%.2f%%).", budget.CategoryName, spent, budget.Period, allocated, label, percent),
+			BudgetID: &budgetID,
+		}
+		notifications := make([]models.Notification, len(managers))
+		for i, u := range managers {
+			notifications[i] = n
+			notifications[i].UserID = u.ID
+		}
+		// Claiming the alert and storing its notifications in one transaction
+		// fires each threshold once per period, even for concurrent expenses.
+		claimed, err := s.repo.RecordAlert(ctx, budget.ID, threshold, periodStart, percent, notifications)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			continue
+		}
+		s.notifier.Email(ctx, managers, n)
+		logging.FromContext(ctx).Info("budget threshold alert sent", "budget_id", budget.ID, "threshold", threshold, "percent", percent, "period", label)
+	}
+	return nil
 }
 
-func (s *BudgetService) GetMonitoringData(year int) ([]models.BudgetMonitoringItem, error) {
+func (s *BudgetService) periodFigures(ctx context.Context, budget *models.Budget, date time.Time) (models.Money, models.Money, error) {
+	month := s.fiscal.MonthOf(date)
+	start, end := budget.PeriodBounds(s.fiscal, month)
+	spent, err := s.expenseRepo.GetTotalBetween(ctx, budget.CategoryID, start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+	return budget.PeriodAllocation(month), spent, nil
+}
+
+// GetMonitoringData returns annual figures per budget plus the figures for
+// the period (month, quarter or year, depending on the budget) containing asOf.
+func (s *BudgetService) GetMonitoringData(ctx context.Context, year int, asOf time.Time) ([]models.BudgetMonitoringItem, error) {
 	if year <= 0 {
 		return nil, errors.New("year must be greater than 0")
 	}
-	return s.repo.GetMonitoringData(year)
+
+	items, err := s.repo.GetMonitoringData(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	monthly, err := s.expenseRepo.GetMonthlyTotals(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	month := s.fiscal.MonthOf(asOf)
+	for i := range items {
+		item := &items[i]
+		budget := models.Budget{
+			ID:         item.BudgetID,
+			CategoryID: item.CategoryID,
+			Amount:     item.BudgetAmount,
+			Year:       year,
+			Period:     item.Period,
+			Phasing:    item.Phasing,
+			IsLocked:   item.IsLocked,
+			LockedAt:   item.LockedAt,
+		}
+
+		first, last := item.Period.PeriodMonths(month)
+		var spent models.Money
+		if totals, ok := monthly[item.CategoryID]; ok {
+			for m := first; m <= last; m++ {
+				spent += totals[m-1]
+			}
+		}
+
+		item.PeriodStart, item.PeriodEnd = budget.PeriodBounds(s.fiscal, month)
+		item.PeriodLabel = item.Period.Label(s.fiscal, year, month)
+		item.PeriodBudgetAmount = budget.PeriodAllocation(month)
+		item.PeriodSpentAmount = spent
+		item.PeriodPercentage = spent.PercentOf(item.PeriodBudgetAmount)
+		item.IsLocked = budget.LockApplies(s.fiscal, asOf)
+	}
+
+	return items, nil
 }
 
-func (s *BudgetService) IsLocked(categoryID, year int) (bool, error) {
+func (s *BudgetService) IsLocked(ctx context.Context, categoryID int, date time.Time) (bool, error) {
 	if categoryID <= 0 {
 		return false, errors.New("category ID must be greater than 0")
 	}
-	if year <= 0 {
-		return false, errors.New("year must be greater than 0")
-	}
-	return s.repo.IsLocked(categoryID, year)
+	return s.repo.IsLocked(ctx, categoryID, date)
 }
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"expense-tracker/internal/logging"
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+type CategorizationService struct {
+	repo     repository.CategorizationRuleRepository
+	currency CurrencyConverter
+}
+
+func NewCategorizationService(repo repository.CategorizationRuleRepository, currency CurrencyConverter) *CategorizationService {
+	return &CategorizationService{repo: repo, currency: currency}
+}
+
+func (s *CategorizationService) GetRules(ctx context.Context) ([]models.CategorizationRule, error) {
+	return s.repo.GetAll(ctx, false)
+}
+
+func (s *CategorizationService) CreateRule(ctx context.Context, req models.CategorizationRuleRequest) (*models.CategorizationRule, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, req)
+}
+
+func (s *CategorizationService) UpdateRule(ctx context.Context, id int, req models.CategorizationRuleRequest) (*models.CategorizationRule, error) {
+	if id <= 0 {
+		return nil, errors.New("rule ID must be greater than 0")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, req)
+}
+
+func (s *CategorizationService) DeleteRule(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("rule ID must be greater than 0")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// Suggest returns the category of the first active rule, in priority order,
+// that matches req, or nil when none does.
+func (s *CategorizationService) Suggest(ctx context.Context, req models.CategorySuggestionRequest) (*models.CategorySuggestion, error) {
+	rules, err := s.repo.GetAll(ctx, true)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, rule := range rules {
+		if !rule.Matches(req.Remarks, req.Amount, req.UserID) {
+			continue
+		}
+		logging.FromContext(ctx).Debug("categorization rule matched", "rule_id", rule.ID, "category_id", rule.CategoryID)
+		return &models.CategorySuggestion{
+			CategoryID:   rule.CategoryID,
+			CategoryName: rule.CategoryName,
+			RuleID:       rule.ID,
+			RuleName:     rule.Name,
+			AutoAssign:   rule.AutoAssign,
+		}, nil
+	}
+	return nil, nil
+}
+
+// SuggestEntered suggests a category for an amount as entered on the expense
+// form, converting it to the base currency the rule bounds are stored in.
+func (s *CategorizationService) SuggestEntered(ctx context.Context, req models.CategorySuggestionRequest) (*models.CategorySuggestion, error) {
+	if req.Currency != "" {
+		code, err := models.NormalizeCurrency(req.Currency)
+		if err != nil {
+			return nil, err
+		}
+		date := time.Now()
+		if req.ExpenseDate != "" {
+			if date, err = time.Parse("2006-01-02", req.ExpenseDate); err != nil {
+				return nil, errors.New("expense date must be in YYYY-MM-DD format")
+			}
+		}
+		if req.Amount, _, err = s.currency.Convert(ctx, req.Amount, code, date); err != nil {
+			return nil, err
+		}
+	}
+	return s.Suggest(ctx, req)
+}
+
+func (s *CategorizationService) GetOverrideReport(ctx context.Context, start, end string) ([]models.RuleOverrideStats, error) {
+	for _, d := range []string{start, end} {
+		if d == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", d); err != nil {
+			return nil, errors.New("dates must be in YYYY-MM-DD format")
+		}
+	}
+	return s.repo.GetOverrideStats(ctx, start, end)
+}
@@ -1,19 +1,23 @@
 package service
 
 import (
+	"context"
 	"errors"
 	"strings"
 
+	"expense-tracker/internal/logging"
 	"expense-tracker/internal/models"
 )
 
 type CategoryRepository interface {
-	GetAll(activeOnly bool) ([]models.Category, error)
-	GetByID(id int) (*models.Category, error)
-	Create(name string, isActive bool) (*models.Category, error)
-	Update(id int, name string, isActive bool) (*models.Category, error)
-	ToggleStatus(id int) (*models.Category, error)
-	ExistsByName(name string) (bool, error)
+	GetAll(ctx context.Context, activeOnly bool) ([]models.Category, error)
+	GetByID(ctx context.Context, id int) (*models.Category, error)
+	Create(ctx context.Context, name string, isActive bool, parentID *int) (*models.Category, error)
+	Update(ctx context.Context, id int, name string, isActive bool, parentID *int) (*models.Category, error)
+	ToggleStatus(ctx context.Context, id int) (*models.Category, error)
+	ExistsByName(ctx context.Context, name string) (bool, error)
+	Merge(ctx context.Context, sourceID, targetID, userID int) (*models.CategoryMerge, error)
+	Delete(ctx context.Context, id int) error
 }
 
 type CategoryService struct {
@@ -24,35 +28,87 @@ func NewCategoryService(repo CategoryRepository) *CategoryService {
 	return &CategoryService{repo: repo}
 }
 
-func (s *CategoryService) GetAll(activeOnly bool) ([]models.Category, error) {
-	return s.repo.GetAll(activeOnly)
+func (s *CategoryService) GetAll(ctx context.Context, activeOnly bool) ([]models.Category, error) {
+	return s.repo.GetAll(ctx, activeOnly)
 }
 
-func (s *CategoryService) GetByID(id int) (*models.Category, error) {
-	return s.repo.GetByID(id)
+func (s *CategoryService) GetByID(ctx context.Context, id int) (*models.Category, error) {
+	return s.repo.GetByID(ctx, id)
 }
 
-func (s *CategoryService) Create(name string, isActive bool) (*models.Category, error) {
+// GetTree returns the categories nested under their parents.
+func (s *CategoryService) GetTree(ctx context.Context, activeOnly bool) ([]models.CategoryNode, error) {
+	categories, err := s.repo.GetAll(ctx, activeOnly)
+	if err != nil {
+		return nil, err
+	}
+	return models.NewCategoryTree(categories).Nodes(), nil
+}
+
+func (s *CategoryService) Create(ctx context.Context, name string, isActive bool, parentID *int) (*models.Category, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil, errors.New("category name is required")
 	}
-	return s.repo.Create(name, isActive)
+	if parentID != nil && *parentID <= 0 {
+		return nil, errors.New("parent category ID must be greater than 0")
+	}
+	return s.repo.Create(ctx, name, isActive, parentID)
 }
 
-func (s *CategoryService) Update(id int, name string, isActive bool) (*models.Category, error) {
+func (s *CategoryService) Update(ctx context.Context, id int, name string, isActive bool, parentID *int) (*models.Category, error) {
 	name = strings.TrimSpace(name)
 	if name == "" {
 		return nil, errors.New("category name is required")
 	}
-	return s.repo.Update(id, name, isActive)
+	if parentID != nil && *parentID <= 0 {
+		return nil, errors.New("parent category ID must be greater than 0")
+	}
+	return s.repo.Update(ctx, id, name, isActive, parentID)
 }
 
-func (s *CategoryService) ToggleStatus(id int) (*models.Category, error) {
-	return s.repo.ToggleStatus(id)
+func (s *CategoryService) ToggleStatus(ctx context.Context, id int) (*models.Category, error) {
+	return s.repo.ToggleStatus(ctx, id)
 }
 
-func (s *CategoryService) InitializeDefaults() error {
+// Merge folds sourceID into req.TargetID, keeping all of its expenses and
+// budgets, and removes sourceID.
+func (s *CategoryService) Merge(ctx context.Context, sourceID int, req models.CategoryMergeRequest, user *models.User) (*models.CategoryMerge, error) {
+	if sourceID <= 0 || req.TargetID <= 0 {
+		return nil, errors.New("source and target category IDs must be greater than 0")
+	}
+	if sourceID == req.TargetID {
+		return nil, errors.New("source and target category must be different")
+	}
+
+	merge, err := s.repo.Merge(ctx, sourceID, req.TargetID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("category merged", "source_id", sourceID, "target_id", req.TargetID,
+		"expenses", merge.ExpensesMoved, "budgets_moved", merge.BudgetsMoved, "budgets_combined", merge.BudgetsCombined, "user_id", user.ID)
+	return merge, nil
+}
+
+func (s *CategoryService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("category ID must be greater than 0")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// InitializeDefaults seeds the default categories into an empty table. Once
+// any category exists the defaults are left alone, so merged or deleted
+// defaults do not come back on the next start.
+func (s *CategoryService) InitializeDefaults(ctx context.Context) error {
+	existing, err := s.repo.GetAll(ctx, false)
+	if err != nil {
+		return err
+	}
+	if len(existing) > 0 {
+		return nil
+	}
+
 	defaultCategories := []string{
 		"Food",
 		"Transport",
@@ -66,13 +122,13 @@ func (s *CategoryService) InitializeDefaults() error {
 	}
 
 	for _, name := range defaultCategories {
-		exists, err := s.repo.ExistsByName(name)
+		exists, err := s.repo.ExistsByName(ctx, name)
 		if err != nil {
 			return err
 		}
 
 		if !exists {
-			_, err := s.repo.Create(name, true)
+			_, err := s.repo.Create(ctx, name, true, nil)
 			if err != nil {
 				return err
 			}
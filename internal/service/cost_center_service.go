@@ -0,0 +1,260 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"expense-tracker/internal/logging"
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+type CostCenterService struct {
+	repo   repository.CostCenterRepository
+	fiscal models.FiscalCalendar
+}
+
+func NewCostCenterService(repo repository.CostCenterRepository, fiscal models.FiscalCalendar) *CostCenterService {
+	return &CostCenterService{repo: repo, fiscal: fiscal}
+}
+
+func (s *CostCenterService) FiscalCalendar() models.FiscalCalendar {
+	return s.fiscal
+}
+
+func (s *CostCenterService) GetAll(ctx context.Context, activeOnly bool) ([]models.CostCenter, error) {
+	return s.repo.GetAll(ctx, activeOnly)
+}
+
+func (s *CostCenterService) Create(ctx context.Context, req models.CostCenterRequest) (*models.CostCenter, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, req)
+}
+
+func (s *CostCenterService) Update(ctx context.Context, id int, req models.CostCenterRequest) (*models.CostCenter, error) {
+	if id <= 0 {
+		return nil, errors.New("cost center ID must be greater than 0")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, req)
+}
+
+func (s *CostCenterService) GetBudgets(ctx context.Context, year int) ([]models.CostCenterBudget, error) {
+	if year <= 0 {
+		return nil, errors.New("year must be greater than 0")
+	}
+	return s.repo.GetBudgets(ctx, year)
+}
+
+func (s *CostCenterService) SetBudget(ctx context.Context, req models.CostCenterBudgetRequest) (*models.CostCenterBudget, error) {
+	if req.CostCenterID <= 0 {
+		return nil, errors.New("cost center ID must be greater than 0")
+	}
+	if req.Year <= 0 {
+		return nil, errors.New("year must be greater than 0")
+	}
+	if req.Amount <= 0 {
+		return nil, errors.New("amount must be greater than 0")
+	}
+	if req.Period == "" {
+		req.Period = models.BudgetPeriodAnnual
+	}
+	if !req.Period.Valid() {
+		return nil, errors.New("period must be annual, quarterly or monthly")
+	}
+	if err := models.ValidatePhasing(req.Amount, req.Phasing); err != nil {
+		return nil, err
+	}
+
+	budget, err := s.repo.SetBudget(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	s.reevaluate(ctx, budget)
+	return s.repo.GetBudgetByID(ctx, budget.ID)
+}
+
+func (s *CostCenterService) ToggleLock(ctx context.Context, budgetID int, isLocked bool, user *models.User) error {
+	if budgetID <= 0 {
+		return errors.New("budget ID must be greater than 0")
+	}
+	if err := s.repo.ToggleLock(ctx, budgetID, isLocked); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("cost center budget not found")
+		}
+		return err
+	}
+	logging.FromContext(ctx).Info("cost center circuit breaker changed", "budget_id", budgetID, "is_locked", isLocked, "user_id", user.ID)
+	return nil
+}
+
+func (s *CostCenterService) UpdatePolicy(ctx context.Context, budgetID int, policy models.BudgetPolicy) (*models.CostCenterBudget, error) {
+	if budgetID <= 0 {
+		return nil, errors.New("budget ID must be greater than 0")
+	}
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdatePolicy(ctx, budgetID, policy); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, errors.New("cost center budget not found")
+		}
+		return nil, err
+	}
+
+	budget, err := s.repo.GetBudgetByID(ctx, budgetID)
+	if err != nil {
+		return nil, err
+	}
+	s.reevaluate(ctx, budget)
+	return s.repo.GetBudgetByID(ctx, budgetID)
+}
+
+// reevaluate re-checks the auto-lock of a current-year budget whose amount or
+// policy changed. Failures are logged rather than undoing the change.
+func (s *CostCenterService) reevaluate(ctx context.Context, budget *models.CostCenterBudget) {
+	now := time.Now()
+	if s.fiscal.YearOf(now) != budget.Year {
+		return
+	}
+	if err := s.evaluateLock(ctx, budget, now); err != nil {
+		logging.FromContext(ctx).Error("failed to re-evaluate cost center lock", "budget_id", budget.ID, "error", err)
+	}
+}
+
+// CheckExpense applies the cost center's circuit breaker and over-budget
+// policy to an expense of amount (in base currency) dated date, against the
+// budget period the date falls into.
+func (s *CostCenterService) CheckExpense(ctx context.Context, costCenterID int, date time.Time, amount models.Money) error {
+	costCenter, err := s.repo.GetByID(ctx, costCenterID)
+	if err != nil {
+		return err
+	}
+	if !costCenter.IsActive {
+		return repository.ErrCostCenterInactive
+	}
+
+	budget, err := s.repo.GetBudget(ctx, costCenterID, s.fiscal.YearOf(date))
+	if err != nil || budget == nil {
+		return err
+	}
+	if budget.Schedule().LockApplies(s.fiscal, date) {
+		return repository.ErrCostCenterLocked
+	}
+	if !budget.RejectOverBudget {
+		return nil
+	}
+
+	allocated, spent, err := s.periodFigures(ctx, budget, date)
+	if err != nil {
+		return err
+	}
+	if spent+amount > allocated {
+		return repository.ErrCostCenterOverBudget
+	}
+	return nil
+}
+
+// EvaluateLock engages the circuit breaker once spend for the period
+// containing date reaches the cost center budget's auto-lock threshold, and
+// releases an automatic lock when spend falls back below it.
+func (s *CostCenterService) EvaluateLock(ctx context.Context, costCenterID int, date time.Time) error {
+	budget, err := s.repo.GetBudget(ctx, costCenterID, s.fiscal.YearOf(date))
+	if err != nil || budget == nil {
+		return err
+	}
+	return s.evaluateLock(ctx, budget, date)
+}
+
+func (s *CostCenterService) evaluateLock(ctx context.Context, budget *models.CostCenterBudget, date time.Time) error {
+	if budget.AutoLockPercent == nil {
+		return nil
+	}
+
+	allocated, spent, err := s.periodFigures(ctx, budget, date)
+	if err != nil {
+		return err
+	}
+	percent := spent.PercentOf(allocated)
+	threshold := *budget.AutoLockPercent
+	locked := budget.Schedule().LockApplies(s.fiscal, date)
+	label := budget.Period.Label(s.fiscal, budget.Year, s.fiscal.MonthOf(date))
+	logger := logging.FromContext(ctx)
+
+	switch {
+	case percent >= threshold && !locked:
+		if err := s.repo.SetAutoLock(ctx, budget.ID, true, date); err != nil {
+			return err
+		}
+		logger.Warn("cost center budget automatically locked", "budget_id", budget.ID, "cost_center_id", budget.CostCenterID, "period", label, "percent", percent, "threshold", threshold)
+	case percent < threshold && budget.AutoLocked && locked:
+		if err := s.repo.SetAutoLock(ctx, budget.ID, false, date); err != nil {
+			return err
+		}
+		logger.Info("cost center budget automatically unlocked", "budget_id", budget.ID, "cost_center_id", budget.CostCenterID, "period", label, "percent", percent, "threshold", threshold)
+	}
+	return nil
+}
+
+// periodFigures returns the allocation of, and spend in, the budget period
+// containing date.
+func (s *CostCenterService) periodFigures(ctx context.Context, budget *models.CostCenterBudget, date time.Time) (models.Money, models.Money, error) {
+	schedule := budget.Schedule()
+	month := s.fiscal.MonthOf(date)
+	start, end := schedule.PeriodBounds(s.fiscal, month)
+	spent, err := s.repo.GetTotalBetween(ctx, budget.CostCenterID, start, end)
+	if err != nil {
+		return 0, 0, err
+	}
+	return schedule.PeriodAllocation(month), spent, nil
+}
+
+// GetMonitoringData returns spend against budget for the fiscal year, one row
+// per cost center, plus the figures for the budget period containing asOf.
+func (s *CostCenterService) GetMonitoringData(ctx context.Context, year int, asOf time.Time) ([]models.CostCenterMonitoringItem, error) {
+	if year <= 0 {
+		return nil, errors.New("year must be greater than 0")
+	}
+
+	items, err := s.repo.GetMonitoringData(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	month := s.fiscal.MonthOf(asOf)
+	for i := range items {
+		item := &items[i]
+		if item.BudgetID == 0 {
+			continue
+		}
+		budget := models.CostCenterBudget{
+			ID:           item.BudgetID,
+			CostCenterID: item.CostCenterID,
+			Year:         year,
+			Amount:       item.BudgetAmount,
+			Period:       item.Period,
+			Phasing:      item.Phasing,
+			IsLocked:     item.IsLocked,
+			LockedAt:     item.LockedAt,
+		}
+		allocated, spent, err := s.periodFigures(ctx, &budget, asOf)
+		if err != nil {
+			return nil, err
+		}
+
+		item.PeriodStart, item.PeriodEnd = budget.Schedule().PeriodBounds(s.fiscal, month)
+		item.PeriodLabel = item.Period.Label(s.fiscal, year, month)
+		item.PeriodBudgetAmount = allocated
+		item.PeriodSpentAmount = spent
+		item.PeriodPercentage = spent.PercentOf(allocated)
+		item.IsLocked = budget.Schedule().LockApplies(s.fiscal, asOf)
+	}
+	return items, nil
+}
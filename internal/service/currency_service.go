@@ -0,0 +1,247 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+const (
+	baseCurrencySetting = "base_currency"
+	defaultBaseCurrency = "USD"
+	ecbBaseCurrency     = "EUR"
+)
+
+type CurrencyService struct {
+	repo     repository.ExchangeRateRepository
+	settings repository.SettingsRepository
+}
+
+func NewCurrencyService(repo repository.ExchangeRateRepository, settings repository.SettingsRepository) *CurrencyService {
+	return &CurrencyService{
+		repo:     repo,
+		settings: settings,
+	}
+}
+
+func (s *CurrencyService) BaseCurrency(ctx context.Context) (string, error) {
+	value, ok, err := s.settings.Get(ctx, baseCurrencySetting)
+	if err != nil {
+		return "", err
+	}
+	if !ok || value == "" {
+		return defaultBaseCurrency, nil
+	}
+	return value, nil
+}
+
+func (s *CurrencyService) Convert(ctx context.Context, amount models.Money, currency string, date time.Time) (models.Money, string, error) {
+	base, err := s.BaseCurrency(ctx)
+	if err != nil {
+		return 0, "", err
+	}
+
+	rate, err := s.RateFor(ctx, currency, base, date)
+	if err != nil {
+		return 0, "", err
+	}
+	return amount.MulRat(rate), rate.FloatString(8), nil
+}
+
+// RateFor returns how many units of `to` one unit of `from` buys on date,
+// using a direct rate, its inverse, or a cross rate through a shared base.
+func (s *CurrencyService) RateFor(ctx context.Context, from, to string, date time.Time) (*big.Rat, error) {
+	if from == to {
+		return big.NewRat(1, 1), nil
+	}
+
+	direct, err := s.repo.FindEffective(ctx, from, to, date)
+	if err != nil {
+		return nil, err
+	}
+	if direct != nil {
+		return models.ParseRate(direct.Rate)
+	}
+
+	inverse, err := s.repo.FindEffective(ctx, to, from, date)
+	if err != nil {
+		return nil, err
+	}
+	if inverse != nil {
+		r, err := models.ParseRate(inverse.Rate)
+		if err != nil {
+			return nil, err
+		}
+		return new(big.Rat).Inv(r), nil
+	}
+
+	pivot, err := s.repo.FindCrossBase(ctx, from, to, date)
+	if err != nil {
+		return nil, err
+	}
+	if pivot != "" {
+		fromLeg, err := s.repo.FindEffective(ctx, pivot, from, date)
+		if err != nil {
+			return nil, err
+		}
+		toLeg, err := s.repo.FindEffective(ctx, pivot, to, date)
+		if err != nil {
+			return nil, err
+		}
+		if fromLeg != nil && toLeg != nil {
+			r1, err := models.ParseRate(fromLeg.Rate)
+			if err != nil {
+				return nil, err
+			}
+			r2, err := models.ParseRate(toLeg.Rate)
+			if err != nil {
+				return nil, err
+			}
+			return new(big.Rat).Quo(r2, r1), nil
+		}
+	}
+
+	return nil, fmt.Errorf("no exchange rate from %s to %s on or before %s", from, to, date.Format("2006-01-02"))
+}
+
+func (s *CurrencyService) GetRates(ctx context.Context, currency string) ([]models.ExchangeRate, error) {
+	if currency != "" {
+		code, err := models.NormalizeCurrency(currency)
+		if err != nil {
+			return nil, err
+		}
+		currency = code
+	}
+	return s.repo.GetAll(ctx, currency)
+}
+
+func (s *CurrencyService) AddRate(ctx context.Context, req models.ExchangeRateRequest) (*models.ExchangeRate, error) {
+	rate, err := buildRate(req.BaseCurrency, req.QuoteCurrency, req.Rate, req.EffectiveDate, "manual")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Upsert(ctx, rate); err != nil {
+		return nil, err
+	}
+	return rate, nil
+}
+
+// ImportCSV reads rows of effective_date,base_currency,quote_currency,rate.
+// A header row is skipped when present.
+func (s *CurrencyService) ImportCSV(ctx context.Context, r io.Reader) (*models.ExchangeRateImportResult, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	result := &models.ExchangeRateImportResult{}
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		if line == 1 && len(record) > 0 && strings.EqualFold(strings.TrimSpace(record[0]), "effective_date") {
+			continue
+		}
+		if len(record) < 4 {
+			result.Skipped++
+			result.Errors = append(result.Errors, fmt.Sprintf("line %d: expected 4 columns", line))
+			continue
+		}
+
+		rate, err := buildRate(record[1], record[2], record[3], record[0], "csv")
+		if err != nil {
+			result.Skipped++
+			result.Errors = append(result.Errors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		if err := s.repo.Upsert(ctx, rate); err != nil {
+			return nil, err
+		}
+		result.Imported++
+	}
+	return result, nil
+}
+
+type ecbEnvelope struct {
+	Cube struct {
+		Days []struct {
+			Time  string `xml:"time,attr"`
+			Rates []struct {
+				Currency string `xml:"currency,attr"`
+				Rate     string `xml:"rate,attr"`
+			} `xml:"Cube"`
+		} `xml:"Cube"`
+	} `xml:"Cube"`
+}
+
+// ImportECB reads the European Central Bank eurofxref XML feed, where every
+// rate is quoted against EUR.
+func (s *CurrencyService) ImportECB(ctx context.Context, r io.Reader) (*models.ExchangeRateImportResult, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, fmt.Errorf("invalid ECB XML: %v", err)
+	}
+	if len(envelope.Cube.Days) == 0 {
+		return nil, errors.New("ECB XML contains no rates")
+	}
+
+	result := &models.ExchangeRateImportResult{}
+	for _, day := range envelope.Cube.Days {
+		for _, entry := range day.Rates {
+			rate, err := buildRate(ecbBaseCurrency, entry.Currency, entry.Rate, day.Time, "ecb")
+			if err != nil {
+				result.Skipped++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s %s: %v", day.Time, entry.Currency, err))
+				continue
+			}
+			if err := s.repo.Upsert(ctx, rate); err != nil {
+				return nil, err
+			}
+			result.Imported++
+		}
+	}
+	return result, nil
+}
+
+func buildRate(base, quote, rate, date, source string) (*models.ExchangeRate, error) {
+	baseCode, err := models.NormalizeCurrency(base)
+	if err != nil {
+		return nil, err
+	}
+	quoteCode, err := models.NormalizeCurrency(quote)
+	if err != nil {
+		return nil, err
+	}
+	if baseCode == quoteCode {
+		return nil, errors.New("base and quote currency must differ")
+	}
+	if _, err := models.ParseRate(rate); err != nil {
+		return nil, err
+	}
+	effective, err := time.Parse("2006-01-02", strings.TrimSpace(date))
+	if err != nil {
+		return nil, fmt.Errorf("invalid effective date %q", date)
+	}
+
+	return &models.ExchangeRate{
+		BaseCurrency:  baseCode,
+		QuoteCurrency: quoteCode,
+		Rate:          strings.TrimSpace(rate),
+		EffectiveDate: effective,
+		Source:        source,
+	}, nil
+}
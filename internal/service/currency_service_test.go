@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"expense-tracker/internal/models"
+)
+
+// fakeRates holds one rate per base/quote pair, effective on every date.
+type fakeRates map[[2]string]string
+
+func (f fakeRates) Upsert(ctx context.Context, rate *models.ExchangeRate) error {
+	f[[2]string{rate.BaseCurrency, rate.QuoteCurrency}] = rate.Rate
+	return nil
+}
+
+func (f fakeRates) GetAll(ctx context.Context, currency string) ([]models.ExchangeRate, error) {
+	return nil, nil
+}
+
+func (f fakeRates) FindEffective(ctx context.Context, base, quote string, date time.Time) (*models.ExchangeRate, error) {
+	rate, ok := f[[2]string{base, quote}]
+	if !ok {
+		return nil, nil
+	}
+	return &models.ExchangeRate{BaseCurrency: base, QuoteCurrency: quote, Rate: rate, EffectiveDate: date}, nil
+}
+
+func (f fakeRates) FindCrossBase(ctx context.Context, from, to string, date time.Time) (string, error) {
+	for pair := range f {
+		if _, ok := f[[2]string{pair[0], from}]; !ok {
+			continue
+		}
+		if _, ok := f[[2]string{pair[0], to}]; ok {
+			return pair[0], nil
+		}
+	}
+	return "", nil
+}
+
+type fakeSettings map[string]string
+
+func (f fakeSettings) Get(ctx context.Context, key string) (string, bool, error) {
+	v, ok := f[key]
+	return v, ok, nil
+}
+
+func (f fakeSettings) Set(ctx context.Context, key, value string) error {
+	f[key] = value
+	return nil
+}
+
+func TestCurrencyServiceConvert(t *testing.T) {
+	rates := fakeRates{
+		{"EUR", "USD"}: "1.25",
+		{"EUR", "GBP"}: "0.8",
+	}
+	svc := NewCurrencyService(rates, fakeSettings{baseCurrencySetting: "USD"})
+	date := time.Date(2026, 3, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		amount   models.Money
+		currency string
+		want     models.Money
+		wantRate string
+		wantErr  bool
+	}{
+		{name: "base currency", amount: 1000, currency: "USD", want: 1000, wantRate: "1.00000000"},
+		{name: "direct rate", amount: 1000, currency: "EUR", want: 1250, wantRate: "1.25000000"},
+		{name: "cross rate through EUR", amount: 1000, currency: "GBP", want: 1563, wantRate: "1.56250000"},
+		{name: "missing rate", amount: 1000, currency: "JPY", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, rate, err := svc.Convert(context.Background(), tt.amount, tt.currency, date)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: Convert succeeded, want error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: Convert returned error: %v", tt.name, err)
+			continue
+		}
+		if got != tt.want || rate != tt.wantRate {
+			t.Errorf("%s: Convert = %d at %s, want %d at %s", tt.name, got, rate, tt.want, tt.wantRate)
+		}
+	}
+}
+
+func TestCurrencyServiceRateForInverse(t *testing.T) {
+	svc := NewCurrencyService(fakeRates{{"USD", "EUR"}: "0.8"}, fakeSettings{})
+	rate, err := svc.RateFor(context.Background(), "EUR", "USD", time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := rate.FloatString(2); got != "1.25" {
+		t.Errorf("RateFor(EUR, USD) = %s, want 1.25", got)
+	}
+}
+
+func TestBuildRate(t *testing.T) {
+	tests := []struct {
+		name                    string
+		base, quote, rate, date string
+		wantErr                 bool
+	}{
+		{name: "valid", base: "eur", quote: "usd", rate: "1.08", date: "2026-01-02"},
+		{name: "same currency", base: "EUR", quote: "eur", rate: "1", date: "2026-01-02", wantErr: true},
+		{name: "bad rate", base: "EUR", quote: "USD", rate: "-1", date: "2026-01-02", wantErr: true},
+		{name: "bad date", base: "EUR", quote: "USD", rate: "1.08", date: "02/01/2026", wantErr: true},
+		{name: "bad code", base: "EURO", quote: "USD", rate: "1.08", date: "2026-01-02", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := buildRate(tt.base, tt.quote, tt.rate, tt.date, "manual")
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: buildRate succeeded, want error", tt.name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: buildRate returned error: %v", tt.name, err)
+			continue
+		}
+		if got.BaseCurrency != "EUR" || got.QuoteCurrency != "USD" {
+			t.Errorf("%s: buildRate = %s/%s, want EUR/USD", tt.name, got.BaseCurrency, got.QuoteCurrency)
+		}
+	}
+}
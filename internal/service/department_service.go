@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"expense-tracker/internal/logging"
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+type DepartmentService struct {
+	repo     repository.DepartmentRepository
+	userRepo repository.UserRepository
+	fiscal   models.FiscalCalendar
+}
+
+func NewDepartmentService(repo repository.DepartmentRepository, userRepo repository.UserRepository, fiscal models.FiscalCalendar) *DepartmentService {
+	return &DepartmentService{repo: repo, userRepo: userRepo, fiscal: fiscal}
+}
+
+func (s *DepartmentService) FiscalCalendar() models.FiscalCalendar {
+	return s.fiscal
+}
+
+func (s *DepartmentService) GetAll(ctx context.Context) ([]models.Department, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *DepartmentService) Create(ctx context.Context, req models.DepartmentRequest) (*models.Department, error) {
+	if err := s.validate(ctx, &req); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, req)
+}
+
+func (s *DepartmentService) Update(ctx context.Context, id int, req models.DepartmentRequest) (*models.Department, error) {
+	if id <= 0 {
+		return nil, errors.New("department ID must be greater than 0")
+	}
+	if err := s.validate(ctx, &req); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, req)
+}
+
+func (s *DepartmentService) validate(ctx context.Context, req *models.DepartmentRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	if req.ManagerID == nil {
+		return nil
+	}
+	manager, err := s.userRepo.GetByID(ctx, *req.ManagerID)
+	if err != nil {
+		return err
+	}
+	if manager.Role != models.RoleManagement {
+		return errors.New("department manager must be a management user")
+	}
+	return nil
+}
+
+func (s *DepartmentService) SetUserDepartment(ctx context.Context, req models.UserDepartmentRequest) error {
+	if req.UserID <= 0 {
+		return errors.New("user ID must be greater than 0")
+	}
+	if err := s.repo.SetUserDepartment(ctx, req.UserID, req.DepartmentID); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("user department changed", "user_id", req.UserID, "department_id", req.DepartmentID)
+	return nil
+}
+
+// VisibleDepartments returns the departments whose expenses user may see and
+// approve: nil (no restriction) for admins and executives, who are limited
+// to their own expenses elsewhere, and the managed departments for management.
+func (s *DepartmentService) VisibleDepartments(ctx context.Context, user *models.User) ([]int, error) {
+	if user.Role != models.RoleManagement {
+		return nil, nil
+	}
+	return s.repo.ManagedBy(ctx, user.ID)
+}
+
+func (s *DepartmentService) GetBudgets(ctx context.Context, year int, user *models.User) ([]models.DepartmentBudget, error) {
+	if year <= 0 {
+		return nil, errors.New("year must be greater than 0")
+	}
+	visible, err := s.VisibleDepartments(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetBudgets(ctx, year, visible)
+}
+
+func (s *DepartmentService) SetBudget(ctx context.Context, req models.DepartmentBudgetRequest) error {
+	if req.DepartmentID <= 0 {
+		return errors.New("department ID must be greater than 0")
+	}
+	if req.Year <= 0 {
+		return errors.New("year must be greater than 0")
+	}
+	if req.Amount <= 0 {
+		return errors.New("amount must be greater than 0")
+	}
+	return s.repo.SetBudget(ctx, req)
+}
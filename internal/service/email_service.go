@@ -1,11 +1,12 @@
 package service
 
 import (
-	"log"
+	"log/slog"
 )
 
 type EmailService interface {
 	SendPasswordSetEmail(email, token string) error
+	SendNotificationEmail(email, subject, message string) error
 }
 
 type mockEmailService struct{}
@@ -16,6 +17,11 @@ func NewEmailService() EmailService {
 
 func (s *mockEmailService) SendPasswordSetEmail(email, token string) error {
 	resetLink := "http://localhost:8080/set-password?token=" + token
-	log.Printf("[EMAIL MOCK] To: %s | Subject: Set Your Password | Message: Please click the link to set your password: %s", email, resetLink)
+	slog.Info("mock email sent", "to", email, "subject", "Set Your Password", "link", resetLink)
+	return nil
+}
+
+func (s *mockEmailService) SendNotificationEmail(email, subject, message string) error {
+	slog.Info("mock email sent", "to", email, "subject", subject, "body", message)
 	return nil
 }
@@ -1,43 +1,95 @@
 package service
 
 import (
+	"context"
 	"errors"
-	"strconv"
+	"slices"
+	"strings"
+	"time"
 
+	"expense-tracker/internal/logging"
 	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
 )
 
 type ExpenseRepositoryInterface interface {
-	Create(req models.ExpenseRequest) (*models.Expense, error)
-	GetAll(filter models.ExpenseFilter) ([]models.Expense, error)
-	Delete(id int) error
-	GetInsights(filter models.ExpenseFilter) (*models.ExpenseInsights, error)
+	Create(ctx context.Context, req models.ExpenseRequest) (*models.Expense, error)
+	GetAll(ctx context.Context, filter models.ExpenseFilter) ([]models.Expense, error)
+	Delete(ctx context.Context, id int) error
+	GetByID(ctx context.Context, id int) (*models.Expense, error)
+	SetApproval(ctx context.Context, id int, status models.ApprovalStatus, userID int, note string) error
+	GetInsights(ctx context.Context, filter models.ExpenseFilter) (*models.ExpenseInsights, error)
 }
 
-type BudgetRepositoryInterface interface {
-	IsLocked(categoryID, year int) (bool, error)
+type BudgetGuard interface {
+	CheckExpense(ctx context.Context, categoryID int, date time.Time, amount models.Money) error
+	EvaluateLock(ctx context.Context, categoryID int, date time.Time) error
+	EvaluateAlerts(ctx context.Context, categoryID int, date time.Time) error
+}
+
+type CurrencyConverter interface {
+	BaseCurrency(ctx context.Context) (string, error)
+	Convert(ctx context.Context, amount models.Money, currency string, date time.Time) (models.Money, string, error)
+}
+
+type CategorySuggester interface {
+	Suggest(ctx context.Context, req models.CategorySuggestionRequest) (*models.CategorySuggestion, error)
+}
+
+// CostCenterGuard applies cost center budgets and locks to new expenses.
+type CostCenterGuard interface {
+	CheckExpense(ctx context.Context, costCenterID int, date time.Time, amount models.Money) error
+	EvaluateLock(ctx context.Context, costCenterID int, date time.Time) error
+}
+
+// DepartmentScope tells which departments a management user is responsible for.
+type DepartmentScope interface {
+	VisibleDepartments(ctx context.Context, user *models.User) ([]int, error)
+}
+
+// VendorResolver maps an entered vendor name to a vendor, creating it if new.
+type VendorResolver interface {
+	Resolve(ctx context.Context, name string) (*int, error)
+}
+
+// TaxRateLookup finds the tax rate chosen for a new expense.
+type TaxRateLookup interface {
+	GetByID(ctx context.Context, id int) (*models.TaxRate, error)
 }
 
 type ExpenseService struct {
-	repo       ExpenseRepositoryInterface
-	budgetRepo BudgetRepositoryInterface
+	repo        ExpenseRepositoryInterface
+	budget      BudgetGuard
+	currency    CurrencyConverter
+	categorizer CategorySuggester
+	costCenters CostCenterGuard
+	departments DepartmentScope
+	vendors     VendorResolver
+	taxes       TaxRateLookup
 }
 
-func NewExpenseService(repo ExpenseRepositoryInterface, budgetRepo BudgetRepositoryInterface) *ExpenseService {
+func NewExpenseService(repo ExpenseRepositoryInterface, budget BudgetGuard, currency CurrencyConverter, categorizer CategorySuggester, costCenters CostCenterGuard, departments DepartmentScope, vendors VendorResolver, taxes TaxRateLookup) *ExpenseService {
 	return &ExpenseService{
-		repo:       repo,
-		budgetRepo: budgetRepo,
+		repo:        repo,
+		budget:      budget,
+		currency:    currency,
+		categorizer: categorizer,
+		costCenters: costCenters,
+		departments: departments,
+		vendors:     vendors,
+		taxes:       taxes,
 	}
 }
 
-func (s *ExpenseService) Create(req models.ExpenseRequest, user *models.User) (*models.Expense, error) {
+func (s *ExpenseService) Create(ctx context.Context, req models.ExpenseRequest, user *models.User) (*models.Expense, error) {
 	if !(user.Role == models.RoleExecutive || user.Role == models.RoleAdmin) {
 		return nil, errors.New("only executives and admins can enter expenses")
 	}
 
 	req.UserID = user.ID
-	if req.CategoryID <= 0 {
-		return nil, errors.New("category ID is required")
+	req.ApprovedBy = nil
+	if user.IsAdmin() {
+		req.ApprovedBy = &user.ID
 	}
 	if req.Amount <= 0 {
 		return nil, errors.New("amount must be greater than 0")
@@ -45,48 +97,285 @@ func (s *ExpenseService) Create(req models.ExpenseRequest, user *models.User) (*
 	if req.ExpenseDate == "" {
 		return nil, errors.New("expense date is required")
 	}
+	expenseDate, err := time.Parse("2006-01-02", req.ExpenseDate)
+	if err != nil {
+		return nil, errors.New("expense date must be in YYYY-MM-DD format")
+	}
+	tags, err := models.NormalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	req.Tags = tags
+	if err := req.PaymentMethod.Validate(); err != nil {
+		return nil, err
+	}
 
-	if len(req.ExpenseDate) >= 4 {
-		year, err := strconv.Atoi(req.ExpenseDate[:4])
-		if err == nil {
-			isLocked, err := s.budgetRepo.IsLocked(req.CategoryID, year)
-			if err != nil {
-				return nil, errors.New("failed to check budget lock status")
-			}
-			if isLocked {
-				return nil, errors.New("spending is temporarily locked for this category")
+	if err := s.applyTax(ctx, &req); err != nil {
+		return nil, err
+	}
+	if err := s.applyCurrency(ctx, &req, expenseDate); err != nil {
+		return nil, err
+	}
+	if req.Tax != nil && req.BaseAmount != req.Amount {
+		req.Tax.NetAmount, req.Tax.TaxAmount = req.Tax.Rate.SplitGross(req.BaseAmount)
+	}
+
+	s.applySuggestion(ctx, &req)
+	if req.CategoryID <= 0 {
+		return nil, errors.New("category ID is required")
+	}
+
+	if err := s.budget.CheckExpense(ctx, req.CategoryID, expenseDate, req.BaseAmount); err != nil {
+		if errors.Is(err, repository.ErrBudgetLocked) || errors.Is(err, repository.ErrOverBudget) {
+			return nil, err
+		}
+		logging.FromContext(ctx).Error("failed to check budget lock status", "category_id", req.CategoryID, "date", req.ExpenseDate, "error", err)
+		return nil, errors.New("failed to check budget lock status")
+	}
+
+	if req.CostCenterID != nil {
+		if err := s.costCenters.CheckExpense(ctx, *req.CostCenterID, expenseDate, req.BaseAmount); err != nil {
+			if errors.Is(err, repository.ErrCostCenterNotFound) || errors.Is(err, repository.ErrCostCenterInactive) ||
+				errors.Is(err, repository.ErrCostCenterLocked) || errors.Is(err, repository.ErrCostCenterOverBudget) {
+				return nil, err
 			}
+			logging.FromContext(ctx).Error("failed to check cost center budget", "cost_center_id", *req.CostCenterID, "date", req.ExpenseDate, "error", err)
+			return nil, errors.New("failed to check cost center budget")
+		}
+	}
+
+	req.VendorID, err = s.vendors.Resolve(ctx, req.Vendor)
+	if err != nil {
+		return nil, err
+	}
+
+	expense, err := s.repo.Create(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.budget.EvaluateLock(ctx, req.CategoryID, expenseDate); err != nil {
+		logging.FromContext(ctx).Error("failed to evaluate budget auto-lock", "category_id", req.CategoryID, "date", req.ExpenseDate, "error", err)
+	}
+	if err := s.budget.EvaluateAlerts(ctx, req.CategoryID, expenseDate); err != nil {
+		logging.FromContext(ctx).Error("failed to evaluate budget alerts", "category_id", req.CategoryID, "date", req.ExpenseDate, "error", err)
+	}
+	if req.CostCenterID != nil {
+		if err := s.costCenters.EvaluateLock(ctx, *req.CostCenterID, expenseDate); err != nil {
+			logging.FromContext(ctx).Error("failed to evaluate cost center auto-lock", "cost_center_id", *req.CostCenterID, "date", req.ExpenseDate, "error", err)
 		}
 	}
+	return expense, nil
+}
 
-	return s.repo.Create(req)
+// applySuggestion records which category the categorization rules suggest
+// and, when no category was chosen, assigns it if the rule allows. A failing
+// lookup only loses the suggestion.
+func (s *ExpenseService) applySuggestion(ctx context.Context, req *models.ExpenseRequest) {
+	suggestion, err := s.categorizer.Suggest(ctx, models.CategorySuggestionRequest{
+		Remarks: req.Remarks,
+		Amount:  req.BaseAmount,
+		UserID:  req.UserID,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to suggest expense category", "error", err)
+		return
+	}
+	if suggestion == nil {
+		return
+	}
+
+	req.SuggestedCategoryID = &suggestion.CategoryID
+	req.SuggestionRuleID = &suggestion.RuleID
+	if req.CategoryID <= 0 && suggestion.AutoAssign {
+		req.CategoryID = suggestion.CategoryID
+	}
 }
 
-func (s *ExpenseService) GetAll(filter models.ExpenseFilter, user *models.User) ([]models.Expense, error) {
-	if user.Role == models.RoleExecutive {
-		filter.UserID = user.ID
+// applyTax splits the entered amount by the chosen tax rate. A tax-exclusive
+// amount becomes the gross amount; foreign currency amounts are split again
+// after conversion.
+func (s *ExpenseService) applyTax(ctx context.Context, req *models.ExpenseRequest) error {
+	req.Tax = nil
+	if req.TaxRateID == nil {
+		return nil
+	}
+	rate, err := s.taxes.GetByID(ctx, *req.TaxRateID)
+	if err != nil {
+		return err
+	}
+	if !rate.IsActive {
+		return errors.New("tax rate must be active")
+	}
+
+	tax := models.ExpenseTax{Rate: *rate}
+	if req.TaxInclusive != nil && !*req.TaxInclusive {
+		tax.NetAmount = req.Amount
+		req.Amount, tax.TaxAmount = rate.AddTax(req.Amount)
+	} else {
+		tax.NetAmount, tax.TaxAmount = rate.SplitGross(req.Amount)
+	}
+	req.Tax = &tax
+	return nil
+}
+
+func (s *ExpenseService) applyCurrency(ctx context.Context, req *models.ExpenseRequest, expenseDate time.Time) error {
+	base, err := s.currency.BaseCurrency(ctx)
+	if err != nil {
+		return err
+	}
+
+	if req.Currency == "" {
+		req.Currency = base
+	}
+	code, err := models.NormalizeCurrency(req.Currency)
+	if err != nil {
+		return err
+	}
+	req.Currency = code
+
+	converted, rate, err := s.currency.Convert(ctx, req.Amount, req.Currency, expenseDate)
+	if err != nil {
+		return err
+	}
+	req.BaseAmount = converted
+	req.ExchangeRate = rate
+	return nil
+}
+
+func (s *ExpenseService) GetAll(ctx context.Context, filter models.ExpenseFilter, user *models.User) ([]models.Expense, error) {
+	if err := s.applyVisibility(ctx, &filter, user); err != nil {
+		return nil, err
 	}
 
 	if err := filter.Validate(); err != nil {
 		return nil, err
 	}
-	return s.repo.GetAll(filter)
+	return s.repo.GetAll(ctx, filter)
 }
 
-func (s *ExpenseService) GetInsights(filter models.ExpenseFilter, user *models.User) (*models.ExpenseInsights, error) {
-	if user.Role == models.RoleExecutive {
-		filter.UserID = user.ID
+func (s *ExpenseService) GetInsights(ctx context.Context, filter models.ExpenseFilter, user *models.User) (*models.ExpenseInsights, error) {
+	if err := s.applyVisibility(ctx, &filter, user); err != nil {
+		return nil, err
 	}
 
 	if err := filter.Validate(); err != nil {
 		return nil, err
 	}
-	return s.repo.GetInsights(filter)
+	return s.repo.GetInsights(ctx, filter)
+}
+
+// applyVisibility limits executives to their own expenses and management to
+// the departments they manage; admins see everything.
+func (s *ExpenseService) applyVisibility(ctx context.Context, filter *models.ExpenseFilter, user *models.User) error {
+	if user.Role == models.RoleExecutive {
+		filter.UserID = user.ID
+		return nil
+	}
+	visible, err := s.departments.VisibleDepartments(ctx, user)
+	if err != nil {
+		return err
+	}
+	filter.VisibleDepartments = visible
+	return nil
+}
+
+// Approve approves a pending expense. Management may only approve expenses
+// of the departments they manage.
+func (s *ExpenseService) Approve(ctx context.Context, id int, req models.ExpenseApprovalRequest, user *models.User) error {
+	return s.decide(ctx, id, models.ApprovalApproved, strings.TrimSpace(req.Note), user)
+}
+
+// Reject rejects a pending expense; it then no longer counts against budgets.
+func (s *ExpenseService) Reject(ctx context.Context, id int, req models.ExpenseApprovalRequest, user *models.User) error {
+	note := strings.TrimSpace(req.Note)
+	if note == "" {
+		return errors.New("a reason is required to reject an expense")
+	}
+	return s.decide(ctx, id, models.ApprovalRejected, note, user)
+}
+
+func (s *ExpenseService) decide(ctx context.Context, id int, status models.ApprovalStatus, note string, user *models.User) error {
+	if !user.CanManage() {
+		return errors.New("only management and admins can approve expenses")
+	}
+	if id <= 0 {
+		return errors.New("expense ID must be greater than 0")
+	}
+
+	expense, err := s.getVisible(ctx, id, user)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.SetApproval(ctx, id, status, user.ID, note); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("expense approval decided", "expense_id", id, "status", status, "user_id", user.ID)
+
+	if status == models.ApprovalRejected {
+		// Rejected spend no longer counts, which may release an automatic lock.
+		if err := s.budget.EvaluateLock(ctx, expense.CategoryID, expense.ExpenseDate); err != nil {
+			logging.FromContext(ctx).Error("failed to evaluate budget auto-lock", "category_id", expense.CategoryID, "error", err)
+		}
+		if expense.CostCenterID != nil {
+			if err := s.costCenters.EvaluateLock(ctx, *expense.CostCenterID, expense.ExpenseDate); err != nil {
+				logging.FromContext(ctx).Error("failed to evaluate cost center auto-lock", "cost_center_id", *expense.CostCenterID, "error", err)
+			}
+		}
+	}
+	return nil
 }
 
-func (s *ExpenseService) Delete(id int) error {
+// Delete removes an expense the user can see: executives their own,
+// management those of the departments they manage, admins any. Expenses
+// already collected into a payout batch are refused.
+func (s *ExpenseService) Delete(ctx context.Context, id int, user *models.User) error {
 	if id <= 0 {
 		return errors.New("expense ID must be greater than 0")
 	}
-	return s.repo.Delete(id)
+	expense, err := s.getVisible(ctx, id, user)
+	if err != nil {
+		return err
+	}
+	if expense.PayoutBatchID != nil {
+		return repository.ErrExpenseInPayout
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	// The deleted spend no longer counts, which may release an automatic lock.
+	if err := s.budget.EvaluateLock(ctx, expense.CategoryID, expense.ExpenseDate); err != nil {
+		logging.FromContext(ctx).Error("failed to evaluate budget auto-lock", "category_id", expense.CategoryID, "error", err)
+	}
+	if expense.CostCenterID != nil {
+		if err := s.costCenters.EvaluateLock(ctx, *expense.CostCenterID, expense.ExpenseDate); err != nil {
+			logging.FromContext(ctx).Error("failed to evaluate cost center auto-lock", "cost_center_id", *expense.CostCenterID, "error", err)
+		}
+	}
+	return nil
+}
+
+// getVisible loads an expense, reporting expenses outside the user's
+// visibility as not found, the same way GetAll leaves them out.
+func (s *ExpenseService) getVisible(ctx context.Context, id int, user *models.User) (*models.Expense, error) {
+	expense, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == models.RoleExecutive {
+		if expense.UserID == nil || *expense.UserID != user.ID {
+			return nil, errors.New("expense not found")
+		}
+		return expense, nil
+	}
+	visible, err := s.departments.VisibleDepartments(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if visible != nil && (expense.DepartmentID == nil || !slices.Contains(visible, *expense.DepartmentID)) {
+		return nil, errors.New("expense not found")
+	}
+	return expense, nil
 }
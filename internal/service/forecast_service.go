@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"expense-tracker/internal/models"
+)
+
+// forecastHistoryYears is how many prior fiscal years feed the seasonal profile.
+const forecastHistoryYears = 3
+
+type ForecastExpenseRepository interface {
+	GetMonthlyTotals(ctx context.Context, year int) (map[int][]models.Money, error)
+}
+
+type ForecastService struct {
+	expenseRepo ForecastExpenseRepository
+	fiscal      models.FiscalCalendar
+}
+
+func NewForecastService(expenseRepo ForecastExpenseRepository, fiscal models.FiscalCalendar) *ForecastService {
+	return &ForecastService{
+		expenseRepo: expenseRepo,
+		fiscal:      fiscal,
+	}
+}
+
+// Apply attaches a year-end projection to every monitoring item, as seen on
+// asOf. Spend to date is extrapolated over the rest of the year using each
+// category's monthly profile from prior years when there is one, and its
+// run-rate otherwise.
+func (s *ForecastService) Apply(ctx context.Context, year int, asOf time.Time, items []models.BudgetMonitoringItem) error {
+	current, err := s.expenseRepo.GetMonthlyTotals(ctx, year)
+	if err != nil {
+		return err
+	}
+
+	history := make([]map[int][]models.Money, 0, forecastHistoryYears)
+	for y := year - 1; y >= year-forecastHistoryYears; y-- {
+		totals, err := s.expenseRepo.GetMonthlyTotals(ctx, y)
+		if err != nil {
+			return err
+		}
+		history = append(history, totals)
+	}
+
+	elapsed := s.elapsedMonths(year, asOf)
+	for i := range items {
+		item := &items[i]
+		actuals := current[item.CategoryID]
+		if actuals == nil {
+			actuals = make([]models.Money, 12)
+		}
+
+		var prior []float64
+		var priorYears int
+		for _, totals := range history {
+			months, ok := totals[item.CategoryID]
+			if !ok {
+				continue
+			}
+			if prior == nil {
+				prior = make([]float64, 12)
+			}
+			for m, v := range months {
+				prior[m] += v.Float64()
+			}
+			priorYears++
+		}
+
+		item.Forecast = s.project(year, actuals, prior, priorYears, elapsed, item.BudgetAmount)
+	}
+	return nil
+}
+
+// elapsedMonths returns how much of the fiscal year has passed on asOf, in
+// months, counting the current month by the fraction of its days elapsed.
+func (s *ForecastService) elapsedMonths(year int, asOf time.Time) float64 {
+	start, end := s.fiscal.Bounds(year)
+	switch {
+	case asOf.Before(start):
+		return 0
+	case asOf.After(end):
+		return 12
+	}
+	month := s.fiscal.MonthOf(asOf)
+	daysInMonth := time.Date(asOf.Year(), asOf.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	return float64(month-1) + float64(asOf.Day())/float64(daysInMonth)
+}
+
+func (s *ForecastService) project(year int, actuals []models.Money, prior []float64, priorYears int, elapsed float64, budget models.Money) *models.CategoryForecast {
+	full := int(elapsed)
+	partial := elapsed - float64(full)
+
+	var spent float64
+	for _, v := range actuals {
+		spent += v.Float64()
+	}
+
+	var priorTotal float64
+	for _, v := range prior {
+		priorTotal += v
+	}
+
+	// weights[m] is the share of annual spend expected in fiscal month m.
+	weights := make([]float64, 12)
+	method := models.ForecastRunRate
+	if priorTotal > 0 {
+		method = models.ForecastSeasonal
+		for m, v := range prior {
+			weights[m] = v / priorTotal
+		}
+	} else {
+		for m := range weights {
+			weights[m] = 1.0 / 12
+		}
+	}
+
+	var seen float64
+	for m := 0; m < full && m < 12; m++ {
+		seen += weights[m]
+	}
+	if full < 12 {
+		seen += weights[full] * partial
+	}
+
+	// annual is the full-year spend implied by the pattern; the months still
+	// ahead are projected as their share of it.
+	var annual float64
+	switch {
+	case elapsed >= 12:
+		method = models.ForecastActual
+	case seen > 0 && spent > 0:
+		annual = spent / seen
+	case priorYears > 0:
+		method = models.ForecastHistory
+		annual = priorTotal / float64(priorYears)
+	}
+
+	monthly := make([]models.Money, 12)
+	for m := range monthly {
+		switch {
+		case m < full || elapsed >= 12:
+			monthly[m] = actuals[m]
+		case m == full:
+			remaining := annual * weights[m] * (1 - partial)
+			monthly[m] = actuals[m] + models.MoneyFromFloat(remaining)
+		default:
+			monthly[m] = models.MoneyFromFloat(annual * weights[m])
+		}
+	}
+
+	var projected models.Money
+	for _, v := range monthly {
+		projected += v
+	}
+
+	f := &models.CategoryForecast{
+		Method:             method,
+		ProjectedSpend:     projected,
+		ProjectedRemaining: budget - projected,
+		ProjectedPercent:   projected.PercentOf(budget),
+		MonthlyProjection:  monthly,
+	}
+	if budget > 0 && projected > budget {
+		f.WillExceed = true
+		f.ExceedDate = s.exceedDate(year, monthly, budget)
+	}
+	return f
+}
+
+// exceedDate finds the day on which cumulative projected spend first passes
+// budget, assuming spend is spread evenly within each month.
+func (s *ForecastService) exceedDate(year int, monthly []models.Money, budget models.Money) *time.Time {
+	var cumulative models.Money
+	for m, v := range monthly {
+		if cumulative+v > budget {
+			start := s.fiscal.MonthStart(year, m+1)
+			days := start.AddDate(0, 1, -1).Day()
+			fraction := float64(budget-cumulative) / float64(v)
+			day := start.AddDate(0, 0, int(fraction*float64(days)))
+			return &day
+		}
+		cumulative += v
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"expense-tracker/internal/logging"
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+const notificationFeedLimit = 50
+
+type NotificationService struct {
+	repo     repository.NotificationRepository
+	userRepo repository.UserRepository
+	email    EmailService
+}
+
+func NewNotificationService(repo repository.NotificationRepository, userRepo repository.UserRepository, email EmailService) *NotificationService {
+	return &NotificationService{
+		repo:     repo,
+		userRepo: userRepo,
+		email:    email,
+	}
+}
+
+// Managers returns the active admin and management users, who receive
+// budget alerts.
+func (s *NotificationService) Managers(ctx context.Context) ([]models.User, error) {
+	users, err := s.userRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	managers := []models.User{}
+	for _, u := range users {
+		if u.IsActive && u.CanManage() {
+			managers = append(managers, u)
+		}
+	}
+	return managers, nil
+}
+
+// Email sends n to the inbox of each user. Failures are logged and do not
+// stop delivery to the remaining users.
+func (s *NotificationService) Email(ctx context.Context, users []models.User, n models.Notification) {
+	logger := logging.FromContext(ctx)
+	for _, u := range users {
+		if err := s.email.SendNotificationEmail(u.Email, n.Title, n.Message); err != nil {
+			logger.Error("failed to send notification email", "user_id", u.ID, "type", n.Type, "error", err)
+		}
+	}
+}
+
+func (s *NotificationService) GetFeed(ctx context.Context, user *models.User, unreadOnly bool) (*models.NotificationFeed, error) {
+	notifications, err := s.repo.GetByUser(ctx, user.ID, unreadOnly, notificationFeedLimit)
+	if err != nil {
+		return nil, err
+	}
+	unread, err := s.repo.CountUnread(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.NotificationFeed{Notifications: notifications, UnreadCount: unread}, nil
+}
+
+func (s *NotificationService) MarkRead(ctx context.Context, id int, user *models.User) error {
+	if id <= 0 {
+		return errors.New("notification ID must be greater than 0")
+	}
+	if err := s.repo.MarkRead(ctx, id, user.ID); err != nil {
+		if err == sql.ErrNoRows {
+			return errors.New("notification not found")
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *NotificationService) MarkAllRead(ctx context.Context, user *models.User) error {
+	return s.repo.MarkAllRead(ctx, user.ID)
+}
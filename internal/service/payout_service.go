@@ -0,0 +1,403 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"expense-tracker/internal/logging"
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+const (
+	payoutDebtorNameSetting = "payout_debtor_name"
+	payoutDebtorIBANSetting = "payout_debtor_iban"
+	payoutDebtorBICSetting  = "payout_debtor_bic"
+)
+
+// PayoutService reimburses expenses paid personally: approved expenses with
+// the personal payment method are grouped into one batch per user, exported
+// for the bank and marked paid once transferred.
+type PayoutService struct {
+	repo        repository.PayoutRepository
+	settings    repository.SettingsRepository
+	currency    CurrencyConverter
+	departments DepartmentScope
+}
+
+func NewPayoutService(repo repository.PayoutRepository, settings repository.SettingsRepository, currency CurrencyConverter, departments DepartmentScope) *PayoutService {
+	return &PayoutService{
+		repo:        repo,
+		settings:    settings,
+		currency:    currency,
+		departments: departments,
+	}
+}
+
+func (s *PayoutService) GetPending(ctx context.Context, user *models.User) ([]models.PendingReimbursement, error) {
+	visible, err := s.departments.VisibleDepartments(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetPending(ctx, visible)
+}
+
+func (s *PayoutService) CreateBatches(ctx context.Context, req models.PayoutBatchRequest, user *models.User) ([]models.PayoutBatch, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	visible, err := s.departments.VisibleDepartments(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.currency.BaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	batches, err := s.repo.CreateBatches(ctx, req, visible, base, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range batches {
+		logging.FromContext(ctx).Info("payout batch created", "batch_id", b.ID, "user_id", b.UserID, "amount", b.TotalAmount.String(), "expenses", b.ExpenseCount, "created_by", user.ID)
+	}
+	return batches, nil
+}
+
+func (s *PayoutService) GetAll(ctx context.Context, status models.PayoutStatus, user *models.User) ([]models.PayoutBatch, error) {
+	if status != "" && status != models.PayoutOpen && status != models.PayoutPaid {
+		return nil, errors.New("status must be open or paid")
+	}
+	visible, err := s.departments.VisibleDepartments(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetAll(ctx, status, visible)
+}
+
+// GetByID returns a batch with its expenses and the payee's bank account.
+func (s *PayoutService) GetByID(ctx context.Context, id int, user *models.User) (*models.PayoutBatch, error) {
+	batch, err := s.getVisible(ctx, id, user)
+	if err != nil {
+		return nil, err
+	}
+	batch.Expenses, err = s.repo.GetExpenses(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := s.repo.GetBankAccounts(ctx, []int{batch.UserID})
+	if err != nil {
+		return nil, err
+	}
+	if account, ok := accounts[batch.UserID]; ok {
+		batch.BankAccount = &account
+	}
+	return batch, nil
+}
+
+func (s *PayoutService) getVisible(ctx context.Context, id int, user *models.User) (*models.PayoutBatch, error) {
+	if id <= 0 {
+		return nil, errors.New("payout batch ID must be greater than 0")
+	}
+	visible, err := s.departments.VisibleDepartments(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, id, visible)
+}
+
+func (s *PayoutService) MarkPaid(ctx context.Context, id int, req models.PayoutPaidRequest, user *models.User) (*models.PayoutBatch, error) {
+	if len(req.PaymentReference) > 35 {
+		return nil, errors.New("payment reference must be at most 35 characters")
+	}
+	if _, err := s.getVisible(ctx, id, user); err != nil {
+		return nil, err
+	}
+	if err := s.repo.MarkPaid(ctx, id, user.ID, req.PaymentReference); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("payout batch paid", "batch_id", id, "user_id", user.ID)
+	return s.repo.GetByID(ctx, id, nil)
+}
+
+// Cancel deletes an open batch so its expenses can be batched again.
+func (s *PayoutService) Cancel(ctx context.Context, id int, user *models.User) error {
+	if _, err := s.getVisible(ctx, id, user); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	logging.FromContext(ctx).Info("payout batch cancelled", "batch_id", id, "user_id", user.ID)
+	return nil
+}
+
+func (s *PayoutService) SetBankAccount(ctx context.Context, req models.BankAccountRequest) error {
+	if err := req.Validate(); err != nil {
+		return err
+	}
+	return s.repo.SetBankAccount(ctx, req)
+}
+
+// GetSettings returns the company account payouts are made from; fields are
+// empty until configured.
+func (s *PayoutService) GetSettings(ctx context.Context) (*models.PayoutSettings, error) {
+	var settings models.PayoutSettings
+	for key, dest := range map[string]*string{
+		payoutDebtorNameSetting: &settings.DebtorName,
+		payoutDebtorIBANSetting: &settings.DebtorIBAN,
+		payoutDebtorBICSetting:  &settings.DebtorBIC,
+	} {
+		value, _, err := s.settings.Get(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		*dest = value
+	}
+	return &settings, nil
+}
+
+func (s *PayoutService) UpdateSettings(ctx context.Context, settings models.PayoutSettings) (*models.PayoutSettings, error) {
+	if err := settings.Validate(); err != nil {
+		return nil, err
+	}
+	for key, value := range map[string]string{
+		payoutDebtorNameSetting: settings.DebtorName,
+		payoutDebtorIBANSetting: settings.DebtorIBAN,
+		payoutDebtorBICSetting:  settings.DebtorBIC,
+	} {
+		if err := s.settings.Set(ctx, key, value); err != nil {
+			return nil, err
+		}
+	}
+	return &settings, nil
+}
+
+// ExportBatches returns the batches to put in a payout file, with the
+// payees' bank accounts: the listed IDs, or every open batch when ids is empty.
+func (s *PayoutService) ExportBatches(ctx context.Context, ids []int, user *models.User) ([]models.PayoutBatch, error) {
+	visible, err := s.departments.VisibleDepartments(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	var batches []models.PayoutBatch
+	if len(ids) == 0 {
+		batches, err = s.repo.GetAll(ctx, models.PayoutOpen, visible)
+	} else {
+		batches, err = s.repo.GetByIDs(ctx, ids, visible)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(batches) == 0 {
+		return nil, errors.New("no payout batches found to export")
+	}
+
+	userIDs := make([]int, 0, len(batches))
+	for _, b := range batches {
+		userIDs = append(userIDs, b.UserID)
+	}
+	accounts, err := s.repo.GetBankAccounts(ctx, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	for i := range batches {
+		if account, ok := accounts[batches[i].UserID]; ok {
+			batches[i].BankAccount = &account
+		}
+	}
+	return batches, nil
+}
+
+func (s *PayoutService) WriteCSV(w io.Writer, batches []models.PayoutBatch) error {
+	writer := csv.NewWriter(w)
+	header := []string{"batch_id", "status", "user", "account_holder", "iban", "bic", "amount", "currency", "expense_count", "payment_reference"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, b := range batches {
+		var account models.BankAccount
+		if b.BankAccount != nil {
+			account = *b.BankAccount
+		}
+		record := []string{
+			strconv.Itoa(b.ID),
+			string(b.Status),
+			b.UserName,
+			account.AccountHolder,
+			account.IBAN,
+			account.BIC,
+			b.TotalAmount.String(),
+			b.Currency,
+			strconv.Itoa(b.ExpenseCount),
+			b.PaymentReference,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// pain001Document is an ISO 20022 customer credit transfer initiation
+// (pain.001.001.03) with one payment per batch.
+type pain001Document struct {
+	XMLName xml.Name `xml:"urn:iso:std:iso:20022:tech:xsd:pain.001.001.03 Document"`
+	GrpHdr  struct {
+		MsgId    string `xml:"MsgId"`
+		CreDtTm  string `xml:"CreDtTm"`
+		NbOfTxs  int    `xml:"NbOfTxs"`
+		CtrlSum  string `xml:"CtrlSum"`
+		InitgPty struct {
+			Nm string `xml:"Nm"`
+		} `xml:"InitgPty"`
+	} `xml:"CstmrCdtTrfInitn>GrpHdr"`
+	PmtInf struct {
+		PmtInfId  string `xml:"PmtInfId"`
+		PmtMtd    string `xml:"PmtMtd"`
+		BtchBookg bool   `xml:"BtchBookg"`
+		NbOfTxs   int    `xml:"NbOfTxs"`
+		CtrlSum   string `xml:"CtrlSum"`
+		PmtTpInf  struct {
+			SvcLvlCd string `xml:"SvcLvl>Cd"`
+		} `xml:"PmtTpInf"`
+		ReqdExctnDt string `xml:"ReqdExctnDt"`
+		Dbtr        struct {
+			Nm string `xml:"Nm"`
+		} `xml:"Dbtr"`
+		DbtrAcct struct {
+			IBAN string `xml:"Id>IBAN"`
+		} `xml:"DbtrAcct"`
+		DbtrAgt     pain001Agent      `xml:"DbtrAgt"`
+		ChrgBr      string            `xml:"ChrgBr"`
+		CdtTrfTxInf []pain001Transfer `xml:"CdtTrfTxInf"`
+	} `xml:"CstmrCdtTrfInitn>PmtInf"`
+}
+
+type pain001Agent struct {
+	FinInstnId struct {
+		BIC  string `xml:"BIC,omitempty"`
+		Othr *struct {
+			Id string `xml:"Id"`
+		} `xml:"Othr,omitempty"`
+	} `xml:"FinInstnId"`
+}
+
+type pain001Transfer struct {
+	EndToEndId string `xml:"PmtId>EndToEndId"`
+	Amt        struct {
+		Ccy   string `xml:"Ccy,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"Amt>InstdAmt"`
+	CdtrAgt *pain001Agent `xml:"CdtrAgt,omitempty"`
+	Cdtr    struct {
+		Nm string `xml:"Nm"`
+	} `xml:"Cdtr"`
+	CdtrAcct struct {
+		IBAN string `xml:"Id>IBAN"`
+	} `xml:"CdtrAcct"`
+	Ustrd string `xml:"RmtInf>Ustrd"`
+}
+
+// sepaCurrency is the only currency a SEPA credit transfer can carry.
+const sepaCurrency = "EUR"
+
+// agent identifies a bank by BIC, or as NOTPROVIDED when the BIC is unknown
+// (allowed for SEPA transfers identified by IBAN).
+func agent(bic string) pain001Agent {
+	var a pain001Agent
+	if bic == "" {
+		a.FinInstnId.Othr = &struct {
+			Id string `xml:"Id"`
+		}{Id: "NOTPROVIDED"}
+		return a
+	}
+	a.FinInstnId.BIC = bic
+	return a
+}
+
+// truncate shortens s to at most n characters, as ISO 20022 text fields are
+// length limited.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) > n {
+		return string(runes[:n])
+	}
+	return s
+}
+
+// WritePain001 writes the batches as a pain.001.001.03 SEPA credit transfer
+// file from the configured company account. Every batch must be open, paid
+// out in euros and its payee must have a bank account; batches in other
+// currencies are exported as CSV instead.
+func (s *PayoutService) WritePain001(ctx context.Context, w io.Writer, batches []models.PayoutBatch, now time.Time) error {
+	settings, err := s.GetSettings(ctx)
+	if err != nil {
+		return err
+	}
+	if settings.DebtorName == "" || settings.DebtorIBAN == "" {
+		return errors.New("payout debtor account must be configured before exporting pain.001")
+	}
+
+	var doc pain001Document
+	var total models.Money
+	for _, b := range batches {
+		if b.Status != models.PayoutOpen {
+			return fmt.Errorf("payout batch %d must be open to be exported for payment", b.ID)
+		}
+		if b.Currency != sepaCurrency {
+			return fmt.Errorf("payout batch %d is in %s; pain.001 SEPA transfers must be in %s", b.ID, b.Currency, sepaCurrency)
+		}
+		if b.BankAccount == nil {
+			return fmt.Errorf("bank account is required for %s (payout batch %d)", b.UserName, b.ID)
+		}
+		tx := pain001Transfer{
+			EndToEndId: b.PaymentReference,
+			Ustrd:      truncate("Expense reimbursement "+b.PaymentReference, 140),
+		}
+		tx.Amt.Ccy = b.Currency
+		tx.Amt.Value = b.TotalAmount.String()
+		if b.BankAccount.BIC != "" {
+			a := agent(b.BankAccount.BIC)
+			tx.CdtrAgt = &a
+		}
+		tx.Cdtr.Nm = truncate(b.BankAccount.AccountHolder, 70)
+		tx.CdtrAcct.IBAN = b.BankAccount.IBAN
+		doc.PmtInf.CdtTrfTxInf = append(doc.PmtInf.CdtTrfTxInf, tx)
+		total += b.TotalAmount
+	}
+
+	msgID := "PAYOUT-" + now.Format("20060102150405")
+	doc.GrpHdr.MsgId = msgID
+	doc.GrpHdr.CreDtTm = now.Format("2006-01-02T15:04:05")
+	doc.GrpHdr.NbOfTxs = len(batches)
+	doc.GrpHdr.CtrlSum = total.String()
+	doc.GrpHdr.InitgPty.Nm = truncate(settings.DebtorName, 70)
+
+	doc.PmtInf.PmtInfId = msgID + "-1"
+	doc.PmtInf.PmtMtd = "TRF"
+	doc.PmtInf.BtchBookg = true
+	doc.PmtInf.NbOfTxs = len(batches)
+	doc.PmtInf.CtrlSum = total.String()
+	doc.PmtInf.PmtTpInf.SvcLvlCd = "SEPA"
+	doc.PmtInf.ReqdExctnDt = now.Format("2006-01-02")
+	doc.PmtInf.Dbtr.Nm = truncate(settings.DebtorName, 70)
+	doc.PmtInf.DbtrAcct.IBAN = settings.DebtorIBAN
+	doc.PmtInf.DbtrAgt = agent(settings.DebtorBIC)
+	doc.PmtInf.ChrgBr = "SLEV"
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	return encoder.Encode(doc)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	"expense-tracker/internal/models"
+)
+
+func testPayoutBatches() []models.PayoutBatch {
+	return []models.PayoutBatch{
+		{
+			ID: 1, UserName: "Alice", Status: models.PayoutOpen, TotalAmount: 12345, ExpenseCount: 3, Currency: "EUR",
+			PaymentReference: "EXP-1",
+			BankAccount:      &models.BankAccount{AccountHolder: "Alice Example", IBAN: "DE89370400440532013000", BIC: "COBADEFFXXX"},
+		},
+		{
+			ID: 2, UserName: "Bob", Status: models.PayoutOpen, TotalAmount: 500, ExpenseCount: 1, Currency: "EUR",
+			PaymentReference: "EXP-2",
+			BankAccount:      &models.BankAccount{AccountHolder: "Bob Example", IBAN: "GB82WEST12345698765432"},
+		},
+	}
+}
+
+func TestPayoutServiceWriteCSV(t *testing.T) {
+	batches := testPayoutBatches()
+	batches = append(batches, models.PayoutBatch{ID: 3, UserName: "Carol", Status: models.PayoutPaid, TotalAmount: 100, Currency: "EUR"})
+
+	var out strings.Builder
+	svc := NewPayoutService(nil, fakeSettings{}, nil, nil)
+	if err := svc.WriteCSV(&out, batches); err != nil {
+		t.Fatalf("WriteCSV returned error: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+
+	want := [][]string{
+		{"batch_id", "status", "user", "account_holder", "iban", "bic", "amount", "currency", "expense_count", "payment_reference"},
+		{"1", "open", "Alice", "Alice Example", "DE89370400440532013000", "COBADEFFXXX", "123.45", "EUR", "3", "EXP-1"},
+		{"2", "open", "Bob", "Bob Example", "GB82WEST12345698765432", "", "5.00", "EUR", "1", "EXP-2"},
+		{"3", "paid", "Carol", "", "", "", "1.00", "EUR", "0", ""},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("got %d records, want %d", len(records), len(want))
+	}
+	for i := range want {
+		if strings.Join(records[i], "|") != strings.Join(want[i], "|") {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestPayoutServiceWritePain001(t *testing.T) {
+	settings := fakeSettings{
+		payoutDebtorNameSetting: "Example GmbH",
+		payoutDebtorIBANSetting: "DE89370400440532013000",
+	}
+	svc := NewPayoutService(nil, settings, nil, nil)
+	now := time.Date(2026, 3, 31, 14, 5, 0, 0, time.UTC)
+
+	var out strings.Builder
+	if err := svc.WritePain001(context.Background(), &out, testPayoutBatches(), now); err != nil {
+		t.Fatalf("WritePain001 returned error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), xml.Header) {
+		t.Error("output does not start with the XML header")
+	}
+
+	var doc pain001Document
+	if err := xml.Unmarshal([]byte(out.String()), &doc); err != nil {
+		t.Fatalf("output is not a valid pain.001 document: %v", err)
+	}
+	if doc.GrpHdr.MsgId != "PAYOUT-20260331140500" || doc.GrpHdr.CreDtTm != "2026-03-31T14:05:00" {
+		t.Errorf("group header = %q at %q", doc.GrpHdr.MsgId, doc.GrpHdr.CreDtTm)
+	}
+	if doc.GrpHdr.NbOfTxs != 2 || doc.GrpHdr.CtrlSum != "128.45" {
+		t.Errorf("group header counts %d transactions summing %s, want 2 summing 128.45", doc.GrpHdr.NbOfTxs, doc.GrpHdr.CtrlSum)
+	}
+	if doc.PmtInf.CtrlSum != "128.45" || doc.PmtInf.ReqdExctnDt != "2026-03-31" {
+		t.Errorf("payment info sums %s on %s", doc.PmtInf.CtrlSum, doc.PmtInf.ReqdExctnDt)
+	}
+	if doc.PmtInf.DbtrAcct.IBAN != "DE89370400440532013000" || doc.PmtInf.DbtrAgt.FinInstnId.Othr == nil {
+		t.Error("debtor without BIC must be identified by IBAN with agent NOTPROVIDED")
+	}
+
+	txs := doc.PmtInf.CdtTrfTxInf
+	if len(txs) != 2 {
+		t.Fatalf("got %d transfers, want 2", len(txs))
+	}
+	if txs[0].EndToEndId != "EXP-1" || txs[0].Amt.Value != "123.45" || txs[0].Amt.Ccy != "EUR" {
+		t.Errorf("first transfer = %s %s %s", txs[0].EndToEndId, txs[0].Amt.Value, txs[0].Amt.Ccy)
+	}
+	if txs[0].CdtrAgt == nil || txs[0].CdtrAgt.FinInstnId.BIC != "COBADEFFXXX" {
+		t.Error("first transfer must name the creditor BIC")
+	}
+	if txs[1].CdtrAgt != nil {
+		t.Error("transfer without BIC must omit the creditor agent")
+	}
+	if txs[1].Cdtr.Nm != "Bob Example" || txs[1].CdtrAcct.IBAN != "GB82WEST12345698765432" {
+		t.Errorf("second transfer pays %s at %s", txs[1].Cdtr.Nm, txs[1].CdtrAcct.IBAN)
+	}
+}
+
+func TestPayoutServiceWritePain001Errors(t *testing.T) {
+	configured := fakeSettings{
+		payoutDebtorNameSetting: "Example GmbH",
+		payoutDebtorIBANSetting: "DE89370400440532013000",
+	}
+	paid := testPayoutBatches()
+	paid[1].Status = models.PayoutPaid
+	noAccount := testPayoutBatches()
+	noAccount[0].BankAccount = nil
+	mixed := testPayoutBatches()
+	mixed[1].Currency = "GBP"
+
+	tests := []struct {
+		name     string
+		settings fakeSettings
+		batches  []models.PayoutBatch
+		wantErr  string
+	}{
+		{"debtor not configured", fakeSettings{}, testPayoutBatches(), "debtor account must be configured"},
+		{"paid batch", configured, paid, "payout batch 2 must be open"},
+		{"missing bank account", configured, noAccount, "bank account is required for Alice"},
+		{"non-euro batch", configured, mixed, "payout batch 2 is in GBP"},
+	}
+	for _, tt := range tests {
+		svc := NewPayoutService(nil, tt.settings, nil, nil)
+		var out strings.Builder
+		err := svc.WritePain001(context.Background(), &out, tt.batches, time.Now())
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("%s: error = %v, want %q", tt.name, err, tt.wantErr)
+		}
+	}
+}
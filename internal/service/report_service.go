@@ -0,0 +1,219 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"expense-tracker/internal/models"
+)
+
+type ReportBudgetRepository interface {
+	GetMonitoringData(ctx context.Context, year int) ([]models.BudgetMonitoringItem, error)
+}
+
+type ReportExpenseRepository interface {
+	GetMonthlyTotals(ctx context.Context, year int) (map[int][]models.Money, error)
+	GetAll(ctx context.Context, filter models.ExpenseFilter) ([]models.Expense, error)
+}
+
+type ReportService struct {
+	budgetRepo  ReportBudgetRepository
+	expenseRepo ReportExpenseRepository
+	departments DepartmentScope
+	fiscal      models.FiscalCalendar
+}
+
+func NewReportService(budgetRepo ReportBudgetRepository, expenseRepo ReportExpenseRepository, departments DepartmentScope, fiscal models.FiscalCalendar) *ReportService {
+	return &ReportService{
+		budgetRepo:  budgetRepo,
+		expenseRepo: expenseRepo,
+		departments: departments,
+		fiscal:      fiscal,
+	}
+}
+
+func (s *ReportService) FiscalCalendar() models.FiscalCalendar {
+	return s.fiscal
+}
+
+// VarianceReport compares each budgeted category's phased monthly budget with
+// actual spend for every month of the fiscal year.
+func (s *ReportService) VarianceReport(ctx context.Context, year int) (*models.VarianceReport, error) {
+	if year <= 0 {
+		return nil, errors.New("year must be greater than 0")
+	}
+
+	items, err := s.budgetRepo.GetMonitoringData(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+	monthly, err := s.expenseRepo.GetMonthlyTotals(ctx, year)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.VarianceReport{
+		Year:       year,
+		Label:      s.fiscal.Label(year),
+		Categories: make([]models.CategoryVariance, 0, len(items)),
+	}
+	totalBudgeted := make([]models.Money, 12)
+	totalActual := make([]models.Money, 12)
+
+	for _, item := range items {
+		budget := models.Budget{Amount: item.BudgetAmount, Year: year, Phasing: item.Phasing}
+		budgeted := budget.MonthlyAllocations()
+		actual := monthly[item.CategoryID]
+		if actual == nil {
+			actual = make([]models.Money, 12)
+		}
+
+		// Subcategory figures are already included in their top-level row.
+		if item.Depth == 0 {
+			for m := 0; m < 12; m++ {
+				totalBudgeted[m] += budgeted[m]
+				totalActual[m] += actual[m]
+			}
+		}
+
+		report.Categories = append(report.Categories, models.CategoryVariance{
+			BudgetID:         item.BudgetID,
+			CategoryID:       item.CategoryID,
+			CategoryName:     item.CategoryName,
+			ParentCategoryID: item.ParentCategoryID,
+			Depth:            item.Depth,
+			RollUp:           item.RollUp,
+			AnnualBudget:     item.BudgetAmount,
+			Months:           s.varianceLines(year, budgeted, actual),
+		})
+	}
+	report.Totals = s.varianceLines(year, totalBudgeted, totalActual)
+
+	return report, nil
+}
+
+func (s *ReportService) varianceLines(year int, budgeted, actual []models.Money) []models.VarianceLine {
+	lines := make([]models.VarianceLine, 12)
+	var ytdBudgeted, ytdActual models.Money
+	for m := 0; m < 12; m++ {
+		ytdBudgeted += budgeted[m]
+		ytdActual += actual[m]
+
+		start := s.fiscal.MonthStart(year, m+1)
+		variance := budgeted[m] - actual[m]
+		ytdVariance := ytdBudgeted - ytdActual
+		lines[m] = models.VarianceLine{
+			Month:              m + 1,
+			Label:              start.Format("Jan 2006"),
+			Start:              start,
+			End:                start.AddDate(0, 1, -1),
+			Budgeted:           budgeted[m],
+			Actual:             actual[m],
+			Variance:           variance,
+			VariancePercent:    variance.PercentOf(budgeted[m]),
+			YTDBudgeted:        ytdBudgeted,
+			YTDActual:          ytdActual,
+			YTDVariance:        ytdVariance,
+			YTDVariancePercent: ytdVariance.PercentOf(ytdBudgeted),
+		}
+	}
+	return lines
+}
+
+// VarianceExpenses lists the expenses behind one category and fiscal month
+// of the variance report, limited to the departments the user may see.
+func (s *ReportService) VarianceExpenses(ctx context.Context, categoryID, year, month int, user *models.User) (*models.VarianceDrillDown, error) {
+	if categoryID <= 0 {
+		return nil, errors.New("category ID must be greater than 0")
+	}
+	if year <= 0 {
+		return nil, errors.New("year must be greater than 0")
+	}
+	if month < 1 || month > 12 {
+		return nil, errors.New("month must be between 1 and 12")
+	}
+
+	visible, err := s.departments.VisibleDepartments(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	start := s.fiscal.MonthStart(year, month)
+	end := start.AddDate(0, 1, -1)
+	expenses, err := s.expenseRepo.GetAll(ctx, models.ExpenseFilter{
+		StartDate:          start.Format("2006-01-02"),
+		EndDate:            end.Format("2006-01-02"),
+		CategoryID:         categoryID,
+		VisibleDepartments: visible,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	drill := &models.VarianceDrillDown{
+		CategoryID: categoryID,
+		Year:       year,
+		Month:      month,
+		Label:      start.Format("Jan 2006"),
+		Start:      start,
+		End:        end,
+		Expenses:   []models.Expense{},
+	}
+	for _, e := range expenses {
+		// Rejected expenses do not count towards the actuals.
+		if e.ApprovalStatus == models.ApprovalRejected {
+			continue
+		}
+		drill.Expenses = append(drill.Expenses, e)
+		drill.Total += e.Amount
+	}
+	return drill, nil
+}
+
+func (s *ReportService) WriteVarianceCSV(w io.Writer, report *models.VarianceReport) error {
+	writer := csv.NewWriter(w)
+	header := []string{"category", "month", "budgeted", "actual", "variance", "variance_percent", "ytd_budgeted", "ytd_actual", "ytd_variance", "ytd_variance_percent"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	write := func(name string, lines []models.VarianceLine) error {
+		for _, l := range lines {
+			record := []string{
+				name,
+				l.Start.Format("2006-01"),
+				l.Budgeted.String(),
+				l.Actual.String(),
+				l.Variance.String(),
+				strconv.FormatFloat(l.VariancePercent, 'f', 2, 64),
+				l.YTDBudgeted.String(),
+				l.YTDActual.String(),
+				l.YTDVariance.String(),
+				strconv.FormatFloat(l.YTDVariancePercent, 'f', 2, 64),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, c := range report.Categories {
+		if err := write(c.CategoryName, c.Months); err != nil {
+			return err
+		}
+	}
+	if err := write("Total", report.Totals); err != nil {
+		return err
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("writing variance CSV: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,434 @@
+package service
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"time"
+
+	"expense-tracker/internal/models"
+)
+
+// csvStatementColumns maps accepted header names to the column they fill.
+var csvStatementColumns = map[string]string{
+	"date":             "date",
+	"booking_date":     "date",
+	"booking date":     "date",
+	"transaction_date": "date",
+	"transaction date": "date",
+	"posted":           "date",
+	"amount":           "amount",
+	"debit":            "debit",
+	"credit":           "credit",
+	"currency":         "currency",
+	"counterparty":     "counterparty",
+	"payee":            "counterparty",
+	"merchant":         "counterparty",
+	"name":             "counterparty",
+	"description":      "description",
+	"memo":             "description",
+	"details":          "description",
+	"purpose":          "description",
+	"reference":        "reference",
+	"transaction_id":   "reference",
+	"transaction id":   "reference",
+	"id":               "reference",
+}
+
+var statementDateLayouts = []string{"2006-01-02", "02.01.2006", "2006/01/02", "20060102"}
+
+// parseStatementCSV reads a statement with a header row naming the columns
+// (see csvStatementColumns). Amounts are signed, negative for money out, or
+// split into debit and credit columns. Comma and semicolon delimiters are
+// accepted; currency defaults to defaultCurrency.
+func parseStatementCSV(r io.Reader, defaultCurrency string) (*models.ParsedStatement, []string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, nil, errors.New("statement CSV must start with a header row")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		if column, ok := csvStatementColumns[strings.ToLower(strings.TrimSpace(name))]; ok {
+			if _, seen := columns[column]; !seen {
+				columns[column] = i
+			}
+		}
+	}
+	_, hasAmount := columns["amount"]
+	_, hasDebit := columns["debit"]
+	if _, ok := columns["date"]; !ok || (!hasAmount && !hasDebit) {
+		return nil, nil, errors.New("statement CSV must have a date column and an amount or debit column")
+	}
+
+	statement := &models.ParsedStatement{}
+	var lineErrors []string
+	line := 1
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		line++
+		if err != nil {
+			return nil, nil, fmt.Errorf("line %d: %v", line, err)
+		}
+		field := func(column string) string {
+			if i, ok := columns[column]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+		if strings.TrimSpace(strings.Join(record, "")) == "" {
+			continue
+		}
+
+		date, err := parseStatementDate(field("date"))
+		if err != nil {
+			lineErrors = append(lineErrors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		var amount models.Money
+		if hasAmount && field("amount") != "" {
+			amount, err = parseStatementAmount(field("amount"))
+		} else if debit := field("debit"); debit != "" {
+			amount, err = parseStatementAmount(debit)
+			amount = -absAmount(amount)
+		} else {
+			amount, err = parseStatementAmount(field("credit"))
+		}
+		if err != nil {
+			lineErrors = append(lineErrors, fmt.Sprintf("line %d: %v", line, err))
+			continue
+		}
+		currency := defaultCurrency
+		if c := field("currency"); c != "" {
+			if currency, err = models.NormalizeCurrency(c); err != nil {
+				lineErrors = append(lineErrors, fmt.Sprintf("line %d: %v", line, err))
+				continue
+			}
+		}
+
+		statement.Lines = append(statement.Lines, models.ParsedStatementLine{
+			BookingDate:  date,
+			Amount:       amount,
+			Currency:     currency,
+			Counterparty: field("counterparty"),
+			Description:  field("description"),
+			Reference:    field("reference"),
+		})
+	}
+	return statement, lineErrors, nil
+}
+
+func parseStatementDate(s string) (time.Time, error) {
+	for _, layout := range statementDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date: %q", s)
+}
+
+// parseStatementAmount accepts "1234.56", "1,234.56" and "1.234,56", with an
+// optional sign.
+func parseStatementAmount(s string) (models.Money, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), " ", "")
+	dot, comma := strings.LastIndex(s, "."), strings.LastIndex(s, ",")
+	switch {
+	case comma > dot:
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.Replace(s, ",", ".", 1)
+	case comma >= 0:
+		s = strings.ReplaceAll(s, ",", "")
+	}
+	return models.ParseMoney(s)
+}
+
+func absAmount(m models.Money) models.Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+var (
+	ofxTag         = regexp.MustCompile(`(?i)<([A-Z0-9.]+)>([^<\r\n]*)`)
+	ofxTransaction = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+)
+
+// parseOFX reads the transactions of an OFX 1.x (SGML) or 2.x (XML) bank or
+// credit card statement.
+func parseOFX(r io.Reader) (*models.ParsedStatement, []string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	text := string(data)
+	if !strings.Contains(strings.ToUpper(text), "<OFX>") {
+		return nil, nil, errors.New("invalid OFX: missing <OFX> element")
+	}
+
+	transactions := ofxTransaction.FindAllStringSubmatchIndex(text, -1)
+	header := text
+	if len(transactions) > 0 {
+		header = text[:transactions[0][0]]
+	}
+	statement := &models.ParsedStatement{}
+	currency := ""
+	for _, m := range ofxTag.FindAllStringSubmatch(header, -1) {
+		switch strings.ToUpper(m[1]) {
+		case "CURDEF":
+			currency = strings.TrimSpace(m[2])
+		case "ACCTID":
+			statement.AccountReference = strings.TrimSpace(m[2])
+		}
+	}
+	if currency, err = models.NormalizeCurrency(currency); err != nil {
+		return nil, nil, fmt.Errorf("invalid OFX: %v", err)
+	}
+
+	var lineErrors []string
+	for n, loc := range transactions {
+		fields := map[string]string{}
+		for _, m := range ofxTag.FindAllStringSubmatch(text[loc[2]:loc[3]], -1) {
+			fields[strings.ToUpper(m[1])] = strings.TrimSpace(m[2])
+		}
+
+		posted := fields["DTPOSTED"]
+		if len(posted) > 8 {
+			posted = posted[:8]
+		}
+		date, err := time.Parse("20060102", posted)
+		if err != nil {
+			lineErrors = append(lineErrors, fmt.Sprintf("transaction %d: invalid DTPOSTED %q", n+1, fields["DTPOSTED"]))
+			continue
+		}
+		amount, err := parseStatementAmount(fields["TRNAMT"])
+		if err != nil {
+			lineErrors = append(lineErrors, fmt.Sprintf("transaction %d: %v", n+1, err))
+			continue
+		}
+		reference := fields["FITID"]
+		if reference == "" {
+			reference = fields["CHECKNUM"]
+		}
+		statement.Lines = append(statement.Lines, models.ParsedStatementLine{
+			BookingDate:  date,
+			Amount:       amount,
+			Currency:     currency,
+			Counterparty: unescapeOFX(fields["NAME"]),
+			Description:  unescapeOFX(fields["MEMO"]),
+			Reference:    reference,
+		})
+	}
+	return statement, lineErrors, nil
+}
+
+func unescapeOFX(s string) string {
+	return strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">").Replace(s)
+}
+
+type camtAmount struct {
+	Value    string `xml:",chardata"`
+	Currency string `xml:"Ccy,attr"`
+}
+
+type camtDate struct {
+	Date     string `xml:"Dt"`
+	DateTime string `xml:"DtTm"`
+}
+
+type camtParty struct {
+	Name      string `xml:"Nm"`
+	PartyName string `xml:"Pty>Nm"`
+}
+
+type camtEntry struct {
+	Amount      camtAmount `xml:"Amt"`
+	CreditDebit string     `xml:"CdtDbtInd"`
+	Status      struct {
+		Value string `xml:",chardata"`
+		Code  string `xml:"Cd"`
+	} `xml:"Sts"`
+	BookingDate camtDate `xml:"BookgDt"`
+	ValueDate   camtDate `xml:"ValDt"`
+	Reference   string   `xml:"AcctSvcrRef"`
+	Info        string   `xml:"AddtlNtryInf"`
+	Details     []struct {
+		Amount      camtAmount `xml:"Amt"`
+		TxAmount    camtAmount `xml:"AmtDtls>TxAmt>Amt"`
+		CreditDebit string     `xml:"CdtDbtInd"`
+		EndToEndID  string     `xml:"Refs>EndToEndId"`
+		Reference   string     `xml:"Refs>AcctSvcrRef"`
+		Creditor    camtParty  `xml:"RltdPties>Cdtr"`
+		Debtor      camtParty  `xml:"RltdPties>Dbtr"`
+		Remittance  []string   `xml:"RmtInf>Ustrd"`
+	} `xml:"NtryDtls>TxDtls"`
+}
+
+type camtDocument struct {
+	Statements []struct {
+		Account struct {
+			IBAN     string `xml:"Id>IBAN"`
+			Other    string `xml:"Id>Othr>Id"`
+			Currency string `xml:"Ccy"`
+		} `xml:"Acct"`
+		Entries []camtEntry `xml:"Ntry"`
+	} `xml:"BkToCstmrStmt>Stmt"`
+}
+
+// parseCAMT053 reads an ISO 20022 camt.053 bank-to-customer statement. Booked
+// entries become lines; a batch entry whose transactions carry their own
+// amounts is split into one line per transaction.
+func parseCAMT053(r io.Reader) (*models.ParsedStatement, []string, error) {
+	var doc camtDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, nil, fmt.Errorf("invalid CAMT.053 XML: %v", err)
+	}
+	if len(doc.Statements) == 0 {
+		return nil, nil, errors.New("invalid CAMT.053 XML: no statement found")
+	}
+
+	statement := &models.ParsedStatement{}
+	var lineErrors []string
+	for _, stmt := range doc.Statements {
+		if statement.AccountReference == "" {
+			statement.AccountReference = stmt.Account.IBAN
+			if statement.AccountReference == "" {
+				statement.AccountReference = stmt.Account.Other
+			}
+		}
+		for n, entry := range stmt.Entries {
+			status := strings.TrimSpace(entry.Status.Value)
+			if entry.Status.Code != "" {
+				status = entry.Status.Code
+			}
+			if status != "" && status != "BOOK" {
+				continue
+			}
+			date := entry.BookingDate
+			if date.Date == "" && date.DateTime == "" {
+				date = entry.ValueDate
+			}
+			bookingDate, err := camtParseDate(date)
+			if err != nil {
+				lineErrors = append(lineErrors, fmt.Sprintf("entry %d: %v", n+1, err))
+				continue
+			}
+
+			split := len(entry.Details) > 1
+			for _, tx := range entry.Details {
+				if tx.Amount.Value == "" && tx.TxAmount.Value == "" {
+					split = false
+				}
+			}
+			if !split {
+				line, err := camtLine(entry.Amount, entry.CreditDebit, bookingDate, stmt.Account.Currency)
+				if err != nil {
+					lineErrors = append(lineErrors, fmt.Sprintf("entry %d: %v", n+1, err))
+					continue
+				}
+				line.Reference = entry.Reference
+				line.Description = entry.Info
+				if len(entry.Details) == 1 {
+					camtDescribe(&line, entry.Details[0].Creditor, entry.Details[0].Debtor, entry.Details[0].Remittance)
+					if line.Reference == "" {
+						line.Reference = entry.Details[0].EndToEndID
+					}
+				}
+				statement.Lines = append(statement.Lines, line)
+				continue
+			}
+
+			for i, tx := range entry.Details {
+				amount := tx.Amount
+				if amount.Value == "" {
+					amount = tx.TxAmount
+				}
+				indicator := tx.CreditDebit
+				if indicator == "" {
+					indicator = entry.CreditDebit
+				}
+				line, err := camtLine(amount, indicator, bookingDate, stmt.Account.Currency)
+				if err != nil {
+					lineErrors = append(lineErrors, fmt.Sprintf("entry %d transaction %d: %v", n+1, i+1, err))
+					continue
+				}
+				line.Reference = tx.Reference
+				if line.Reference == "" {
+					line.Reference = tx.EndToEndID
+				}
+				if line.Reference == "" && entry.Reference != "" {
+					line.Reference = fmt.Sprintf("%s/%d", entry.Reference, i+1)
+				}
+				line.Description = entry.Info
+				camtDescribe(&line, tx.Creditor, tx.Debtor, tx.Remittance)
+				statement.Lines = append(statement.Lines, line)
+			}
+		}
+	}
+	return statement, lineErrors, nil
+}
+
+func camtParseDate(d camtDate) (time.Time, error) {
+	if d.Date != "" {
+		return time.Parse("2006-01-02", strings.TrimSpace(d.Date))
+	}
+	if len(d.DateTime) >= 10 {
+		return time.Parse("2006-01-02", d.DateTime[:10])
+	}
+	return time.Time{}, errors.New("missing booking date")
+}
+
+func camtLine(amt camtAmount, indicator string, date time.Time, accountCurrency string) (models.ParsedStatementLine, error) {
+	amount, err := models.ParseMoney(amt.Value)
+	if err != nil {
+		return models.ParsedStatementLine{}, err
+	}
+	currency := amt.Currency
+	if currency == "" {
+		currency = accountCurrency
+	}
+	if currency, err = models.NormalizeCurrency(currency); err != nil {
+		return models.ParsedStatementLine{}, err
+	}
+	if indicator == "DBIT" {
+		amount = -amount
+	}
+	return models.ParsedStatementLine{BookingDate: date, Amount: amount, Currency: currency}, nil
+}
+
+// camtDescribe sets the counterparty (the creditor of a payment, the debtor
+// of a refund) and prefers the remittance information as description.
+func camtDescribe(line *models.ParsedStatementLine, creditor, debtor camtParty, remittance []string) {
+	party := creditor
+	if line.Amount > 0 {
+		party = debtor
+	}
+	line.Counterparty = party.Name
+	if line.Counterparty == "" {
+		line.Counterparty = party.PartyName
+	}
+	if text := strings.TrimSpace(strings.Join(remittance, " ")); text != "" {
+		line.Description = text
+	}
+}
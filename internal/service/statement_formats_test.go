@@ -0,0 +1,224 @@
+package service
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"expense-tracker/internal/models"
+)
+
+func assertStatementLines(t *testing.T, got, want []models.ParsedStatementLine) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d: %+v", len(got), len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestParseStatementAmount(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    models.Money
+		wantErr bool
+	}{
+		{in: "1234.56", want: 123456},
+		{in: "-1234.56", want: -123456},
+		{in: "1,234.56", want: 123456},
+		{in: "1.234,56", want: 123456},
+		{in: "-12,50", want: -1250},
+		{in: "1 234,56", want: 123456},
+		{in: "", wantErr: true},
+		{in: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseStatementAmount(tt.in)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("parseStatementAmount(%q) error = %v, wantErr %v", tt.in, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseStatementAmount(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParseStatementCSV(t *testing.T) {
+	tests := []struct {
+		name       string
+		in         string
+		want       []models.ParsedStatementLine
+		lineErrors int
+		wantErr    bool
+	}{
+		{
+			name: "signed amounts",
+			in: "\xef\xbb\xbfDate,Amount,Currency,Payee,Memo,Transaction ID\n" +
+				"2026-03-02,-42.10,usd,Cafe,Lunch,T1\n" +
+				"2026-03-03,100.00,,Client,Refund,T2\n",
+			want: []models.ParsedStatementLine{
+				{BookingDate: utcDay(2026, 3, 2), Amount: -4210, Currency: "USD", Counterparty: "Cafe", Description: "Lunch", Reference: "T1"},
+				{BookingDate: utcDay(2026, 3, 3), Amount: 10000, Currency: "EUR", Counterparty: "Client", Description: "Refund", Reference: "T2"},
+			},
+		},
+		{
+			name: "semicolons with debit and credit columns",
+			in: "Booking Date;Debit;Credit;Name\n" +
+				"02.03.2026;1.234,50;;Hotel\n" +
+				"03.03.2026;;20,00;Shop\n" +
+				"\n" +
+				"not a date;5,00;;Taxi\n",
+			want: []models.ParsedStatementLine{
+				{BookingDate: utcDay(2026, 3, 2), Amount: -123450, Currency: "EUR", Counterparty: "Hotel"},
+				{BookingDate: utcDay(2026, 3, 3), Amount: 2000, Currency: "EUR", Counterparty: "Shop"},
+			},
+			lineErrors: 1,
+		},
+		{
+			name:    "missing amount column",
+			in:      "date,payee\n2026-03-02,Cafe\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty file",
+			in:      "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statement, lineErrors, err := parseStatementCSV(strings.NewReader(tt.in), "EUR")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(lineErrors) != tt.lineErrors {
+				t.Errorf("got line errors %q, want %d", lineErrors, tt.lineErrors)
+			}
+			assertStatementLines(t, statement.Lines, tt.want)
+		})
+	}
+}
+
+func TestParseOFX(t *testing.T) {
+	sgml := `OFXHEADER:100
+DATA:OFXSGML
+
+<OFX>
+<BANKMSGSRSV1><STMTTRNRS><STMTRS>
+<CURDEF>usd
+<BANKACCTFROM><ACCTID>123456789</BANKACCTFROM>
+<BANKTRANLIST>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20260302120000[-5:EST]
+<TRNAMT>-42.10
+<FITID>F1
+<NAME>Smith &amp; Sons
+<MEMO>Office supplies
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>CHECK
+<DTPOSTED>20260303
+<TRNAMT>-15.00
+<CHECKNUM>1001
+</STMTTRN>
+<STMTTRN>
+<DTPOSTED>garbage
+<TRNAMT>-1.00
+</STMTTRN>
+</BANKTRANLIST>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+	statement, lineErrors, err := parseOFX(strings.NewReader(sgml))
+	if err != nil {
+		t.Fatalf("parseOFX returned error: %v", err)
+	}
+	if statement.AccountReference != "123456789" {
+		t.Errorf("account reference = %q, want 123456789", statement.AccountReference)
+	}
+	if len(lineErrors) != 1 {
+		t.Errorf("got line errors %q, want 1", lineErrors)
+	}
+	assertStatementLines(t, statement.Lines, []models.ParsedStatementLine{
+		{BookingDate: utcDay(2026, 3, 2), Amount: -4210, Currency: "USD", Counterparty: "Smith & Sons", Description: "Office supplies", Reference: "F1"},
+		{BookingDate: utcDay(2026, 3, 3), Amount: -1500, Currency: "USD", Reference: "1001"},
+	})
+
+	for _, in := range []string{"not an ofx file", "<OFX><STMTTRN><DTPOSTED>20260302</STMTTRN></OFX>"} {
+		if _, _, err := parseOFX(strings.NewReader(in)); err == nil {
+			t.Errorf("parseOFX(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func TestParseCAMT053(t *testing.T) {
+	doc := `<?xml version="1.0" encoding="UTF-8"?>
+<Document xmlns="urn:iso:std:iso:20022:tech:xsd:camt.053.001.02">
+<BkToCstmrStmt><Stmt>
+  <Acct><Id><IBAN>DE89370400440532013000</IBAN></Id><Ccy>EUR</Ccy></Acct>
+  <Ntry>
+    <Amt Ccy="EUR">42.10</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>BOOK</Sts>
+    <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+    <AcctSvcrRef>R1</AcctSvcrRef>
+    <NtryDtls><TxDtls>
+      <RltdPties><Cdtr><Nm>Cafe</Nm></Cdtr></RltdPties>
+      <RmtInf><Ustrd>Lunch</Ustrd><Ustrd>team</Ustrd></RmtInf>
+    </TxDtls></NtryDtls>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="EUR">1.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts>PDNG</Sts>
+    <BookgDt><Dt>2026-03-02</Dt></BookgDt>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="EUR">30.00</Amt><CdtDbtInd>DBIT</CdtDbtInd><Sts><Cd>BOOK</Cd></Sts>
+    <ValDt><DtTm>2026-03-04T10:00:00</DtTm></ValDt>
+    <AcctSvcrRef>B1</AcctSvcrRef>
+    <AddtlNtryInf>Batch</AddtlNtryInf>
+    <NtryDtls>
+      <TxDtls><Amt Ccy="EUR">10.00</Amt><RltdPties><Cdtr><Pty><Nm>Taxi</Nm></Pty></Cdtr></RltdPties></TxDtls>
+      <TxDtls><AmtDtls><TxAmt><Amt Ccy="EUR">20.00</Amt></TxAmt></AmtDtls><Refs><EndToEndId>E2</EndToEndId></Refs></TxDtls>
+    </NtryDtls>
+  </Ntry>
+  <Ntry>
+    <Amt Ccy="EUR">5.00</Amt><CdtDbtInd>CRDT</CdtDbtInd><Sts>BOOK</Sts>
+  </Ntry>
+</Stmt></BkToCstmrStmt>
+</Document>`
+
+	statement, lineErrors, err := parseCAMT053(strings.NewReader(doc))
+	if err != nil {
+		t.Fatalf("parseCAMT053 returned error: %v", err)
+	}
+	if statement.AccountReference != "DE89370400440532013000" {
+		t.Errorf("account reference = %q", statement.AccountReference)
+	}
+	if len(lineErrors) != 1 {
+		t.Errorf("got line errors %q, want 1 for the entry without date", lineErrors)
+	}
+	assertStatementLines(t, statement.Lines, []models.ParsedStatementLine{
+		{BookingDate: utcDay(2026, 3, 2), Amount: -4210, Currency: "EUR", Counterparty: "Cafe", Description: "Lunch team", Reference: "R1"},
+		{BookingDate: utcDay(2026, 3, 4), Amount: -1000, Currency: "EUR", Counterparty: "Taxi", Description: "Batch", Reference: "B1/1"},
+		{BookingDate: utcDay(2026, 3, 4), Amount: -2000, Currency: "EUR", Description: "Batch", Reference: "E2"},
+	})
+
+	for _, in := range []string{"not xml", `<Document></Document>`} {
+		if _, _, err := parseCAMT053(strings.NewReader(in)); err == nil {
+			t.Errorf("parseCAMT053(%q) succeeded, want error", in)
+		}
+	}
+}
+
+func utcDay(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
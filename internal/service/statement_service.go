@@ -0,0 +1,278 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"expense-tracker/internal/logging"
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+// matchWindowDays is how far a statement line's booking date may be from the
+// expense date; card payments are often booked a few days later.
+const matchWindowDays = 3
+
+// ExpenseCreator creates expenses for statement lines that have none;
+// implemented by ExpenseService.
+type ExpenseCreator interface {
+	Create(ctx context.Context, req models.ExpenseRequest, user *models.User) (*models.Expense, error)
+}
+
+// StatementService imports bank and card statements and reconciles their
+// lines with expenses, automatically where the match is unambiguous.
+type StatementService struct {
+	repo     repository.StatementRepository
+	expenses ExpenseCreator
+	currency CurrencyConverter
+}
+
+func NewStatementService(repo repository.StatementRepository, expenses ExpenseCreator, currency CurrencyConverter) *StatementService {
+	return &StatementService{
+		repo:     repo,
+		expenses: expenses,
+		currency: currency,
+	}
+}
+
+// Import parses a statement file, stores its outgoing payments that were not
+// imported before and auto-matches them.
+func (s *StatementService) Import(ctx context.Context, format models.StatementFormat, filename string, r io.Reader, user *models.User) (*models.StatementImportResult, error) {
+	var parsed *models.ParsedStatement
+	var lineErrors []string
+	var err error
+	switch format {
+	case models.StatementCSV:
+		base, baseErr := s.currency.BaseCurrency(ctx)
+		if baseErr != nil {
+			return nil, baseErr
+		}
+		parsed, lineErrors, err = parseStatementCSV(r, base)
+	case models.StatementOFX:
+		parsed, lineErrors, err = parseOFX(r)
+	case models.StatementCAMT053:
+		parsed, lineErrors, err = parseCAMT053(r)
+	default:
+		return nil, errors.New("format must be csv, ofx or camt053")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(parsed.Lines) == 0 && len(lineErrors) == 0 {
+		return nil, errors.New("statement contains no transactions")
+	}
+
+	result := &models.StatementImportResult{Errors: lineErrors}
+	var payments []models.ParsedStatementLine
+	seen := map[string]int{}
+	for _, line := range parsed.Lines {
+		if line.Amount >= 0 {
+			result.Credits++
+			continue
+		}
+		line.Amount = -line.Amount
+		key := strings.Join([]string{parsed.AccountReference, line.BookingDate.Format("2006-01-02"), line.Amount.String(), line.Currency,
+			line.Counterparty, line.Description, line.Reference}, "\x1f")
+		// Identical payments on the same day stay distinct by their position.
+		seen[key]++
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x1f%d", key, seen[key])))
+		line.Fingerprint = hex.EncodeToString(sum[:])
+		payments = append(payments, line)
+	}
+
+	if len(payments) == 0 {
+		return result, nil
+	}
+	statement := models.BankStatement{
+		Filename:         filename,
+		Format:           format,
+		AccountReference: parsed.AccountReference,
+		ImportedBy:       &user.ID,
+	}
+	stored, lines, err := s.repo.Create(ctx, statement, payments)
+	if err != nil {
+		return nil, err
+	}
+	result.Imported = len(lines)
+	result.Duplicates = len(payments) - len(lines)
+	if stored == nil {
+		return result, nil
+	}
+	result.Statement = stored
+
+	for _, line := range lines {
+		matched, err := s.autoMatch(ctx, line, user)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to auto-match statement line", "line_id", line.ID, "error", err)
+			continue
+		}
+		if matched {
+			result.AutoMatched++
+		}
+	}
+	stored.MatchedCount = result.AutoMatched
+
+	logging.FromContext(ctx).Info("bank statement imported", "statement_id", stored.ID, "format", format,
+		"imported", result.Imported, "duplicates", result.Duplicates, "auto_matched", result.AutoMatched, "user_id", user.ID)
+	return result, nil
+}
+
+// autoMatch links the line to its best candidate when that choice is
+// unambiguous: the only candidate, or the only best-scoring one whose
+// vendor appears on the line.
+func (s *StatementService) autoMatch(ctx context.Context, line models.StatementLine, user *models.User) (bool, error) {
+	candidates, err := s.candidates(ctx, line)
+	if err != nil || len(candidates) == 0 {
+		return false, err
+	}
+	best := candidates[0]
+	if len(candidates) > 1 && (!best.VendorMatched || candidates[1].Score == best.Score) {
+		return false, nil
+	}
+	if err := s.repo.Match(ctx, line.ID, best.ExpenseID, user.ID, true); err != nil {
+		if errors.Is(err, repository.ErrExpenseReconciled) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// candidates scores the possible expenses for a line, best first: points for
+// each day closer to the booking date, and more when the expense's vendor
+// name or an alias appears in the line's counterparty or description.
+func (s *StatementService) candidates(ctx context.Context, line models.StatementLine) ([]models.MatchCandidate, error) {
+	candidates, err := s.repo.FindCandidates(ctx, line, matchWindowDays)
+	if err != nil {
+		return nil, err
+	}
+	text := " " + models.NormalizeVendorName(line.Counterparty+" "+line.Description) + " "
+	for i := range candidates {
+		c := &candidates[i]
+		days := int(line.BookingDate.Sub(c.ExpenseDate).Hours() / 24)
+		if days < 0 {
+			days = -days
+		}
+		c.Score = 100 - 10*days
+		for _, name := range c.VendorNames {
+			if name != "" && strings.Contains(text, " "+name+" ") {
+				c.VendorMatched = true
+				c.Score += 50
+				break
+			}
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+	return candidates, nil
+}
+
+func (s *StatementService) GetAll(ctx context.Context) ([]models.BankStatement, error) {
+	return s.repo.GetAll(ctx)
+}
+
+func (s *StatementService) Delete(ctx context.Context, id int) error {
+	if id <= 0 {
+		return errors.New("statement ID must be greater than 0")
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// GetLines lists statement lines; unmatched lines come with their match
+// candidates for the review queue.
+func (s *StatementService) GetLines(ctx context.Context, filter models.StatementLineFilter) ([]models.StatementLine, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	lines, err := s.repo.GetLines(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range lines {
+		if lines[i].Status != models.LineUnmatched {
+			continue
+		}
+		if lines[i].Candidates, err = s.candidates(ctx, lines[i]); err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// Match reconciles a line with an expense chosen in the review queue. Amount
+// and date are not checked so fees and exchange differences can be accepted.
+func (s *StatementService) Match(ctx context.Context, lineID int, req models.StatementMatchRequest, user *models.User) (*models.StatementLine, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Match(ctx, lineID, req.ExpenseID, user.ID, false); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("statement line matched", "line_id", lineID, "expense_id", req.ExpenseID, "user_id", user.ID)
+	return s.repo.GetLine(ctx, lineID)
+}
+
+func (s *StatementService) Ignore(ctx context.Context, lineID int, user *models.User) (*models.StatementLine, error) {
+	if err := s.repo.SetIgnored(ctx, lineID, user.ID); err != nil {
+		return nil, err
+	}
+	return s.repo.GetLine(ctx, lineID)
+}
+
+// Reset puts a matched or ignored line back into the review queue.
+func (s *StatementService) Reset(ctx context.Context, lineID int, user *models.User) (*models.StatementLine, error) {
+	if err := s.repo.Reset(ctx, lineID); err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("statement line reset", "line_id", lineID, "user_id", user.ID)
+	return s.repo.GetLine(ctx, lineID)
+}
+
+// CreateExpense enters an expense for an unmatched line through
+// ExpenseService.Create, so budgets, approval and vendor resolution apply,
+// and matches the line to it.
+func (s *StatementService) CreateExpense(ctx context.Context, lineID int, req models.StatementExpenseRequest, user *models.User) (*models.Expense, error) {
+	line, err := s.repo.GetLine(ctx, lineID)
+	if err != nil {
+		return nil, err
+	}
+	if line.Status == models.LineMatched {
+		return nil, errors.New("statement line is already matched")
+	}
+
+	remarks := strings.TrimSpace(req.Remarks)
+	if remarks == "" {
+		remarks = line.Description
+	}
+	vendor := strings.TrimSpace(req.Vendor)
+	if vendor == "" {
+		vendor = line.Counterparty
+	}
+	expense, err := s.expenses.Create(ctx, models.ExpenseRequest{
+		CategoryID:    req.CategoryID,
+		Amount:        line.Amount,
+		ExpenseDate:   line.BookingDate.Format("2006-01-02"),
+		Remarks:       remarks,
+		Currency:      line.Currency,
+		Tags:          req.Tags,
+		CostCenterID:  req.CostCenterID,
+		Vendor:        vendor,
+		PaymentMethod: req.PaymentMethod,
+	}, user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Match(ctx, lineID, expense.ID, user.ID, false); err != nil {
+		logging.FromContext(ctx).Error("failed to match statement line to created expense", "line_id", lineID, "expense_id", expense.ID, "error", err)
+		return nil, fmt.Errorf("expense %d was created but could not be matched: %v", expense.ID, err)
+	}
+	expense.ReconciliationStatus = models.Reconciled
+	logging.FromContext(ctx).Info("expense created from statement line", "line_id", lineID, "expense_id", expense.ID, "user_id", user.ID)
+	return expense, nil
+}
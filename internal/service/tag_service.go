@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+const defaultTagSuggestions = 10
+
+type TagService struct {
+	repo repository.TagRepository
+}
+
+func NewTagService(repo repository.TagRepository) *TagService {
+	return &TagService{repo: repo}
+}
+
+// Autocomplete returns existing tags matching what the user has typed so far.
+func (s *TagService) Autocomplete(ctx context.Context, prefix string, limit int) ([]models.Tag, error) {
+	if limit <= 0 || limit > 50 {
+		limit = defaultTagSuggestions
+	}
+	return s.repo.Search(ctx, strings.ToLower(strings.Join(strings.Fields(prefix), " ")), limit)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"strconv"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+// TaxService manages the VAT / sales tax rates expenses can be entered with
+// and reports input tax per period.
+type TaxService struct {
+	repo     repository.TaxRateRepository
+	currency CurrencyConverter
+}
+
+func NewTaxService(repo repository.TaxRateRepository, currency CurrencyConverter) *TaxService {
+	return &TaxService{repo: repo, currency: currency}
+}
+
+func (s *TaxService) GetAll(ctx context.Context, activeOnly bool) ([]models.TaxRate, error) {
+	return s.repo.GetAll(ctx, activeOnly)
+}
+
+func (s *TaxService) GetByID(ctx context.Context, id int) (*models.TaxRate, error) {
+	if id <= 0 {
+		return nil, errors.New("tax rate ID must be greater than 0")
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *TaxService) Create(ctx context.Context, req models.TaxRateRequest) (*models.TaxRate, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Create(ctx, req)
+}
+
+func (s *TaxService) Update(ctx context.Context, id int, req models.TaxRateRequest) (*models.TaxRate, error) {
+	if id <= 0 {
+		return nil, errors.New("tax rate ID must be greater than 0")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	return s.repo.Update(ctx, id, req)
+}
+
+// VATReport sums approved expenses of the period by rate and category.
+func (s *TaxService) VATReport(ctx context.Context, req models.VATReportRequest) (*models.VATReport, error) {
+	start, end, err := req.Bounds()
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.currency.BaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := s.repo.GetVATSummary(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+	untaxedCount, untaxedAmount, err := s.repo.GetUntaxedTotal(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &models.VATReport{
+		StartDate:     start.Format("2006-01-02"),
+		EndDate:       end.Format("2006-01-02"),
+		Currency:      base,
+		Lines:         lines,
+		Rates:         []models.VATRateTotal{},
+		UntaxedCount:  untaxedCount,
+		UntaxedAmount: untaxedAmount,
+	}
+	// Lines are ordered by rate, so lines of the same rate are adjacent.
+	for _, l := range lines {
+		n := len(report.Rates)
+		if n == 0 || report.Rates[n-1].TaxRateID != l.TaxRateID || report.Rates[n-1].Rate != l.Rate {
+			report.Rates = append(report.Rates, models.VATRateTotal{TaxRateID: l.TaxRateID, TaxRateName: l.TaxRateName, Rate: l.Rate})
+			n++
+		}
+		total := &report.Rates[n-1]
+		total.ExpenseCount += l.ExpenseCount
+		total.NetAmount += l.NetAmount
+		total.TaxAmount += l.TaxAmount
+		total.GrossAmount += l.GrossAmount
+		total.RecoverableTax += l.RecoverableTax
+
+		report.NetAmount += l.NetAmount
+		report.TaxAmount += l.TaxAmount
+		report.GrossAmount += l.GrossAmount
+		report.RecoverableTax += l.RecoverableTax
+	}
+	return report, nil
+}
+
+func (s *TaxService) WriteVATCSV(w io.Writer, report *models.VATReport) error {
+	writer := csv.NewWriter(w)
+	header := []string{"tax_rate", "rate", "category", "expense_count", "net_amount", "tax_amount", "gross_amount", "recoverable_tax", "currency"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, l := range report.Lines {
+		record := []string{
+			l.TaxRateName,
+			strconv.FormatFloat(l.Rate, 'f', 2, 64),
+			l.CategoryName,
+			strconv.Itoa(l.ExpenseCount),
+			l.NetAmount.String(),
+			l.TaxAmount.String(),
+			l.GrossAmount.String(),
+			l.RecoverableTax.String(),
+			report.Currency,
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	total := []string{"Total", "", "", "", report.NetAmount.String(), report.TaxAmount.String(), report.GrossAmount.String(), report.RecoverableTax.String(), report.Currency}
+	if err := writer.Write(total); err != nil {
+		return err
+	}
+	writer.Flush()
+	return writer.Error()
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+// fakeTaxRates serves a fixed VAT summary; the other methods are not used.
+type fakeTaxRates struct {
+	repository.TaxRateRepository
+	lines         []models.VATReportLine
+	untaxedCount  int
+	untaxedAmount models.Money
+}
+
+func (f *fakeTaxRates) GetVATSummary(ctx context.Context, start, end time.Time) ([]models.VATReportLine, error) {
+	return f.lines, nil
+}
+
+func (f *fakeTaxRates) GetUntaxedTotal(ctx context.Context, start, end time.Time) (int, models.Money, error) {
+	return f.untaxedCount, f.untaxedAmount, nil
+}
+
+func TestTaxServiceVATReport(t *testing.T) {
+	repo := &fakeTaxRates{
+		lines: []models.VATReportLine{
+			{TaxRateID: 1, TaxRateName: "Standard", Rate: 19, CategoryName: "Travel", ExpenseCount: 2, NetAmount: 10000, TaxAmount: 1900, GrossAmount: 11900, RecoverableTax: 1900},
+			{TaxRateID: 1, TaxRateName: "Standard", Rate: 19, CategoryName: "Office", ExpenseCount: 1, NetAmount: 5000, TaxAmount: 950, GrossAmount: 5950, RecoverableTax: 950},
+			{TaxRateID: 1, TaxRateName: "Standard", Rate: 16, CategoryName: "Office", ExpenseCount: 1, NetAmount: 1000, TaxAmount: 160, GrossAmount: 1160, RecoverableTax: 160},
+			{TaxRateID: 2, TaxRateName: "Meals", Rate: 7, CategoryName: "Food", ExpenseCount: 3, NetAmount: 3000, TaxAmount: 210, GrossAmount: 3210},
+		},
+		untaxedCount:  2,
+		untaxedAmount: 4200,
+	}
+	svc := NewTaxService(repo, NewCurrencyService(fakeRates{}, fakeSettings{baseCurrencySetting: "EUR"}))
+
+	report, err := svc.VATReport(context.Background(), models.VATReportRequest{Year: 2026, Quarter: 2})
+	if err != nil {
+		t.Fatalf("VATReport returned error: %v", err)
+	}
+	if report.StartDate != "2026-04-01" || report.EndDate != "2026-06-30" || report.Currency != "EUR" {
+		t.Errorf("report covers %s..%s in %s", report.StartDate, report.EndDate, report.Currency)
+	}
+
+	// A rate changed during the period is reported as a separate total.
+	wantRates := []models.VATRateTotal{
+		{TaxRateID: 1, TaxRateName: "Standard", Rate: 19, ExpenseCount: 3, NetAmount: 15000, TaxAmount: 2850, GrossAmount: 17850, RecoverableTax: 2850},
+		{TaxRateID: 1, TaxRateName: "Standard", Rate: 16, ExpenseCount: 1, NetAmount: 1000, TaxAmount: 160, GrossAmount: 1160, RecoverableTax: 160},
+		{TaxRateID: 2, TaxRateName: "Meals", Rate: 7, ExpenseCount: 3, NetAmount: 3000, TaxAmount: 210, GrossAmount: 3210},
+	}
+	if len(report.Rates) != len(wantRates) {
+		t.Fatalf("got %d rate totals, want %d: %+v", len(report.Rates), len(wantRates), report.Rates)
+	}
+	for i := range wantRates {
+		if report.Rates[i] != wantRates[i] {
+			t.Errorf("rate total %d = %+v, want %+v", i, report.Rates[i], wantRates[i])
+		}
+	}
+	if report.NetAmount != 19000 || report.TaxAmount != 3220 || report.GrossAmount != 22220 || report.RecoverableTax != 3010 {
+		t.Errorf("report totals = net %d tax %d gross %d recoverable %d", report.NetAmount, report.TaxAmount, report.GrossAmount, report.RecoverableTax)
+	}
+	if report.UntaxedCount != 2 || report.UntaxedAmount != 4200 {
+		t.Errorf("untaxed = %d expenses for %d, want 2 for 4200", report.UntaxedCount, report.UntaxedAmount)
+	}
+
+	var out strings.Builder
+	if err := svc.WriteVATCSV(&out, report); err != nil {
+		t.Fatalf("WriteVATCSV returned error: %v", err)
+	}
+	records, err := csv.NewReader(strings.NewReader(out.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != len(repo.lines)+2 {
+		t.Fatalf("got %d CSV records, want header, %d lines and total", len(records), len(repo.lines))
+	}
+	if got := strings.Join(records[1], ","); got != "Standard,19.00,Travel,2,100.00,19.00,119.00,19.00,EUR" {
+		t.Errorf("first line = %s", got)
+	}
+	if got := strings.Join(records[len(records)-1], ","); got != "Total,,,,190.00,32.20,222.20,30.10,EUR" {
+		t.Errorf("total line = %s", got)
+	}
+}
+
+func TestTaxServiceVATReportInvalidPeriod(t *testing.T) {
+	svc := NewTaxService(&fakeTaxRates{}, NewCurrencyService(fakeRates{}, fakeSettings{baseCurrencySetting: "EUR"}))
+	if _, err := svc.VATReport(context.Background(), models.VATReportRequest{Year: 2026, Quarter: 5}); err == nil {
+		t.Error("VATReport accepted quarter 5")
+	}
+}
@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"crypto/rand"
 	"encoding/hex"
 	"errors"
@@ -10,10 +11,10 @@ import (
 )
 
 type UserService interface {
-	CreateUser(name, displayID, email string, role models.UserRole) (*models.User, error)
-	GetAllUsers() ([]models.User, error)
-	GetUserByID(id int) (*models.User, error)
-	UpdateUserRole(userID int, role models.UserRole) error
+	CreateUser(ctx context.Context, name, displayID, email string, role models.UserRole) (*models.User, error)
+	GetAllUsers(ctx context.Context) ([]models.User, error)
+	GetUserByID(ctx context.Context, id int) (*models.User, error)
+	UpdateUserRole(ctx context.Context, userID int, role models.UserRole) error
 }
 
 type userService struct {
@@ -28,7 +29,7 @@ func NewUserService(userRepo repository.UserRepository, emailService EmailServic
 	}
 }
 
-func (s *userService) CreateUser(name, displayID, email string, role models.UserRole) (*models.User, error) {
+func (s *userService) CreateUser(ctx context.Context, name, displayID, email string, role models.UserRole) (*models.User, error) {
 	if name == "" || displayID == "" || email == "" {
 		return nil, errors.New("name, user ID, and email are required")
 	}
@@ -46,7 +47,7 @@ func (s *userService) CreateUser(name, displayID, email string, role models.User
 		PasswordSetTokenExpiry: &expiry,
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
+	if err := s.userRepo.Create(ctx, user); err != nil {
 		return nil, err
 	}
 
@@ -57,20 +58,20 @@ func (s *userService) CreateUser(name, displayID, email string, role models.User
 	return user, nil
 }
 
-func (s *userService) GetAllUsers() ([]models.User, error) {
-	return s.userRepo.GetAll()
+func (s *userService) GetAllUsers(ctx context.Context) ([]models.User, error) {
+	return s.userRepo.GetAll(ctx)
 }
 
-func (s *userService) GetUserByID(id int) (*models.User, error) {
-	return s.userRepo.GetByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id int) (*models.User, error) {
+	return s.userRepo.GetByID(ctx, id)
 }
 
-func (s *userService) UpdateUserRole(userID int, role models.UserRole) error {
+func (s *userService) UpdateUserRole(ctx context.Context, userID int, role models.UserRole) error {
 	if role != models.RoleAdmin && role != models.RoleManagement && role != models.RoleExecutive {
 		return errors.New("invalid role")
 	}
 
-	return s.userRepo.UpdateRole(userID, role)
+	return s.userRepo.UpdateRole(ctx, userID, role)
 }
 
 func generateRandomToken(n int) (string, error) {
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"expense-tracker/internal/logging"
+	"expense-tracker/internal/models"
+	"expense-tracker/internal/repository"
+)
+
+type VendorService struct {
+	repo repository.VendorRepository
+}
+
+func NewVendorService(repo repository.VendorRepository) *VendorService {
+	return &VendorService{repo: repo}
+}
+
+func (s *VendorService) GetAll(ctx context.Context, search string) ([]models.Vendor, error) {
+	return s.repo.GetAll(ctx, models.NormalizeVendorName(search))
+}
+
+func (s *VendorService) Create(ctx context.Context, req models.VendorRequest) (*models.Vendor, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	normalized := models.NormalizeVendorName(req.Name)
+	existing, err := s.repo.FindByNormalizedName(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		return nil, repository.ErrVendorExists
+	}
+	return s.repo.Create(ctx, req.Name, normalized)
+}
+
+func (s *VendorService) Update(ctx context.Context, id int, req models.VendorRequest) (*models.Vendor, error) {
+	if id <= 0 {
+		return nil, errors.New("vendor ID must be greater than 0")
+	}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	normalized := models.NormalizeVendorName(req.Name)
+	existing, err := s.repo.FindByNormalizedName(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil && existing.ID != id {
+		return nil, repository.ErrVendorExists
+	}
+	return s.repo.Update(ctx, id, req.Name, normalized)
+}
+
+// Resolve returns the ID of the vendor matching name by normalized name or
+// alias, creating the vendor on first use. An empty name resolves to nil.
+func (s *VendorService) Resolve(ctx context.Context, name string) (*int, error) {
+	if strings.TrimSpace(name) == "" {
+		return nil, nil
+	}
+	req := models.VendorRequest{Name: name}
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+	normalized := models.NormalizeVendorName(req.Name)
+	vendor, err := s.repo.FindByNormalizedName(ctx, normalized)
+	if err != nil {
+		return nil, err
+	}
+	if vendor == nil {
+		vendor, err = s.repo.Create(ctx, req.Name, normalized)
+		if errors.Is(err, repository.ErrVendorExists) {
+			// Created concurrently by another expense.
+			vendor, err = s.repo.FindByNormalizedName(ctx, normalized)
+		}
+		if err != nil {
+			return nil, err
+		}
+		logging.FromContext(ctx).Info("vendor created", "vendor_id", vendor.ID, "name", vendor.Name)
+	}
+	return &vendor.ID, nil
+}
+
+func (s *VendorService) AddAlias(ctx context.Context, vendorID int, req models.VendorAliasRequest) (*models.Vendor, error) {
+	if vendorID <= 0 {
+		return nil, errors.New("vendor ID must be greater than 0")
+	}
+	alias := models.NormalizeVendorName(req.Alias)
+	if alias == "" {
+		return nil, errors.New("alias is required")
+	}
+	if err := s.repo.AddAlias(ctx, vendorID, alias); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, vendorID)
+}
+
+func (s *VendorService) RemoveAlias(ctx context.Context, vendorID int, alias string) (*models.Vendor, error) {
+	if vendorID <= 0 {
+		return nil, errors.New("vendor ID must be greater than 0")
+	}
+	if err := s.repo.RemoveAlias(ctx, vendorID, models.NormalizeVendorName(alias)); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(ctx, vendorID)
+}
+
+// Merge folds sourceID into req.TargetID. Expenses move to the target and the
+// source name keeps resolving to it as an alias.
+func (s *VendorService) Merge(ctx context.Context, sourceID int, req models.VendorMergeRequest, user *models.User) (*models.VendorMerge, error) {
+	if sourceID <= 0 || req.TargetID <= 0 {
+		return nil, errors.New("source and target vendor IDs must be greater than 0")
+	}
+	if sourceID == req.TargetID {
+		return nil, errors.New("source and target vendor must be different")
+	}
+
+	merge, err := s.repo.Merge(ctx, sourceID, req.TargetID)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("vendor merged", "source_id", sourceID, "target_id", req.TargetID,
+		"expenses", merge.ExpensesMoved, "aliases", merge.AliasesMoved, "user_id", user.ID)
+	return merge, nil
+}
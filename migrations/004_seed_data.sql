@@ -1,14 +1,20 @@
--- Seed initial categories (matching application defaults for consistency)
-INSERT INTO categories (name, is_active) VALUES 
-('Food', true),
-('Transport', true),
-('Rent', true),
-('Utilities', true),
-('Marketing', true),
-('Salary', true),
-('Office Rent', true),
-('HR Development', true),
-('Entertainment', true)
+-- Seed initial categories (matching application defaults for consistency).
+-- Only into an empty table: migrations are replayed on every start and must
+-- not bring back defaults that were merged or deleted since.
+INSERT INTO categories (name, is_active)
+SELECT v.name, true
+FROM (VALUES
+    ('Food'),
+    ('Transport'),
+    ('Rent'),
+    ('Utilities'),
+    ('Marketing'),
+    ('Salary'),
+    ('Office Rent'),
+    ('HR Development'),
+    ('Entertainment')
+) AS v(name)
+WHERE NOT EXISTS (SELECT 1 FROM categories)
 ON CONFLICT (name) DO NOTHING;
 
 -- Seed some annual budgets for 2026
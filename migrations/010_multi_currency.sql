@@ -0,0 +1,33 @@
+-- Company-wide settings (base currency and similar options)
+CREATE TABLE IF NOT EXISTS app_settings (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO app_settings (key, value) VALUES ('base_currency', 'USD')
+ON CONFLICT (key) DO NOTHING;
+
+-- Date-effective exchange rates: 1 unit of base_currency = rate units of quote_currency
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id SERIAL PRIMARY KEY,
+    base_currency CHAR(3) NOT NULL,
+    quote_currency CHAR(3) NOT NULL,
+    rate DECIMAL(18, 8) NOT NULL CHECK (rate > 0),
+    effective_date DATE NOT NULL,
+    source VARCHAR(20) NOT NULL DEFAULT 'manual',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(base_currency, quote_currency, effective_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_exchange_rates_lookup ON exchange_rates(base_currency, quote_currency, effective_date DESC);
+
+-- Expenses keep the amount in base currency; the original entry is preserved alongside it
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS currency CHAR(3) NOT NULL DEFAULT 'USD';
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS original_amount DECIMAL(12, 2);
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS exchange_rate DECIMAL(18, 8) NOT NULL DEFAULT 1;
+
+UPDATE expenses SET original_amount = amount WHERE original_amount IS NULL;
+
+COMMENT ON COLUMN expenses.amount IS 'Amount in company base currency, used for budgets and reporting';
+COMMENT ON COLUMN expenses.original_amount IS 'Amount as entered, in expenses.currency';
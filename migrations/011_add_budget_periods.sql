@@ -0,0 +1,15 @@
+-- Budget period granularity with optional month-by-month phasing of the annual amount
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS period VARCHAR(10) NOT NULL DEFAULT 'annual';
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS phasing DECIMAL(12, 2)[];
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS locked_at DATE;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'budgets_period_check') THEN
+        ALTER TABLE budgets ADD CONSTRAINT budgets_period_check CHECK (period IN ('annual', 'quarterly', 'monthly'));
+    END IF;
+END $$;
+
+COMMENT ON COLUMN budgets.period IS 'Granularity used for status, monitoring and lock checks: annual, quarterly or monthly';
+COMMENT ON COLUMN budgets.phasing IS 'Optional 12 monthly amounts summing to budgets.amount; even split when NULL';
+COMMENT ON COLUMN budgets.locked_at IS 'Date the circuit breaker was engaged; locks on periodic budgets expire with the period';
@@ -0,0 +1,13 @@
+-- Turn budget_entries into an allocation ledger: every change to a budget is recorded as a delta
+ALTER TABLE budget_entries ALTER COLUMN amount TYPE DECIMAL(12, 2);
+ALTER TABLE budget_entries ADD COLUMN IF NOT EXISTS entry_type VARCHAR(20) NOT NULL DEFAULT 'revision';
+ALTER TABLE budget_entries ADD COLUMN IF NOT EXISTS user_id INTEGER REFERENCES users(id);
+
+-- Budgets created before the ledger existed get an opening entry so history sums to the effective amount
+INSERT INTO budget_entries (budget_id, amount, description, date, entry_type)
+SELECT b.id, b.amount, 'Opening balance', b.created_at, 'initial'
+FROM budgets b
+WHERE NOT EXISTS (SELECT 1 FROM budget_entries be WHERE be.budget_id = b.id);
+
+COMMENT ON COLUMN budget_entries.amount IS 'Change in allocation (positive or negative); budgets.amount is the running total';
+COMMENT ON COLUMN budget_entries.entry_type IS 'initial, revision or supplementary';
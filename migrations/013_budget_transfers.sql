@@ -0,0 +1,15 @@
+-- Transfers move allocation between two categories' budgets in the same year
+CREATE TABLE IF NOT EXISTS budget_transfers (
+    id SERIAL PRIMARY KEY,
+    from_budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    to_budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    reason TEXT,
+    user_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK (from_budget_id <> to_budget_id)
+);
+
+ALTER TABLE budget_entries ADD COLUMN IF NOT EXISTS transfer_id INTEGER REFERENCES budget_transfers(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_budget_entries_transfer_id ON budget_entries(transfer_id);
@@ -0,0 +1,19 @@
+-- Per-budget circuit breaker policies
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS auto_lock_percent DECIMAL(5, 2) CHECK (auto_lock_percent > 0);
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS reject_over_budget BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS auto_locked BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS override_until DATE;
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS override_reason TEXT;
+
+-- Audit trail of every lock, unlock and override, manual or automatic
+CREATE TABLE IF NOT EXISTS budget_lock_events (
+    id SERIAL PRIMARY KEY,
+    budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    action VARCHAR(20) NOT NULL,
+    automatic BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT,
+    user_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_budget_lock_events_budget_id ON budget_lock_events(budget_id);
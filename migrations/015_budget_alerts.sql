@@ -0,0 +1,26 @@
+-- Spend thresholds (percent of the period budget) that notify management when crossed
+ALTER TABLE budgets ADD COLUMN IF NOT EXISTS alert_thresholds DECIMAL(5, 2)[] NOT NULL DEFAULT '{80,100}';
+
+-- One row per threshold crossed per budget period, so each alert fires once
+CREATE TABLE IF NOT EXISTS budget_alerts (
+    id SERIAL PRIMARY KEY,
+    budget_id INTEGER NOT NULL REFERENCES budgets(id) ON DELETE CASCADE,
+    threshold DECIMAL(5, 2) NOT NULL,
+    period_start DATE NOT NULL,
+    percent DECIMAL(7, 2) NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (budget_id, threshold, period_start)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    message TEXT NOT NULL,
+    budget_id INTEGER REFERENCES budgets(id) ON DELETE SET NULL,
+    read_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user_id ON notifications(user_id, created_at DESC);
@@ -0,0 +1,5 @@
+-- Month (1-12) in which the fiscal year starts; 1 means calendar years.
+-- Budgets' year column holds the fiscal year, labelled by the calendar year it starts in.
+-- Read at startup; FISCAL_YEAR_START_MONTH overrides and persists it.
+INSERT INTO app_settings (key, value) VALUES ('fiscal_year_start_month', '1')
+ON CONFLICT (key) DO NOTHING;
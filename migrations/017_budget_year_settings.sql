@@ -0,0 +1,6 @@
+-- Per fiscal year planning settings such as the savings target shown on the budget dashboard
+CREATE TABLE IF NOT EXISTS budget_year_settings (
+    year INTEGER PRIMARY KEY,
+    savings_target DECIMAL(12, 2) NOT NULL DEFAULT 0 CHECK (savings_target >= 0),
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
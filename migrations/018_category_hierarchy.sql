@@ -0,0 +1,12 @@
+-- Parent/child categories. Budgets may be set at any level; expenses are
+-- recorded at any level and their spend rolls up to every ancestor.
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS parent_id INTEGER REFERENCES categories(id) ON DELETE RESTRICT;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'categories_parent_not_self') THEN
+        ALTER TABLE categories ADD CONSTRAINT categories_parent_not_self CHECK (parent_id IS NULL OR parent_id <> id);
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_categories_parent_id ON categories(parent_id);
@@ -0,0 +1,29 @@
+-- Deleting a category must never take its expenses or budgets with it;
+-- used categories are merged into another one instead.
+-- Guarded so replaying the migration on boot does not rebuild the constraints
+-- and lock both tables every time.
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'budgets_category_id_fkey' AND confdeltype = 'r') THEN
+        ALTER TABLE budgets DROP CONSTRAINT IF EXISTS budgets_category_id_fkey;
+        ALTER TABLE budgets ADD CONSTRAINT budgets_category_id_fkey FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'expenses_category_id_fkey' AND confdeltype = 'r') THEN
+        ALTER TABLE expenses DROP CONSTRAINT IF EXISTS expenses_category_id_fkey;
+        ALTER TABLE expenses ADD CONSTRAINT expenses_category_id_fkey FOREIGN KEY (category_id) REFERENCES categories(id) ON DELETE RESTRICT;
+    END IF;
+END $$;
+
+-- Audit trail of merges; the source category no longer exists afterwards
+CREATE TABLE IF NOT EXISTS category_merges (
+    id SERIAL PRIMARY KEY,
+    source_category_id INTEGER NOT NULL,
+    source_name VARCHAR(255) NOT NULL,
+    target_category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE CASCADE,
+    expenses_moved INTEGER NOT NULL DEFAULT 0,
+    budgets_moved INTEGER NOT NULL DEFAULT 0,
+    budgets_combined INTEGER NOT NULL DEFAULT 0,
+    subcategories_moved INTEGER NOT NULL DEFAULT 0,
+    user_id INTEGER REFERENCES users(id),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
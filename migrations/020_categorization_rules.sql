@@ -0,0 +1,24 @@
+-- Admin-managed rules that suggest, or auto-assign, a category for new expenses
+CREATE TABLE IF NOT EXISTS categorization_rules (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    category_id INTEGER NOT NULL REFERENCES categories(id) ON DELETE RESTRICT,
+    priority INTEGER NOT NULL DEFAULT 100,
+    match_type VARCHAR(20) NOT NULL DEFAULT 'keyword' CHECK (match_type IN ('keyword', 'regex')),
+    remarks_pattern TEXT NOT NULL DEFAULT '',
+    min_amount DECIMAL(12, 2),
+    max_amount DECIMAL(12, 2),
+    user_id INTEGER REFERENCES users(id) ON DELETE CASCADE,
+    auto_assign BOOLEAN NOT NULL DEFAULT FALSE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_categorization_rules_priority ON categorization_rules(priority, id) WHERE is_active;
+
+-- What was suggested when the expense was created, to measure how often users override it
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS suggested_category_id INTEGER REFERENCES categories(id) ON DELETE SET NULL;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS suggestion_rule_id INTEGER REFERENCES categorization_rules(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_suggestion_rule_id ON expenses(suggestion_rule_id);
@@ -0,0 +1,28 @@
+-- Projects and cost centers: a second expense dimension, budgeted independently of categories
+CREATE TABLE IF NOT EXISTS cost_centers (
+    id SERIAL PRIMARY KEY,
+    code VARCHAR(20) NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS cost_center_id INTEGER REFERENCES cost_centers(id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS idx_expenses_cost_center_id ON expenses(cost_center_id);
+
+-- Annual cost center budgets with the same circuit breaker policy as category budgets
+CREATE TABLE IF NOT EXISTS cost_center_budgets (
+    id SERIAL PRIMARY KEY,
+    cost_center_id INTEGER NOT NULL REFERENCES cost_centers(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL DEFAULT 0.00,
+    is_locked BOOLEAN NOT NULL DEFAULT FALSE,
+    locked_at TIMESTAMP,
+    auto_lock_percent DECIMAL(5, 2) CHECK (auto_lock_percent > 0),
+    reject_over_budget BOOLEAN NOT NULL DEFAULT FALSE,
+    auto_locked BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(cost_center_id, year)
+);
@@ -0,0 +1,43 @@
+-- Departments with a manager; management users see and approve their departments' expenses
+CREATE TABLE IF NOT EXISTS departments (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    manager_id INTEGER REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_departments_manager_id ON departments(manager_id);
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS department_id INTEGER REFERENCES departments(id) ON DELETE SET NULL;
+
+-- The submitter's department at the time of entry, so moving a user does not move their history
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS department_id INTEGER REFERENCES departments(id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS idx_expenses_department_id ON expenses(department_id);
+
+-- Approval workflow: existing expenses count as approved, new ones wait for their manager
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS approval_status VARCHAR(20) NOT NULL DEFAULT 'approved';
+ALTER TABLE expenses ALTER COLUMN approval_status SET DEFAULT 'pending';
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS approved_by INTEGER REFERENCES users(id);
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS approved_at TIMESTAMP;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS approval_note TEXT;
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'expenses_approval_status_check') THEN
+        ALTER TABLE expenses ADD CONSTRAINT expenses_approval_status_check
+            CHECK (approval_status IN ('pending', 'approved', 'rejected'));
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_approval_status ON expenses(approval_status);
+
+CREATE TABLE IF NOT EXISTS department_budgets (
+    id SERIAL PRIMARY KEY,
+    department_id INTEGER NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    year INTEGER NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL DEFAULT 0.00,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE(department_id, year)
+);
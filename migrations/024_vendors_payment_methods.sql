@@ -0,0 +1,34 @@
+-- Suppliers, matched on a normalized name so "ACME Inc." and "acme inc" are
+-- the same vendor; aliases catch other spellings and merged duplicates.
+CREATE TABLE IF NOT EXISTS vendors (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    normalized_name VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS vendor_aliases (
+    id SERIAL PRIMARY KEY,
+    vendor_id INTEGER NOT NULL REFERENCES vendors(id) ON DELETE CASCADE,
+    alias VARCHAR(255) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vendor_aliases_vendor_id ON vendor_aliases(vendor_id);
+
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS vendor_id INTEGER REFERENCES vendors(id) ON DELETE RESTRICT;
+CREATE INDEX IF NOT EXISTS idx_expenses_vendor_id ON expenses(vendor_id);
+
+-- NULL for expenses recorded before payment methods were tracked
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS payment_method VARCHAR(20);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'expenses_payment_method_check') THEN
+        ALTER TABLE expenses ADD CONSTRAINT expenses_payment_method_check
+            CHECK (payment_method IN ('company_card', 'cash', 'personal', 'bank_transfer'));
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_payment_method ON expenses(payment_method);
@@ -0,0 +1,33 @@
+-- Where reimbursements are paid to: one account per user
+CREATE TABLE IF NOT EXISTS user_bank_accounts (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    account_holder VARCHAR(140) NOT NULL,
+    iban VARCHAR(34) NOT NULL,
+    bic VARCHAR(11),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Approved expenses paid personally (payment_method 'personal') are grouped
+-- into one payout batch per user, which is then paid as a single transfer.
+CREATE TABLE IF NOT EXISTS payout_batches (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',
+    total_amount DECIMAL(12, 2) NOT NULL DEFAULT 0.00,
+    expense_count INTEGER NOT NULL DEFAULT 0,
+    currency VARCHAR(3) NOT NULL,
+    created_by INTEGER REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    paid_by INTEGER REFERENCES users(id),
+    paid_at TIMESTAMP,
+    payment_reference VARCHAR(35),
+    CONSTRAINT payout_batches_status_check CHECK (status IN ('open', 'paid'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_payout_batches_user_id ON payout_batches(user_id);
+CREATE INDEX IF NOT EXISTS idx_payout_batches_status ON payout_batches(status);
+
+-- Cancelling an open batch releases its expenses for the next one
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS payout_batch_id INTEGER REFERENCES payout_batches(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_expenses_payout_batch_id ON expenses(payout_batch_id);
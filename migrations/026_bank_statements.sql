@@ -0,0 +1,35 @@
+-- Imported bank and card statements (CSV, OFX or CAMT.053)
+CREATE TABLE IF NOT EXISTS bank_statements (
+    id SERIAL PRIMARY KEY,
+    filename VARCHAR(255) NOT NULL DEFAULT '',
+    format VARCHAR(10) NOT NULL,
+    account_reference VARCHAR(100) NOT NULL DEFAULT '',
+    imported_by INTEGER REFERENCES users(id),
+    imported_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT bank_statements_format_check CHECK (format IN ('csv', 'ofx', 'camt053'))
+);
+
+-- One row per outgoing payment. A line is matched while expense_id is set;
+-- deleting the expense puts it back in the review queue. The fingerprint
+-- keeps overlapping statements from importing the same payment twice.
+CREATE TABLE IF NOT EXISTS bank_statement_lines (
+    id SERIAL PRIMARY KEY,
+    statement_id INTEGER NOT NULL REFERENCES bank_statements(id) ON DELETE CASCADE,
+    booking_date DATE NOT NULL,
+    amount DECIMAL(12, 2) NOT NULL CHECK (amount > 0),
+    currency CHAR(3) NOT NULL,
+    counterparty VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    reference VARCHAR(255) NOT NULL DEFAULT '',
+    fingerprint CHAR(64) NOT NULL UNIQUE,
+    expense_id INTEGER REFERENCES expenses(id) ON DELETE SET NULL,
+    auto_matched BOOLEAN NOT NULL DEFAULT FALSE,
+    ignored BOOLEAN NOT NULL DEFAULT FALSE,
+    matched_by INTEGER REFERENCES users(id),
+    matched_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_statement_id ON bank_statement_lines(statement_id);
+CREATE INDEX IF NOT EXISTS idx_bank_statement_lines_booking_date ON bank_statement_lines(booking_date);
+-- An expense is reconciled against at most one statement line
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_statement_lines_expense_id ON bank_statement_lines(expense_id) WHERE expense_id IS NOT NULL;
@@ -0,0 +1,29 @@
+-- VAT / sales tax rates that can be applied to expenses. Input tax on a
+-- non-recoverable rate (e.g. entertainment) is a cost and not reclaimed.
+CREATE TABLE IF NOT EXISTS tax_rates (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL UNIQUE,
+    rate DECIMAL(5, 2) NOT NULL CHECK (rate >= 0 AND rate < 100),
+    recoverable BOOLEAN NOT NULL DEFAULT TRUE,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- amount stays the gross amount in the base currency; the rate and its
+-- recoverability are copied so later changes to a rate keep history intact.
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS tax_rate_id INTEGER REFERENCES tax_rates(id) ON DELETE RESTRICT;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS tax_rate DECIMAL(5, 2);
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS tax_recoverable BOOLEAN;
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS net_amount DECIMAL(12, 2);
+ALTER TABLE expenses ADD COLUMN IF NOT EXISTS tax_amount DECIMAL(12, 2);
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'expenses_tax_split_check') THEN
+        ALTER TABLE expenses ADD CONSTRAINT expenses_tax_split_check
+            CHECK (tax_rate_id IS NULL OR (tax_rate IS NOT NULL AND tax_recoverable IS NOT NULL AND net_amount + tax_amount = amount));
+    END IF;
+END $$;
+
+CREATE INDEX IF NOT EXISTS idx_expenses_tax_rate_id ON expenses(tax_rate_id);
@@ -0,0 +1,13 @@
+-- Cost center budgets get the same period granularity and phasing as category budgets
+ALTER TABLE cost_center_budgets ADD COLUMN IF NOT EXISTS period VARCHAR(10) NOT NULL DEFAULT 'annual';
+ALTER TABLE cost_center_budgets ADD COLUMN IF NOT EXISTS phasing DECIMAL(12, 2)[];
+
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_constraint WHERE conname = 'cost_center_budgets_period_check') THEN
+        ALTER TABLE cost_center_budgets ADD CONSTRAINT cost_center_budgets_period_check CHECK (period IN ('annual', 'quarterly', 'monthly'));
+    END IF;
+END $$;
+
+COMMENT ON COLUMN cost_center_budgets.period IS 'Granularity used for monitoring and lock checks: annual, quarterly or monthly';
+COMMENT ON COLUMN cost_center_budgets.phasing IS 'Optional 12 monthly amounts summing to cost_center_budgets.amount; even split when NULL';
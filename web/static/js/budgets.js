@@ -9,12 +9,93 @@ function hideBudgetModal() {
     document.getElementById('budgetModal').style.display = 'none';
 }
 
+function showRollForwardModal() {
+    document.getElementById('rollForwardModal').style.display = 'block';
+    document.getElementById('rollForwardPreview').innerHTML = '';
+    document.getElementById('rollForwardCommit').disabled = true;
+    const toYear = document.getElementById('rollToYear');
+    if (!toYear.value) {
+        toYear.value = parseInt(document.getElementById('rollFromYear').value) + 1;
+    }
+}
+
+function hideRollForwardModal() {
+    document.getElementById('rollForwardModal').style.display = 'none';
+}
+
 // Close modal when clicking outside
 window.onclick = function(event) {
     const modal = document.getElementById('budgetModal');
     if (event.target === modal) {
         hideBudgetModal();
     }
+    if (event.target === document.getElementById('rollForwardModal')) {
+        hideRollForwardModal();
+    }
+}
+
+function rollForwardRequest(preview) {
+    const formData = new FormData(document.getElementById('rollForwardForm'));
+    return {
+        from_year: parseInt(formData.get('from_year')),
+        to_year: parseInt(formData.get('to_year')),
+        basis: formData.get('basis'),
+        uplift_percent: parseFloat(formData.get('uplift_percent')) || 0,
+        carry_over: formData.get('carry_over') === 'on',
+        overwrite: formData.get('overwrite') === 'on',
+        preview: preview
+    };
+}
+
+async function runRollForward(preview) {
+    const response = await fetch('/api/budgets/roll-forward', {
+        method: 'POST',
+        headers: { 'Content-Type': 'application/json' },
+        body: JSON.stringify(rollForwardRequest(preview))
+    });
+    const result = await response.json();
+    if (!response.ok) {
+        throw new Error(result.message || 'Roll-forward failed');
+    }
+    return result.data;
+}
+
+async function previewRollForward(event) {
+    event.preventDefault();
+    const container = document.getElementById('rollForwardPreview');
+    try {
+        const data = await runRollForward(true);
+        const rows = data.items.map(item => `
+            <tr>
+                <td>${escapeHtml(item.category_name)}</td>
+                <td class="text-right">${formatCurrency(item.source_amount)}</td>
+                <td class="text-right">${formatCurrency(item.source_spent)}</td>
+                <td class="text-right font-bold">${formatCurrency(item.proposed_amount)}</td>
+                <td>${item.action}${item.note ? ` <small>(${escapeHtml(item.note)})</small>` : ''}</td>
+            </tr>
+        `).join('');
+        container.innerHTML = `
+            <table class="data-table" style="margin-top: 1rem;">
+                <thead><tr><th>Category</th><th class="text-right">Budget</th><th class="text-right">Spent</th><th class="text-right">Proposed</th><th>Action</th></tr></thead>
+                <tbody>${rows || '<tr><td colspan="5" class="empty-state">No budgets to roll forward.</td></tr>'}</tbody>
+            </table>
+            <p class="text-secondary">Total proposed: ${formatCurrency(data.total_proposed)}</p>
+        `;
+        document.getElementById('rollForwardCommit').disabled = data.items.length === data.skipped;
+    } catch (error) {
+        toast.error(error.message);
+    }
+}
+
+async function commitRollForward() {
+    try {
+        const data = await runRollForward(false);
+        toast.success(`${data.created} created, ${data.updated} updated, ${data.skipped} skipped`);
+        hideRollForwardModal();
+        fetchBudgets();
+    } catch (error) {
+        toast.error(error.message);
+    }
 }
 
 // Save budget
@@ -28,7 +109,8 @@ async function saveBudget(event) {
     const data = {
         category_id: parseInt(formData.get('category_id')),
         year: parseInt(formData.get('year')),
-        amount: parseFloat(formData.get('amount'))
+        amount: parseFloat(formData.get('amount')),
+        period: formData.get('period') || 'annual'
     };
     
     try {
@@ -66,7 +148,7 @@ async function saveBudget(event) {
 
 // Fetch and display budgets
 async function fetchBudgets() {
-    const year = 2026; // Default for demo
+    const year = document.body.dataset.fiscalYear;
     try {
         const response = await fetch(`/api/budgets?year=${year}`);
         const result = await response.json();
@@ -85,9 +167,15 @@ function updateDashboard(summary) {
     
     // Update summary cards
     document.getElementById('totalAnnualBudget').textContent = formatCurrency(summary.total_annual_budget);
-    document.getElementById('highestAllocation').textContent = formatCurrency(summary.highest_allocation);
-    document.getElementById('savingsTarget').textContent = formatCurrency(summary.savings_target);
-    document.getElementById('remainingBudget').textContent = 'Remaining: ' + formatCurrency(summary.remaining_budget);
+    document.getElementById('consumedBar').style.width = Math.min(summary.percent_consumed, 100) + '%';
+    document.getElementById('percentConsumed').textContent = summary.percent_consumed.toFixed(2) + '% consumed';
+    document.getElementById('totalSpent').textContent = formatCurrency(summary.total_spent);
+    const overBudget = document.getElementById('overBudget');
+    overBudget.textContent = `${summary.categories_over_budget} categories over budget`;
+    overBudget.title = summary.over_budget_categories.join(', ');
+    document.getElementById('remainingBudget').textContent = formatCurrency(summary.remaining_budget);
+    document.getElementById('committedBudget').textContent = 'Committed: ' + formatCurrency(summary.committed);
+    document.getElementById('savingsTarget').textContent = 'Savings target: ' + formatCurrency(summary.savings_target);
 }
 
 
@@ -8,12 +8,13 @@ function showCreateModal() {
     document.getElementById('formMessage').style.display = 'none';
 }
 
-function showEditModal(id, name, isActive) {
+function showEditModal(id, name, isActive, parentId) {
     document.getElementById('modalTitle').textContent = 'Edit Category';
     document.getElementById('submitBtn').textContent = 'Save Changes';
     document.getElementById('categoryId').value = id;
     document.getElementById('categoryName').value = name;
     document.getElementById('isActive').checked = isActive;
+    document.getElementById('parentId').value = parentId || '';
     document.getElementById('createModal').style.display = 'block';
     document.getElementById('formMessage').style.display = 'none';
 }
@@ -47,9 +48,11 @@ async function handleCategorySubmit(event) {
     const formData = new FormData(form);
     const id = formData.get('id');
     
+    const parentId = formData.get('parent_id');
     const data = {
         name: formData.get('name'),
-        is_active: formData.get('is_active') === 'on'
+        is_active: formData.get('is_active') === 'on',
+        parent_id: parentId ? parseInt(parentId) : null
     };
     
     const url = id ? `/api/categories/${id}` : '/api/categories';
@@ -129,7 +132,7 @@ function displayCategories(categories) {
         const statusIcon = '<svg xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 256 256"><path fill="currentColor" d="M144 128a16 16 0 1 1-16-16a16 16 0 0 1 16 16m-64-16a16 16 0 1 0 16 16a16 16 0 0 0-16-16m128 0a16 16 0 1 0 16 16a16 16 0 0 0-16-16"/></svg>';
 
         return `
-            <tr class="${!category.is_active ? 'row-inactive' : ''}" data-id="${category.id}" data-active="${category.is_active}" style="${activeOnly && !category.is_active ? 'display: none;' : ''}">
+            <tr class="${!category.is_active ? 'row-inactive' : ''}" data-id="${category.id}" data-active="${category.is_active}" data-parent="${category.parent_id || ''}" style="${activeOnly && !category.is_active ? 'display: none;' : ''}">
                 <td>#${category.id}</td>
                 <td class="font-bold">${escapeHtml(category.name)}</td>
                 <td>
@@ -146,7 +149,9 @@ function displayCategories(categories) {
                                 title="${category.is_active ? 'Mark Inactive' : 'Mark Active'}">
                             ${statusIcon}
                         </button>
-                        <button class="btn-icon" onclick="showEditModal(${category.id}, '${category.name.replace(/'/g, "\\'")}', ${category.is_active})" aria-label="Edit Category" title="Edit Category">
+                        <button class="btn-icon" onclick="showMergeModal(${category.id}, '${category.name.replace(/'/g, "\\'")}')" aria-label="Merge Category" title="Merge into another category">&#8594;</button>
+                        <button class="btn-icon" onclick="deleteCategory(${category.id})" aria-label="Delete Category" title="Delete Category">&times;</button>
+                        <button class="btn-icon" onclick="showEditModal(${category.id}, '${category.name.replace(/'/g, "\\'")}', ${category.is_active}, ${category.parent_id || null})" aria-label="Edit Category" title="Edit Category">
                             <svg aria-hidden="true" xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 256 256"><path fill="currentColor" d="M227.31 73.37L182.63 28.7a16 16 0 0 0-22.63 0L36.69 152A15.86 15.86 0 0 0 32 163.31V208a16 16 0 0 0 16 16h44.69a15.86 15.86 0 0 0 11.31-4.69L227.31 96a16 16 0 0 0 0-22.63M92.69 208H48v-44.69l88-88L180.69 120ZM192 108.69L147.31 64l24-24L216 84.69Z"/></svg>
                         </button>
                     </div>
@@ -184,6 +189,61 @@ async function toggleStatus(id, currentStatus) {
     }
 }
 
+function showMergeModal(id, name) {
+    document.getElementById('mergeSourceId').value = id;
+    document.getElementById('mergeSourceName').textContent = name;
+    document.getElementById('mergeForm').reset();
+    document.getElementById('mergeModal').style.display = 'block';
+}
+
+function hideMergeModal() {
+    document.getElementById('mergeModal').style.display = 'none';
+}
+
+async function mergeCategory(event) {
+    event.preventDefault();
+    const id = document.getElementById('mergeSourceId').value;
+    const targetId = parseInt(document.getElementById('mergeTargetId').value);
+
+    try {
+        const response = await fetch(`/api/categories/${id}/merge`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ target_id: targetId })
+        });
+        const result = await response.json();
+        if (response.ok) {
+            const m = result.data;
+            toast.success(`Merged: ${m.expenses_moved} expenses, ${m.budgets_moved + m.budgets_combined} budgets moved`);
+            hideMergeModal();
+            window.location.reload();
+        } else {
+            toast.error(result.message || 'Failed to merge category');
+        }
+    } catch (error) {
+        console.error('Error merging category:', error);
+        toast.error('An error occurred. Please try again.');
+    }
+}
+
+async function deleteCategory(id) {
+    if (!confirm('Delete this category? Only unused categories can be deleted.')) {
+        return;
+    }
+
+    try {
+        const response = await fetch(`/api/categories/${id}`, { method: 'DELETE' });
+        const result = await response.json();
+        if (response.ok) {
+            fetchCategories();
+        } else {
+            toast.error(result.message || 'Failed to delete category');
+        }
+    } catch (error) {
+        console.error('Error deleting category:', error);
+        toast.error('An error occurred. Please try again.');
+    }
+}
 
 // Utility function to escape HTML
 function escapeHtml(text) {
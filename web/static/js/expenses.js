@@ -33,8 +33,21 @@ async function saveExpense(event) {
         amount: parseFloat(formData.get('amount')),
         expense_date: formData.get('date'),
         category_id: parseInt(formData.get('category_id')),
-        remarks: formData.get('remarks')
+        remarks: formData.get('remarks'),
+        tags: splitTags(formData.get('tags'))
     };
+    const currency = (formData.get('currency') || '').trim().toUpperCase();
+    if (currency) data.currency = currency;
+    const costCenterId = parseInt(formData.get('cost_center_id'));
+    if (costCenterId) data.cost_center_id = costCenterId;
+    const vendor = (formData.get('vendor') || '').trim();
+    if (vendor) data.vendor = vendor;
+    if (formData.get('payment_method')) data.payment_method = formData.get('payment_method');
+    const taxRateId = parseInt(formData.get('tax_rate_id'));
+    if (taxRateId) {
+        data.tax_rate_id = taxRateId;
+        data.tax_inclusive = formData.get('tax_inclusive') === 'on';
+    }
     
     try {
         const response = await fetch('/api/expenses', {
@@ -55,6 +68,7 @@ async function saveExpense(event) {
             setTimeout(() => {
                 hideExpenseModal();
                 fetchExpenses(); // Refresh the list
+                loadVendors(); // Pick up a vendor created by this expense
             }, 1500);
         } else {
             formMessage.className = 'form-message error';
@@ -80,6 +94,14 @@ async function fetchExpenses(filters = {}) {
     if (filters.categoryId) params.append('category_id', filters.categoryId);
     if (filters.minAmount) params.append('min_amount', filters.minAmount);
     if (filters.maxAmount) params.append('max_amount', filters.maxAmount);
+    if (filters.tags) {
+        params.append('tags', filters.tags);
+        params.append('tag_match', filters.tagMatch);
+    }
+    if (filters.status) params.append('status', filters.status);
+    if (filters.vendorId) params.append('vendor_id', filters.vendorId);
+    if (filters.paymentMethod) params.append('payment_method', filters.paymentMethod);
+    if (filters.reconciliation) params.append('reconciliation', filters.reconciliation);
     
     if (params.toString()) {
         url += '?' + params.toString();
@@ -122,12 +144,33 @@ function renderExpensesTable(expenses) {
         return `
             <tr>
                 <td>${date}</td>
-                <td class="font-bold">${escapeHtml(e.remarks)}</td>
-                <td><span class="category-tag">${escapeHtml(e.category_name)}</span></td>
-                <td><span class="text-secondary" style="font-size: 0.9rem;">${escapeHtml(e.user_name || 'System')}</span></td>
-                <td><span class="expense-amount">$${e.amount.toLocaleString(undefined, {minimumFractionDigits: 2})}</span></td>
+                <td class="font-bold">
+                    ${escapeHtml(e.remarks)}
+                    ${e.vendor_name ? `<span class="text-secondary" style="display: block; font-size: 0.8rem; font-weight: normal;">${escapeHtml(e.vendor_name)}</span>` : ''}
+                    ${(e.tags || []).map(t => `<span class="text-secondary" style="display: inline-block; margin: 0.25rem 0.25rem 0 0; font-size: 0.8rem; font-weight: normal;">#${escapeHtml(t)}</span>`).join('')}
+                </td>
+                <td>
+                    <span class="category-tag">${escapeHtml(e.category_name)}</span>
+                    ${e.cost_center_code ? `<span class="text-secondary" style="display: block; font-size: 0.8rem;">${escapeHtml(e.cost_center_code)}</span>` : ''}
+                </td>
+                <td>
+                    <span class="text-secondary" style="font-size: 0.9rem;">${escapeHtml(e.user_name || 'System')}</span>
+                    ${e.department_name ? `<span class="text-secondary" style="display: block; font-size: 0.8rem;">${escapeHtml(e.department_name)}</span>` : ''}
+                </td>
+                <td>
+                    <span class="expense-amount">$${e.amount.toLocaleString(undefined, {minimumFractionDigits: 2})}</span>
+                    ${e.payment_method ? `<span class="text-secondary" style="display: block; font-size: 0.8rem;">${paymentMethodLabels[e.payment_method] || escapeHtml(e.payment_method)}</span>` : ''}
+                    ${e.reconciliation_status === 'reconciled' ? '<span class="text-secondary" style="display: block; font-size: 0.8rem;">✓ Reconciled</span>' : ''}
+                    ${approvalBadge(e)}
+                    ${e.tax_amount !== undefined ? `<span class="text-secondary" style="display: block; font-size: 0.8rem;">Net ${e.net_amount.toLocaleString(undefined, {minimumFractionDigits: 2})} + ${escapeHtml(e.tax_rate_name)} ${e.tax_amount.toLocaleString(undefined, {minimumFractionDigits: 2})}</span>` : ''}
+                    ${e.original_amount !== e.amount ? `<span class="text-secondary" style="display: block; font-size: 0.8rem;">${escapeHtml(e.currency)} ${e.original_amount.toLocaleString(undefined, {minimumFractionDigits: 2})}</span>` : ''}
+                </td>
                 <td class="text-right">
                     <div class="action-group">
+                        ${canApprove && e.approval_status === 'pending' ? `
+                            <button class="btn btn-primary" onclick="decideExpense(${e.id}, 'approve')" style="padding: 0.3rem 0.75rem; font-size: 0.8rem;">Approve</button>
+                            <button class="btn btn-secondary" onclick="decideExpense(${e.id}, 'reject')" style="padding: 0.3rem 0.75rem; font-size: 0.8rem;">Reject</button>
+                        ` : ''}
                         <button class="btn-icon" onclick="deleteExpense(${e.id})" aria-label="Delete Expense" title="Delete Expense">
                             <svg aria-hidden="true" xmlns="http://www.w3.org/2000/svg" width="20" height="20" viewBox="0 0 256 256"><path fill="currentColor" d="M216 48h-40v-8a24 24 0 0 0-24-24h-48a24 24 0 0 0-24 24v8H40a8 8 0 0 0 0 16h8v144a16 16 0 0 0 16 16h128a16 16 0 0 0 16-16V64h8a8 8 0 0 0 0-16M96 40a8 8 0 0 1 8-8h48a8 8 0 0 1 8 8v8H96Zm96 168H64V64h128Zm-80-104v64a8 8 0 0 1-16 0v-64a8 8 0 0 1 16 0m48 0v64a8 8 0 0 1-16 0v-64a8 8 0 0 1 16 0"/></svg>
                         </button>
@@ -138,6 +181,52 @@ function renderExpensesTable(expenses) {
     }).join('');
 }
 
+const paymentMethodLabels = {
+    company_card: 'Company card',
+    cash: 'Cash',
+    personal: 'Personal (reimbursable)',
+    bank_transfer: 'Bank transfer'
+};
+
+function approvalBadge(e) {
+    if (!e.approval_status || e.approval_status === 'approved') return '';
+    const color = e.approval_status === 'rejected' ? 'var(--danger-color)' : 'var(--warning-color)';
+    const note = e.approval_note ? `: ${escapeHtml(e.approval_note)}` : '';
+    return `<span style="display: block; font-size: 0.8rem; color: ${color};">${e.approval_status}${note}</span>`;
+}
+
+// Approve or reject a pending expense
+async function decideExpense(id, action) {
+    let note = '';
+    if (action === 'reject') {
+        note = prompt('Reason for rejecting this expense:');
+        if (note === null) return;
+        if (!note.trim()) {
+            toast.error('A reason is required to reject an expense');
+            return;
+        }
+    }
+
+    try {
+        const response = await fetch(`/api/expenses/${id}/${action}`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ note: note.trim() })
+        });
+        const result = await response.json();
+
+        if (response.ok && result.success) {
+            toast.success(action === 'approve' ? 'Expense approved' : 'Expense rejected');
+            applyFilters();
+        } else {
+            toast.error(result.message || `Failed to ${action} expense`);
+        }
+    } catch (error) {
+        console.error(`Error trying to ${action} expense:`, error);
+        toast.error('An error occurred. Please try again.');
+    }
+}
+
 // Apply filters
 function applyFilters() {
     const filters = {
@@ -146,7 +235,13 @@ function applyFilters() {
         endDate: document.getElementById('filterEndDate').value,
         categoryId: document.getElementById('filterCategory').value,
         minAmount: document.getElementById('filterMinAmount').value,
-        maxAmount: document.getElementById('filterMaxAmount').value
+        maxAmount: document.getElementById('filterMaxAmount').value,
+        tags: splitTags(document.getElementById('filterTags').value).join(','),
+        tagMatch: document.getElementById('filterTagMatch').value,
+        status: document.getElementById('filterStatus').value,
+        vendorId: document.getElementById('filterVendor').value,
+        paymentMethod: document.getElementById('filterPaymentMethod').value,
+        reconciliation: document.getElementById('filterReconciliation').value
     };
     
     // Client-side validation
@@ -170,6 +265,12 @@ function clearFilters() {
     document.getElementById('filterCategory').value = '';
     document.getElementById('filterMinAmount').value = '';
     document.getElementById('filterMaxAmount').value = '';
+    document.getElementById('filterTags').value = '';
+    document.getElementById('filterTagMatch').value = 'any';
+    document.getElementById('filterStatus').value = '';
+    document.getElementById('filterVendor').value = '';
+    document.getElementById('filterPaymentMethod').value = '';
+    document.getElementById('filterReconciliation').value = '';
     fetchExpenses();
 }
 
@@ -227,6 +328,144 @@ document.addEventListener('DOMContentLoaded', () => {
     }
 });
 
+// Category suggestions from the admin-managed categorization rules
+document.addEventListener('DOMContentLoaded', () => {
+    const remarksInput = document.getElementById('expenseRemarks');
+    const amountInput = document.getElementById('expenseAmount');
+    if (remarksInput && amountInput) {
+        remarksInput.addEventListener('input', scheduleCategorySuggestion);
+        amountInput.addEventListener('change', scheduleCategorySuggestion);
+    }
+});
+
+let suggestionTimeout;
+
+function scheduleCategorySuggestion() {
+    clearTimeout(suggestionTimeout);
+    suggestionTimeout = setTimeout(suggestCategory, 400);
+}
+
+async function suggestCategory() {
+    const hint = document.getElementById('categorySuggestion');
+    const categorySelect = document.getElementById('expenseCategory');
+    const remarks = document.getElementById('expenseRemarks').value.trim();
+    const amount = parseFloat(document.getElementById('expenseAmount').value) || 0;
+    const currency = document.getElementById('expenseCurrency').value.trim().toUpperCase();
+    const expenseDate = document.getElementById('expenseDate').value;
+    hint.textContent = '';
+    if (!remarks && !amount) return;
+
+    try {
+        const response = await fetch('/api/categorization/suggest', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ remarks: remarks, amount: amount, currency: currency, expense_date: expenseDate })
+        });
+        const result = await response.json();
+        const suggestion = response.ok ? result.data : null;
+        if (!suggestion) return;
+
+        if (!categorySelect.value) {
+            categorySelect.value = suggestion.category_id;
+            categorySelect.dispatchEvent(new Event('change'));
+            hint.textContent = `Suggested: ${suggestion.category_name}`;
+        } else if (parseInt(categorySelect.value) !== suggestion.category_id) {
+            hint.textContent = `Usually filed under ${suggestion.category_name}`;
+        }
+    } catch (error) {
+        console.error('Error fetching category suggestion:', error);
+    }
+}
+
+// Tag autocomplete: suggest existing tags for the last comma-separated entry
+document.addEventListener('DOMContentLoaded', () => {
+    ['expenseTags', 'filterTags'].forEach(id => {
+        const input = document.getElementById(id);
+        if (input) input.addEventListener('input', () => scheduleTagSuggestions(input));
+    });
+});
+
+// Vendors: the filter lists every vendor; the form suggests them by name
+document.addEventListener('DOMContentLoaded', loadVendors);
+
+async function loadVendors() {
+    const filter = document.getElementById('filterVendor');
+    const options = document.getElementById('vendorOptions');
+    if (!filter && !options) return;
+
+    try {
+        const response = await fetch('/api/vendors');
+        const result = await response.json();
+        if (!response.ok || !result.data) return;
+
+        if (filter) {
+            filter.innerHTML = '<option value="">All Vendors</option>' + result.data
+                .map(v => `<option value="${v.id}">${escapeHtml(v.name)}</option>`)
+                .join('');
+        }
+        if (options) {
+            options.innerHTML = result.data
+                .map(v => `<option value="${escapeHtml(v.name)}"></option>`)
+                .join('');
+        }
+    } catch (error) {
+        console.error('Error fetching vendors:', error);
+    }
+}
+
+// Tax rates: the form offers the active ones
+document.addEventListener('DOMContentLoaded', loadTaxRates);
+
+async function loadTaxRates() {
+    const select = document.getElementById('expenseTaxRate');
+    if (!select) return;
+
+    try {
+        const response = await fetch('/api/tax-rates?active=true');
+        const result = await response.json();
+        if (!response.ok || !result.data) return;
+
+        select.innerHTML = '<option value="">No tax</option>' + result.data
+            .map(t => `<option value="${t.id}">${escapeHtml(t.name)} (${t.rate.toFixed(2)}%)</option>`)
+            .join('');
+    } catch (error) {
+        console.error('Error fetching tax rates:', error);
+    }
+}
+
+let tagTimeout;
+
+function splitTags(value) {
+    return (value || '').split(',').map(t => t.trim()).filter(t => t);
+}
+
+function scheduleTagSuggestions(input) {
+    clearTimeout(tagTimeout);
+    tagTimeout = setTimeout(() => suggestTags(input), 250);
+}
+
+async function suggestTags(input) {
+    const options = document.getElementById('tagOptions');
+    const parts = input.value.split(',');
+    const prefix = parts.pop().trim();
+    const head = parts.map(t => t.trim()).filter(t => t);
+    options.innerHTML = '';
+    if (!prefix) return;
+
+    try {
+        const response = await fetch(`/api/tags?q=${encodeURIComponent(prefix)}`);
+        const result = await response.json();
+        if (!response.ok || !result.data) return;
+
+        options.innerHTML = result.data
+            .filter(tag => !head.includes(tag.name))
+            .map(tag => `<option value="${escapeHtml(head.concat(tag.name).join(', '))}">${tag.usage_count} expenses</option>`)
+            .join('');
+    } catch (error) {
+        console.error('Error fetching tags:', error);
+    }
+}
+
 let budgetFetchTimeout;
 
 async function checkBudgetStatus() {
@@ -248,10 +487,8 @@ async function checkBudgetStatus() {
     if (budgetFetchTimeout) clearTimeout(budgetFetchTimeout);
 
     budgetFetchTimeout = setTimeout(async () => {
-        const year = new Date(dateVal).getFullYear();
-
         try {
-            const response = await fetch(`/api/budgets/status?category_id=${categoryId}&year=${year}`);
+            const response = await fetch(`/api/budgets/status?category_id=${categoryId}&date=${dateVal}`);
             const result = await response.json();
             
             if (response.ok && result.success) {